@@ -13,6 +13,11 @@
 // @host      localhost:8080
 // @BasePath  /
 
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 OIDC bearer token, required when authentication is enabled
+
 // @externalDocs.description  OpenAPI
 // @externalDocs.url          https://swagger.io/resources/open-api/
 
@@ -31,10 +36,15 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 
+	"k8s-monitor/internal/alerting"
 	"k8s-monitor/internal/config"
 	"k8s-monitor/internal/handlers"
+	"k8s-monitor/internal/history"
+	"k8s-monitor/internal/incidents"
+	"k8s-monitor/internal/metrics"
 	"k8s-monitor/internal/middleware"
 	"k8s-monitor/internal/services"
+	"k8s-monitor/internal/storage"
 	"k8s-monitor/pkg/utils"
 )
 
@@ -49,14 +59,59 @@ func main() {
 		logger.WithError(err).Fatal("Failed to load configuration")
 	}
 
-	// Initialize Kubernetes service
-	k8sService, err := services.NewKubernetesService(cfg.Kubernetes)
+	// Self-instrumentation of the HTTP server and the Kubernetes clients
+	httpMetrics := metrics.NewHTTPMetrics()
+	clientMetrics := metrics.NewKubernetesClientMetrics()
+
+	// Validate how pods, services and workloads are grouped into applications
+	grouper, err := services.NewGrouper(cfg.Grouping)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid grouping configuration")
+	}
+
+	// Initialize the cluster registry with Kubernetes and application services per cluster
+	clusterManager, err := services.NewClusterManager(cfg.Kubernetes, grouper, clientMetrics.WrapTransport, logger)
 	if err != nil {
-		logger.WithError(err).Fatal("Failed to initialize Kubernetes service")
+		logger.WithError(err).Fatal("Failed to initialize Kubernetes clusters")
 	}
 
-	// Initialize application service
-	appService := services.NewApplicationService(k8sService, logger)
+	// Start watching applications for change events
+	appWatcher := services.NewApplicationWatcher(clusterManager, cfg.Stream, logger)
+	appWatcher.Start()
+
+	// Evaluate alert rules against the watcher's observations
+	var alertEngine *alerting.Engine
+	if cfg.Alerting.Enabled {
+		alertEngine, err = alerting.NewEngine(cfg.Alerting, appWatcher, appWatcher.StaleAfter(), logger)
+		if err != nil {
+			logger.WithError(err).Fatal("Invalid alerting configuration")
+		}
+		alertEngine.Start()
+	}
+
+	// Open the store for incidents, history and settings
+	store, err := storage.Open(cfg.Storage, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to open storage")
+	}
+	retention := storage.NewRetention(store, cfg.Storage.Retention, logger)
+	retention.Start()
+
+	// Record application health history
+	historyService, err := history.NewService(store, cfg.History)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid history configuration")
+	}
+	var historyRecorder *history.Recorder
+	if cfg.History.Enabled {
+		historyRecorder = history.NewRecorder(appWatcher, store, time.Duration(cfg.History.Interval)*time.Second, logger)
+		historyRecorder.Start()
+	}
+
+	// Track incidents from application status transitions
+	incidentTracker := incidents.NewTracker(appWatcher, store, incidents.ClusterEvents(clusterManager),
+		time.Duration(cfg.Stream.PollInterval)*time.Second, logger)
+	incidentTracker.Start()
 
 	// Setup Gin router
 	if cfg.Server.Mode == "production" {
@@ -68,6 +123,8 @@ func main() {
 	// Add middleware
 	router.Use(gin.Recovery())
 	router.Use(middleware.Logger(logger))
+	router.Use(middleware.Metrics(httpMetrics))
+	router.Use(middleware.Staleness())
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     cfg.CORS.AllowedOrigins,
 		AllowMethods:     cfg.CORS.AllowedMethods,
@@ -78,14 +135,48 @@ func main() {
 	}))
 
 	// Initialize handlers
-	healthHandler := handlers.NewHealthHandler(k8sService, logger)
-	podHandler := handlers.NewPodHandler(k8sService, logger)
-	appHandler := handlers.NewApplicationHandler(appService, logger)
+	healthHandler := handlers.NewHealthHandler(clusterManager, logger)
+	clusterHandler := handlers.NewClusterHandler(clusterManager, logger)
+	podHandler := handlers.NewPodHandler(clusterManager, logger)
+	nodeHandler := handlers.NewNodeHandler(clusterManager, logger)
+	appHandler := handlers.NewApplicationHandler(clusterManager, logger)
 	docsHandler := handlers.NewDocsHandler()
-	argoCDHandler := handlers.NewArgoCDHandler(k8sService, logger)
+	argoCDHandler := handlers.NewArgoCDHandler(clusterManager, logger)
+	streamHandler := handlers.NewStreamHandler(clusterManager, appWatcher,
+		time.Duration(cfg.Stream.HeartbeatInterval)*time.Second, logger)
+	alertHandler := handlers.NewAlertHandler(alertEngine, logger)
+	incidentHandler := handlers.NewIncidentHandler(incidentTracker, logger)
+	settingsHandler := handlers.NewSettingsHandler(store, logger)
+	historyHandler := handlers.NewHistoryHandler(historyService, clusterManager, logger)
+
+	// Expensive lists are cached briefly, and identical requests in flight build one response
+	cached := func(c *gin.Context) { c.Next() }
+	if cfg.Cache.Enabled {
+		cached = middleware.ResponseCache(time.Duration(cfg.Cache.TTL)*time.Second, logger)
+	}
+
+	// API routes require a bearer token when authentication is enabled
+	authenticated := func(c *gin.Context) { c.Next() }
+	if cfg.Auth.Enabled {
+		authenticated, err = middleware.Auth(cfg.Auth, logger)
+		if err != nil {
+			logger.WithError(err).Fatal("Invalid authentication configuration")
+		}
+		logger.WithField("issuer", cfg.Auth.Issuer).Info("API authentication enabled")
+	}
 
 	// Setup routes
-	setupRoutes(router, healthHandler, podHandler, appHandler, docsHandler, argoCDHandler)
+	setupRoutes(router, cached, authenticated, healthHandler, clusterHandler, podHandler, nodeHandler, appHandler, docsHandler, argoCDHandler, streamHandler, alertHandler, incidentHandler, settingsHandler, historyHandler)
+
+	// Expose Prometheus metrics; application metrics are computed from the watcher's last observation
+	if cfg.Metrics.Enabled {
+		registry := metrics.NewRegistry(httpMetrics, clientMetrics, metrics.NewApplicationCollector(appWatcher, appWatcher.StaleAfter()))
+		public := cfg.Auth.Enabled && cfg.Metrics.Public
+		if public {
+			logger.WithField("path", cfg.Metrics.Path).Warn("Metrics are served without authentication")
+		}
+		setupMetricsRoute(router, cfg.Metrics.Path, public, authenticated, metrics.Handler(registry))
+	}
 
 	// Create HTTP server
 	server := &http.Server{
@@ -120,16 +211,52 @@ func main() {
 	} else {
 		logger.Info("Server shutdown complete")
 	}
+
+	// Stop background watchers and Kubernetes informers
+	if alertEngine != nil {
+		alertEngine.Stop()
+	}
+	incidentTracker.Stop()
+	if historyRecorder != nil {
+		historyRecorder.Stop()
+	}
+	appWatcher.Stop()
+	clusterManager.Stop()
+
+	retention.Stop()
+	if err := store.Close(); err != nil {
+		logger.WithError(err).Error("Failed to close storage")
+	}
 }
 
-// setupRoutes configures all API routes
+// setupMetricsRoute serves metrics at path. Metrics name every cluster, namespace and
+// application, so they go through the authenticated middleware unless made public.
+func setupMetricsRoute(router *gin.Engine, path string, public bool, authenticated gin.HandlerFunc, handler http.Handler) {
+	if public {
+		router.GET(path, gin.WrapH(handler))
+		return
+	}
+	router.GET(path, authenticated, gin.WrapH(handler))
+}
+
+// setupRoutes configures all API routes. API v1 routes go through the authenticated
+// middleware, and list endpoints through the cached middleware.
 func setupRoutes(
 	router *gin.Engine,
+	cached gin.HandlerFunc,
+	authenticated gin.HandlerFunc,
 	healthHandler *handlers.HealthHandler,
+	clusterHandler *handlers.ClusterHandler,
 	podHandler *handlers.PodHandler,
+	nodeHandler *handlers.NodeHandler,
 	appHandler *handlers.ApplicationHandler,
 	docsHandler *handlers.DocsHandler,
 	argoCDHandler *handlers.ArgoCDHandler,
+	streamHandler *handlers.StreamHandler,
+	alertHandler *handlers.AlertHandler,
+	incidentHandler *handlers.IncidentHandler,
+	settingsHandler *handlers.SettingsHandler,
+	historyHandler *handlers.HistoryHandler,
 ) {
 	// Health check endpoint
 	router.GET("/health", healthHandler.Check)
@@ -142,24 +269,49 @@ func setupRoutes(
 	router.GET("/redoc", docsHandler.RedocUI)
 
 	// API v1 routes
-	v1 := router.Group("/api/v1")
+	v1 := router.Group("/api/v1", authenticated)
 	{
+		// Cluster endpoints
+		v1.GET("/clusters", clusterHandler.List)
+
 		// Pod endpoints
-		v1.GET("/pods", podHandler.List)
-		v1.GET("/pods/:namespace", podHandler.ListByNamespace)
+		v1.GET("/pods", cached, podHandler.List)
+		v1.GET("/pods/:namespace", cached, podHandler.ListByNamespace)
+		v1.GET("/pods/:namespace/:name/logs", podHandler.GetLogs)
+		v1.GET("/pods/:namespace/:name/events", podHandler.GetEvents)
 
 		// Application endpoints
-		v1.GET("/applications", appHandler.List)
-		v1.GET("/applications/:namespace", appHandler.ListByNamespace)
+		v1.GET("/applications", cached, appHandler.List)
+		v1.GET("/applications/stream", streamHandler.Applications)
+		v1.GET("/applications/:namespace", cached, appHandler.ListByNamespace)
 		v1.GET("/applications/:namespace/:name", appHandler.GetApplication)
 		v1.GET("/applications/:namespace/:name/status", appHandler.GetApplicationStatus)
+		v1.GET("/applications/:namespace/:name/logs", appHandler.GetLogs)
+		v1.GET("/applications/:namespace/:name/events", appHandler.GetEvents)
+		v1.GET("/applications/:namespace/:name/history", historyHandler.GetHistory)
 
 		// Namespace endpoints
-		v1.GET("/namespaces", podHandler.ListNamespaces)
+		v1.GET("/namespaces", cached, podHandler.ListNamespaces)
+
+		// Node endpoints
+		v1.GET("/nodes", cached, nodeHandler.List)
 
 		// ArgoCD endpoints
-		v1.GET("/argocd/applications", argoCDHandler.List)
-		v1.GET("/argocd/applications/:namespace", argoCDHandler.ListByNamespace)
+		v1.GET("/argocd/applications", cached, argoCDHandler.List)
+		v1.GET("/argocd/applications/:namespace", cached, argoCDHandler.ListByNamespace)
 		v1.GET("/argocd/applications/:namespace/:name", argoCDHandler.GetApplication)
+
+		// Alert endpoints
+		v1.GET("/alerts", alertHandler.List)
+
+		// Incident endpoints
+		v1.GET("/incidents", incidentHandler.List)
+		v1.GET("/incidents/:id", incidentHandler.GetIncident)
+
+		// Settings endpoints
+		v1.GET("/settings", settingsHandler.List)
+		v1.GET("/settings/:key", settingsHandler.Get)
+		v1.PUT("/settings/:key", settingsHandler.Put)
+		v1.DELETE("/settings/:key", settingsHandler.Delete)
 	}
 }
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSetupMetricsRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deny := func(c *gin.Context) { c.AbortWithStatus(http.StatusUnauthorized) }
+	metrics := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	tests := []struct {
+		name   string
+		public bool
+		want   int
+	}{
+		{name: "authenticated by default", want: http.StatusUnauthorized},
+		{name: "public", public: true, want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			setupMetricsRoute(router, "/metrics", tt.public, deny, metrics)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+			if recorder.Code != tt.want {
+				t.Errorf("GET /metrics = %d, want %d", recorder.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,430 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+	"k8s-monitor/pkg/utils"
+)
+
+const (
+	// deliveryQueueSize bounds notifications waiting to be sent
+	deliveryQueueSize = 256
+	// retryBackoff is the delay before the first retry, doubled on every attempt
+	retryBackoff = time.Second
+)
+
+// Source provides the last observed applications of every cluster, and when each cluster
+// was last observed successfully
+type Source interface {
+	Applications() map[string][]models.Application
+	ArgoCDApplications() map[string][]models.ArgoCDApplication
+	ObservedAt() map[string]time.Time
+}
+
+// receiver is a configured notification target
+type receiver struct {
+	notifier   Notifier
+	maxRetries int
+}
+
+// alertState tracks one alert between evaluations
+type alertState struct {
+	alert        models.Alert
+	rule         *rule
+	activeSince  time.Time
+	firing       bool
+	lastNotified time.Time
+}
+
+// restartSample is one observation of an application's restart count
+type restartSample struct {
+	at    time.Time
+	count int
+}
+
+// delivery is a notification waiting to be sent
+type delivery struct {
+	alert     models.Alert
+	receivers []string
+}
+
+// Engine periodically evaluates alert rules against the observed applications and sends
+// firing and resolved notifications. An alert is sent once when it starts firing, again
+// after the repeat interval while it keeps firing, and once when it resolves.
+type Engine struct {
+	source         Source
+	rules          []*rule
+	receivers      map[string]receiver
+	receiverNames  []string
+	interval       time.Duration
+	staleAfter     time.Duration
+	repeatInterval time.Duration
+	sendResolved   bool
+	logger         *logrus.Entry
+
+	mu       sync.Mutex
+	states   map[string]*alertState
+	restarts map[string][]restartSample // cluster/namespace/name -> samples within the longest window
+
+	queue  chan delivery
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewEngine validates the alerting configuration and creates an engine over the source.
+// Clusters not observed within staleAfter are unreachable.
+func NewEngine(cfg config.AlertingConfig, source Source, staleAfter time.Duration, logger *logrus.Logger) (*Engine, error) {
+	e := &Engine{
+		source:         source,
+		receivers:      make(map[string]receiver, len(cfg.Receivers)),
+		interval:       time.Duration(cfg.Interval) * time.Second,
+		staleAfter:     staleAfter,
+		repeatInterval: time.Duration(cfg.RepeatInterval) * time.Second,
+		sendResolved:   cfg.SendResolved,
+		logger:         utils.WithComponent(logger, "alerting"),
+		states:         make(map[string]*alertState),
+		restarts:       make(map[string][]restartSample),
+		queue:          make(chan delivery, deliveryQueueSize),
+	}
+	if e.interval <= 0 {
+		e.interval = 30 * time.Second
+	}
+
+	for _, receiverCfg := range cfg.Receivers {
+		notifier, err := newNotifier(receiverCfg)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := e.receivers[receiverCfg.Name]; exists {
+			return nil, fmt.Errorf("duplicate alert receiver %q", receiverCfg.Name)
+		}
+		maxRetries := receiverCfg.MaxRetries
+		if maxRetries <= 0 {
+			maxRetries = defaultMaxRetries
+		}
+		e.receivers[receiverCfg.Name] = receiver{notifier: notifier, maxRetries: maxRetries}
+		e.receiverNames = append(e.receiverNames, receiverCfg.Name)
+	}
+
+	ruleConfigs := cfg.Rules
+	if len(ruleConfigs) == 0 {
+		ruleConfigs = DefaultRules
+	}
+	names := make(map[string]bool, len(ruleConfigs))
+	for _, ruleCfg := range ruleConfigs {
+		r, err := newRule(ruleCfg)
+		if err != nil {
+			return nil, err
+		}
+		if names[r.name] {
+			return nil, fmt.Errorf("duplicate alert rule %q", r.name)
+		}
+		names[r.name] = true
+		for _, name := range r.receivers {
+			if _, ok := e.receivers[name]; !ok {
+				return nil, fmt.Errorf("alert rule %q: unknown receiver %q", r.name, name)
+			}
+		}
+		e.rules = append(e.rules, r)
+	}
+
+	return e, nil
+}
+
+// Start begins evaluating rules and delivering notifications in the background
+func (e *Engine) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+	e.done = make(chan struct{})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		e.deliver(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				e.Evaluate(now)
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(e.done)
+	}()
+
+	e.logger.WithFields(logrus.Fields{
+		"rules":     len(e.rules),
+		"receivers": len(e.receivers),
+	}).Info("Alerting started")
+}
+
+// Stop ends evaluation and delivery
+func (e *Engine) Stop() {
+	if e.cancel == nil {
+		return
+	}
+	e.cancel()
+	<-e.done
+}
+
+// Evaluate checks every rule against the source's last observation and queues the
+// resulting notifications. The last observation of an unreachable cluster is not
+// evaluated: its alerts neither repeat nor resolve until the cluster is observed again.
+func (e *Engine) Evaluate(now time.Time) {
+	applications := e.source.Applications()
+	argoApplications := e.source.ArgoCDApplications()
+
+	unreachable := make(map[string]time.Time)
+	for cluster, observedAt := range e.source.ObservedAt() {
+		if now.Sub(observedAt) > e.staleAfter {
+			unreachable[cluster] = observedAt
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.recordRestarts(now, applications)
+
+	// Collect the alerts whose condition currently holds
+	active := make(map[string]models.Alert)
+	activeRules := make(map[string]*rule)
+	for _, r := range e.rules {
+		for cluster, observedAt := range unreachable {
+			if r.ruleType != RuleClusterUnreachable || !r.clusterInScope(cluster) {
+				continue
+			}
+			message := fmt.Sprintf("Cluster %s has not been observed since %s", cluster, observedAt.UTC().Format(time.RFC3339))
+			alert := newAlert(r, models.AlertKindCluster, cluster, "", cluster, message)
+			active[alert.Fingerprint] = alert
+			activeRules[alert.Fingerprint] = r
+		}
+		for cluster, apps := range applications {
+			if _, ok := unreachable[cluster]; ok {
+				continue
+			}
+			for _, app := range apps {
+				if !r.inScope(cluster, app.Namespace) {
+					continue
+				}
+				increase := 0
+				if r.ruleType == RuleRestarts {
+					increase = e.restartIncreaseLocked(appKey(cluster, app.Namespace, app.Name), r.window, now)
+				}
+				message, ok := r.evaluateApplication(app, increase)
+				if !ok {
+					continue
+				}
+				alert := newAlert(r, models.AlertKindApplication, cluster, app.Namespace, app.Name, message)
+				active[alert.Fingerprint] = alert
+				activeRules[alert.Fingerprint] = r
+			}
+		}
+		for cluster, apps := range argoApplications {
+			if _, ok := unreachable[cluster]; ok {
+				continue
+			}
+			for _, app := range apps {
+				if !r.inScope(cluster, app.Namespace) {
+					continue
+				}
+				message, ok := r.evaluateArgoCD(app)
+				if !ok {
+					continue
+				}
+				alert := newAlert(r, models.AlertKindArgoCD, cluster, app.Namespace, app.Name, message)
+				active[alert.Fingerprint] = alert
+				activeRules[alert.Fingerprint] = r
+			}
+		}
+	}
+
+	for fingerprint, alert := range active {
+		state, exists := e.states[fingerprint]
+		if !exists {
+			state = &alertState{rule: activeRules[fingerprint], activeSince: now}
+			e.states[fingerprint] = state
+		}
+		alert.StartsAt = state.activeSince
+		state.alert = alert
+
+		switch {
+		case !state.firing && now.Sub(state.activeSince) >= state.rule.forDuration:
+			state.firing = true
+			state.lastNotified = now
+			e.enqueueLocked(state)
+		case state.firing && e.repeatInterval > 0 && now.Sub(state.lastNotified) >= e.repeatInterval:
+			state.lastNotified = now
+			e.enqueueLocked(state)
+		}
+	}
+
+	for fingerprint, state := range e.states {
+		if _, stillActive := active[fingerprint]; stillActive {
+			continue
+		}
+		if _, ok := unreachable[state.alert.Cluster]; ok && state.alert.Kind != models.AlertKindCluster {
+			continue
+		}
+		delete(e.states, fingerprint)
+		if state.firing && e.sendResolved {
+			endsAt := now
+			state.alert.Status = models.AlertResolved
+			state.alert.EndsAt = &endsAt
+			e.enqueueLocked(state)
+		}
+	}
+}
+
+// Active returns the alerts that are currently firing
+func (e *Engine) Active() []models.Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	alerts := make([]models.Alert, 0, len(e.states))
+	for _, state := range e.states {
+		if state.firing {
+			alerts = append(alerts, state.alert)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if !alerts[i].StartsAt.Equal(alerts[j].StartsAt) {
+			return alerts[i].StartsAt.After(alerts[j].StartsAt)
+		}
+		return alerts[i].Fingerprint < alerts[j].Fingerprint
+	})
+	return alerts
+}
+
+// recordRestarts stores the current restart counts, keeping samples within the longest
+// restarts rule window
+func (e *Engine) recordRestarts(now time.Time, applications map[string][]models.Application) {
+	var window time.Duration
+	for _, r := range e.rules {
+		if r.ruleType == RuleRestarts && r.window > window {
+			window = r.window
+		}
+	}
+	if window == 0 {
+		return
+	}
+
+	seen := make(map[string]bool)
+	for cluster, apps := range applications {
+		for _, app := range apps {
+			key := appKey(cluster, app.Namespace, app.Name)
+			seen[key] = true
+
+			samples := append(e.restarts[key], restartSample{at: now, count: app.Summary.RestartCount})
+			for len(samples) > 1 && now.Sub(samples[0].at) > window {
+				samples = samples[1:]
+			}
+			e.restarts[key] = samples
+		}
+	}
+	for key := range e.restarts {
+		if !seen[key] {
+			delete(e.restarts, key)
+		}
+	}
+}
+
+// restartIncreaseLocked returns how much an application's restart count grew within the window
+func (e *Engine) restartIncreaseLocked(key string, window time.Duration, now time.Time) int {
+	samples := e.restarts[key]
+	if len(samples) == 0 {
+		return 0
+	}
+	current := samples[len(samples)-1].count
+	for _, sample := range samples {
+		if now.Sub(sample.at) <= window {
+			// Restart counts drop when pods are replaced; never report a negative increase
+			return max(current-sample.count, 0)
+		}
+	}
+	return 0
+}
+
+// enqueueLocked queues a notification of the alert's current state without blocking evaluation
+func (e *Engine) enqueueLocked(state *alertState) {
+	receivers := state.rule.receivers
+	if len(receivers) == 0 {
+		receivers = e.receiverNames
+	}
+	if len(receivers) == 0 {
+		return
+	}
+
+	select {
+	case e.queue <- delivery{alert: state.alert, receivers: receivers}:
+	default:
+		e.logger.WithField("alert", state.alert.Fingerprint).Warn("Alert delivery queue is full, dropping notification")
+	}
+}
+
+// deliver sends queued notifications until the context is cancelled
+func (e *Engine) deliver(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d := <-e.queue:
+			for _, name := range d.receivers {
+				r := e.receivers[name]
+				logger := e.logger.WithFields(logrus.Fields{
+					"receiver": name,
+					"rule":     d.alert.Rule,
+					"status":   d.alert.Status,
+					"target":   d.alert.Namespace + "/" + d.alert.Name,
+				})
+				if err := notifyWithRetry(ctx, r.notifier, d.alert, r.maxRetries, retryBackoff); err != nil {
+					logger.WithError(err).Error("Failed to send alert notification")
+					continue
+				}
+				logger.Info("Sent alert notification")
+			}
+		}
+	}
+}
+
+// newAlert creates a firing alert for a rule and target
+func newAlert(r *rule, kind, cluster, namespace, name, message string) models.Alert {
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%s\x00%s\x00%s\x00%s\x00%s", r.name, kind, cluster, namespace, name)
+
+	return models.Alert{
+		Fingerprint: fmt.Sprintf("%016x", hash.Sum64()),
+		Rule:        r.name,
+		Severity:    r.severity,
+		Status:      models.AlertFiring,
+		Kind:        kind,
+		Cluster:     cluster,
+		Namespace:   namespace,
+		Name:        name,
+		Message:     message,
+	}
+}
+
+func appKey(cluster, namespace, name string) string {
+	return cluster + "/" + namespace + "/" + name
+}
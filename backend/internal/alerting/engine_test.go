@@ -0,0 +1,246 @@
+package alerting
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+)
+
+// staticSource serves a mutable observation
+type staticSource struct {
+	applications map[string][]models.Application
+	argo         map[string][]models.ArgoCDApplication
+	observedAt   map[string]time.Time
+}
+
+func (s *staticSource) Applications() map[string][]models.Application { return s.applications }
+
+func (s *staticSource) ArgoCDApplications() map[string][]models.ArgoCDApplication { return s.argo }
+
+func (s *staticSource) ObservedAt() map[string]time.Time { return s.observedAt }
+
+func newTestEngine(t *testing.T, source Source, rules ...config.AlertRuleConfig) *Engine {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	engine, err := NewEngine(config.AlertingConfig{
+		RepeatInterval: 3600,
+		SendResolved:   true,
+		Rules:          rules,
+		Receivers:      []config.AlertReceiverConfig{{Name: "ops", Type: ReceiverWebhook, URL: "http://example.invalid"}},
+	}, source, 2*time.Minute, logger)
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+	return engine
+}
+
+// drain returns the queued notifications
+func drain(e *Engine) []models.Alert {
+	var alerts []models.Alert
+	for {
+		select {
+		case d := <-e.queue:
+			alerts = append(alerts, d.alert)
+		default:
+			return alerts
+		}
+	}
+}
+
+func application(name, status string, restarts int) models.Application {
+	return models.Application{
+		Name:      name,
+		Namespace: "shop",
+		Status:    status,
+		Summary:   models.ApplicationSummary{TotalPods: 2, ReadyPods: 1, RestartCount: restarts},
+	}
+}
+
+func TestEngineStatusRule(t *testing.T) {
+	source := &staticSource{applications: map[string][]models.Application{
+		"prod": {application("api", "unhealthy", 0), application("web", "healthy", 0)},
+	}}
+	engine := newTestEngine(t, source, config.AlertRuleConfig{
+		Name: "unhealthy", Type: RuleStatus, Statuses: []string{"unhealthy"}, For: 60, Severity: "critical",
+	})
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	engine.Evaluate(start)
+	if alerts := drain(engine); len(alerts) != 0 {
+		t.Fatalf("alert fired before the for duration: %+v", alerts)
+	}
+
+	engine.Evaluate(start.Add(time.Minute))
+	alerts := drain(engine)
+	if len(alerts) != 1 || alerts[0].Status != models.AlertFiring || alerts[0].Name != "api" || !alerts[0].StartsAt.Equal(start) {
+		t.Fatalf("unexpected firing notifications %+v", alerts)
+	}
+	if active := engine.Active(); len(active) != 1 || active[0].Severity != "critical" {
+		t.Errorf("Active() = %+v", active)
+	}
+
+	// Still firing: deduplicated until the repeat interval
+	engine.Evaluate(start.Add(2 * time.Minute))
+	if alerts := drain(engine); len(alerts) != 0 {
+		t.Errorf("duplicate notification %+v", alerts)
+	}
+	engine.Evaluate(start.Add(time.Minute + time.Hour))
+	if alerts := drain(engine); len(alerts) != 1 {
+		t.Errorf("got %d repeated notifications, want 1", len(alerts))
+	}
+
+	source.applications["prod"][0].Status = "healthy"
+	engine.Evaluate(start.Add(2 * time.Hour))
+	alerts = drain(engine)
+	if len(alerts) != 1 || alerts[0].Status != models.AlertResolved || alerts[0].EndsAt == nil {
+		t.Fatalf("unexpected resolve notifications %+v", alerts)
+	}
+	if active := engine.Active(); len(active) != 0 {
+		t.Errorf("resolved alert still active: %+v", active)
+	}
+}
+
+func TestEngineRestartsAndArgoCDRules(t *testing.T) {
+	source := &staticSource{
+		applications: map[string][]models.Application{"prod": {application("api", "healthy", 10)}},
+		argo: map[string][]models.ArgoCDApplication{"prod": {
+			{Name: "shop", Namespace: "argocd", SyncStatus: "OutOfSync", HealthStatus: "Healthy"},
+			{Name: "billing", Namespace: "argocd", SyncStatus: "Synced", HealthStatus: "Healthy"},
+		}},
+	}
+	engine := newTestEngine(t, source,
+		config.AlertRuleConfig{Name: "restarts", Type: RuleRestarts, Threshold: 3, Window: 600},
+		config.AlertRuleConfig{Name: "out-of-sync", Type: RuleArgoCDOutOfSync, For: 600, Namespaces: []string{"argocd"}},
+		config.AlertRuleConfig{Name: "excluded", Type: RuleArgoCDOutOfSync, ExcludeNamespaces: []string{"argocd"}},
+	)
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	engine.Evaluate(start)
+
+	// Existing restarts do not count, only increases within the window
+	source.applications["prod"][0].Summary.RestartCount = 14
+	engine.Evaluate(start.Add(5 * time.Minute))
+	alerts := drain(engine)
+	if len(alerts) != 1 || alerts[0].Rule != "restarts" {
+		t.Fatalf("unexpected notifications %+v", alerts)
+	}
+
+	engine.Evaluate(start.Add(10 * time.Minute))
+	alerts = drain(engine)
+	if len(alerts) != 1 || alerts[0].Rule != "out-of-sync" || alerts[0].Name != "shop" || alerts[0].Kind != models.AlertKindArgoCD {
+		t.Fatalf("unexpected notifications %+v", alerts)
+	}
+
+	// The restart burst leaves the window
+	engine.Evaluate(start.Add(16 * time.Minute))
+	alerts = drain(engine)
+	if len(alerts) != 1 || alerts[0].Rule != "restarts" || alerts[0].Status != models.AlertResolved {
+		t.Fatalf("unexpected notifications %+v", alerts)
+	}
+}
+
+func TestEngineUnreachableCluster(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	source := &staticSource{
+		applications: map[string][]models.Application{"prod": {application("api", "unhealthy", 0)}},
+		observedAt:   map[string]time.Time{"prod": start},
+	}
+	engine := newTestEngine(t, source,
+		config.AlertRuleConfig{Name: "unhealthy", Type: RuleStatus, Statuses: []string{"unhealthy"}},
+		config.AlertRuleConfig{Name: "unreachable", Type: RuleClusterUnreachable, Severity: "critical"},
+	)
+
+	engine.Evaluate(start)
+	if alerts := drain(engine); len(alerts) != 1 || alerts[0].Rule != "unhealthy" {
+		t.Fatalf("unexpected notifications %+v", alerts)
+	}
+
+	// Polls fail: the frozen observation neither repeats nor resolves the application alert
+	source.applications["prod"][0].Status = "healthy"
+	engine.Evaluate(start.Add(2 * time.Hour))
+	alerts := drain(engine)
+	if len(alerts) != 1 || alerts[0].Rule != "unreachable" || alerts[0].Kind != models.AlertKindCluster || alerts[0].Name != "prod" {
+		t.Fatalf("unexpected notifications %+v", alerts)
+	}
+	if active := engine.Active(); len(active) != 2 {
+		t.Errorf("got %d active alerts, want 2", len(active))
+	}
+
+	// The cluster is observed again
+	source.observedAt["prod"] = start.Add(2*time.Hour + time.Minute)
+	engine.Evaluate(start.Add(2*time.Hour + time.Minute))
+	alerts = drain(engine)
+	if len(alerts) != 2 || alerts[0].Status != models.AlertResolved || alerts[1].Status != models.AlertResolved {
+		t.Fatalf("unexpected notifications %+v", alerts)
+	}
+}
+
+func TestNewEngineValidation(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	tests := []config.AlertingConfig{
+		{Rules: []config.AlertRuleConfig{{Name: "x", Type: "bogus"}}},
+		{Rules: []config.AlertRuleConfig{{Name: "x", Type: RuleStatus}}},
+		{Rules: []config.AlertRuleConfig{{Name: "x", Type: RuleRestarts, Threshold: 3}}},
+		{Rules: []config.AlertRuleConfig{{Name: "x", Type: RuleArgoCDOutOfSync, Receivers: []string{"missing"}}}},
+		{Receivers: []config.AlertReceiverConfig{{Name: "x", Type: "email", URL: "http://example.invalid"}}},
+	}
+	for i, cfg := range tests {
+		if _, err := NewEngine(cfg, &staticSource{}, time.Minute, logger); err == nil {
+			t.Errorf("case %d: expected a validation error", i)
+		}
+	}
+}
+
+func TestNotifyWithRetry(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	notifier, err := newNotifier(config.AlertReceiverConfig{Name: "slack", Type: ReceiverSlack, URL: server.URL})
+	if err != nil {
+		t.Fatalf("newNotifier() error = %v", err)
+	}
+
+	alert := models.Alert{Rule: "unhealthy", Status: models.AlertFiring, Message: "down"}
+	if err := notifyWithRetry(context.Background(), notifier, alert, 3, time.Millisecond); err != nil {
+		t.Fatalf("notifyWithRetry() error = %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("got %d calls, want 2", calls.Load())
+	}
+
+	// Client errors are not retried
+	calls.Store(0)
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer rejecting.Close()
+
+	notifier.url = rejecting.URL
+	if err := notifyWithRetry(context.Background(), notifier, alert, 3, time.Millisecond); err == nil || calls.Load() != 1 {
+		t.Errorf("got %v after %d calls, want a permanent error after 1 call", err, calls.Load())
+	}
+}
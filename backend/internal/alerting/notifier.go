@@ -0,0 +1,175 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+)
+
+// Receiver types
+const (
+	ReceiverWebhook = "webhook"
+	ReceiverSlack   = "slack"
+)
+
+const (
+	defaultReceiverTimeout = 10 * time.Second
+	defaultMaxRetries      = 3
+)
+
+// Notifier delivers alerts to one receiver
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, alert models.Alert) error
+}
+
+// permanentError marks delivery failures that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// WebhookPayload is the JSON body posted to generic webhooks
+type WebhookPayload struct {
+	Version string       `json:"version"`
+	Alert   models.Alert `json:"alert"`
+}
+
+// httpNotifier posts alerts as JSON to a webhook URL
+type httpNotifier struct {
+	name    string
+	url     string
+	headers map[string]string
+	client  *http.Client
+	encode  func(alert models.Alert) ([]byte, error)
+}
+
+// newNotifier creates the notifier for a receiver configuration
+func newNotifier(cfg config.AlertReceiverConfig) (*httpNotifier, error) {
+	if cfg.Name == "" || cfg.URL == "" {
+		return nil, fmt.Errorf("alert receivers need a name and a URL")
+	}
+
+	timeout := time.Duration(cfg.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = defaultReceiverTimeout
+	}
+
+	n := &httpNotifier{
+		name:    cfg.Name,
+		url:     cfg.URL,
+		headers: cfg.Headers,
+		client:  &http.Client{Timeout: timeout},
+	}
+
+	switch cfg.Type {
+	case ReceiverWebhook, "":
+		n.encode = encodeWebhook
+	case ReceiverSlack:
+		n.encode = encodeSlack
+	default:
+		return nil, fmt.Errorf("alert receiver %q: unknown type %q", cfg.Name, cfg.Type)
+	}
+
+	return n, nil
+}
+
+// Name returns the receiver name
+func (n *httpNotifier) Name() string {
+	return n.name
+}
+
+// Notify posts one alert. Client errors other than 429 are permanent.
+func (n *httpNotifier) Notify(ctx context.Context, alert models.Alert) error {
+	body, err := n.encode(alert)
+	if err != nil {
+		return &permanentError{err: err}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return &permanentError{err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range n.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("receiver %s responded with status %d", n.name, resp.StatusCode)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return &permanentError{err: err}
+	}
+	return err
+}
+
+// notifyWithRetry delivers an alert, retrying transient failures with exponential backoff
+func notifyWithRetry(ctx context.Context, notifier Notifier, alert models.Alert, maxRetries int, backoff time.Duration) error {
+	var err error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff << (attempt - 1)):
+			}
+		}
+
+		if err = notifier.Notify(ctx, alert); err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return err
+		}
+	}
+	return fmt.Errorf("giving up after %d attempts: %w", maxRetries+1, err)
+}
+
+func encodeWebhook(alert models.Alert) ([]byte, error) {
+	return json.Marshal(WebhookPayload{Version: "1", Alert: alert})
+}
+
+// encodeSlack formats an alert for Slack-compatible incoming webhooks
+func encodeSlack(alert models.Alert) ([]byte, error) {
+	icon := ":red_circle:"
+	if alert.Status == models.AlertResolved {
+		icon = ":large_green_circle:"
+	} else if alert.Severity != "critical" {
+		icon = ":warning:"
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "%s *[%s] %s* (%s)\n%s", icon, strings.ToUpper(string(alert.Status)),
+		alert.Rule, alert.Severity, alert.Message)
+	if alert.Cluster != "" {
+		fmt.Fprintf(&text, "\nCluster: `%s`", alert.Cluster)
+	}
+	fmt.Fprintf(&text, "\nSince: %s", alert.StartsAt.UTC().Format(time.RFC3339))
+	if alert.EndsAt != nil {
+		fmt.Fprintf(&text, "\nResolved: %s", alert.EndsAt.UTC().Format(time.RFC3339))
+	}
+
+	return json.Marshal(map[string]string{"text": text.String()})
+}
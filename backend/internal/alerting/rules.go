@@ -0,0 +1,143 @@
+package alerting
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+)
+
+// Rule types
+const (
+	RuleStatus             = "status"
+	RuleRestarts           = "restarts"
+	RuleArgoCDOutOfSync    = "argocd_out_of_sync"
+	RuleClusterUnreachable = "cluster_unreachable" // clusters whose applications cannot be observed
+)
+
+// defaultSeverity applies to rules without a severity
+const defaultSeverity = "warning"
+
+// DefaultRules are evaluated when alerting is enabled without any configured rule
+var DefaultRules = []config.AlertRuleConfig{
+	{Name: "application-unhealthy", Type: RuleStatus, Statuses: []string{string(models.StatusUnhealthy)}, For: 60, Severity: "critical"},
+	{Name: "application-degraded", Type: RuleStatus, Statuses: []string{string(models.StatusDegraded)}, For: 300, Severity: "warning"},
+	{Name: "application-restarting", Type: RuleRestarts, Threshold: 5, Window: 600, Severity: "warning"},
+	{Name: "argocd-out-of-sync", Type: RuleArgoCDOutOfSync, For: 900, Severity: "warning"},
+	{Name: "cluster-unreachable", Type: RuleClusterUnreachable, Severity: "critical"},
+}
+
+// rule is a validated alert rule
+type rule struct {
+	name              string
+	ruleType          string
+	severity          string
+	statuses          map[string]bool
+	threshold         int
+	window            time.Duration
+	forDuration       time.Duration
+	clusters          map[string]bool
+	namespaces        map[string]bool
+	excludeNamespaces map[string]bool
+	receivers         []string
+}
+
+// newRule validates a rule configuration
+func newRule(cfg config.AlertRuleConfig) (*rule, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("alert rule without a name")
+	}
+
+	r := &rule{
+		name:              cfg.Name,
+		ruleType:          cfg.Type,
+		severity:          cfg.Severity,
+		threshold:         cfg.Threshold,
+		window:            time.Duration(cfg.Window) * time.Second,
+		forDuration:       time.Duration(cfg.For) * time.Second,
+		clusters:          stringSet(cfg.Clusters),
+		namespaces:        stringSet(cfg.Namespaces),
+		excludeNamespaces: stringSet(cfg.ExcludeNamespaces),
+		receivers:         cfg.Receivers,
+	}
+	if r.severity == "" {
+		r.severity = defaultSeverity
+	}
+
+	switch cfg.Type {
+	case RuleStatus:
+		if len(cfg.Statuses) == 0 {
+			return nil, fmt.Errorf("alert rule %q: status rules need at least one status", cfg.Name)
+		}
+		r.statuses = make(map[string]bool, len(cfg.Statuses))
+		for _, status := range cfg.Statuses {
+			r.statuses[strings.ToLower(status)] = true
+		}
+	case RuleRestarts:
+		if cfg.Threshold <= 0 || cfg.Window <= 0 {
+			return nil, fmt.Errorf("alert rule %q: restarts rules need a positive threshold and window", cfg.Name)
+		}
+	case RuleArgoCDOutOfSync, RuleClusterUnreachable:
+	default:
+		return nil, fmt.Errorf("alert rule %q: unknown type %q", cfg.Name, cfg.Type)
+	}
+
+	return r, nil
+}
+
+// clusterInScope reports whether the rule applies to a cluster
+func (r *rule) clusterInScope(cluster string) bool {
+	return len(r.clusters) == 0 || r.clusters[cluster]
+}
+
+// inScope reports whether the rule applies to a cluster and namespace
+func (r *rule) inScope(cluster, namespace string) bool {
+	if !r.clusterInScope(cluster) {
+		return false
+	}
+	if len(r.namespaces) > 0 && !r.namespaces[namespace] {
+		return false
+	}
+	return !r.excludeNamespaces[namespace]
+}
+
+// evaluateApplication returns the alert message when the rule's condition holds for an application
+func (r *rule) evaluateApplication(app models.Application, restartIncrease int) (string, bool) {
+	switch r.ruleType {
+	case RuleStatus:
+		if !r.statuses[app.Status] {
+			return "", false
+		}
+		return fmt.Sprintf("Application %s/%s is %s (%d/%d pods ready)",
+			app.Namespace, app.Name, app.Status, app.Summary.ReadyPods, app.Summary.TotalPods), true
+	case RuleRestarts:
+		if restartIncrease < r.threshold {
+			return "", false
+		}
+		return fmt.Sprintf("Application %s/%s restarted %d times in the last %s",
+			app.Namespace, app.Name, restartIncrease, r.window), true
+	}
+	return "", false
+}
+
+// evaluateArgoCD returns the alert message when the rule's condition holds for an ArgoCD application
+func (r *rule) evaluateArgoCD(app models.ArgoCDApplication) (string, bool) {
+	if r.ruleType != RuleArgoCDOutOfSync || app.SyncStatus != "OutOfSync" {
+		return "", false
+	}
+	return fmt.Sprintf("ArgoCD application %s/%s is out of sync (health: %s, revision: %s)",
+		app.Namespace, app.Name, app.HealthStatus, app.TargetRevision), true
+}
+
+func stringSet(values []string) map[string]bool {
+	if len(values) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
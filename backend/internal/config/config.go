@@ -14,6 +14,14 @@ type Config struct {
 	Kubernetes KubernetesConfig `mapstructure:"kubernetes"`
 	CORS       CORSConfig       `mapstructure:"cors"`
 	Logging    LoggingConfig    `mapstructure:"logging"`
+	Stream     StreamConfig     `mapstructure:"stream"`
+	Metrics    MetricsConfig    `mapstructure:"metrics"`
+	Alerting   AlertingConfig   `mapstructure:"alerting"`
+	Storage    StorageConfig    `mapstructure:"storage"`
+	History    HistoryConfig    `mapstructure:"history"`
+	Grouping   GroupingConfig   `mapstructure:"grouping"`
+	Cache      CacheConfig      `mapstructure:"response_cache"`
+	Auth       AuthConfig       `mapstructure:"auth"`
 }
 
 // ServerConfig holds HTTP server configuration
@@ -27,13 +35,28 @@ type ServerConfig struct {
 
 // KubernetesConfig holds Kubernetes client configuration
 type KubernetesConfig struct {
-	InCluster      bool   `mapstructure:"in_cluster"`
-	ConfigPath     string `mapstructure:"config_path"`
-	DefaultCluster string `mapstructure:"default_cluster"`
+	InCluster      bool            `mapstructure:"in_cluster"`
+	ConfigPath     string          `mapstructure:"config_path"`
+	DefaultCluster string          `mapstructure:"default_cluster"`
+	Contexts       []string        `mapstructure:"contexts"`
+	Clusters       []ClusterConfig `mapstructure:"clusters"`
 	Namespaces     struct {
 		Allowed []string `mapstructure:"allowed"`
 		Exclude []string `mapstructure:"exclude"`
 	} `mapstructure:"namespaces"`
+	Cache struct {
+		Enabled      bool `mapstructure:"enabled"`
+		ResyncPeriod int  `mapstructure:"resync_period"`
+		SyncTimeout  int  `mapstructure:"sync_timeout"`
+	} `mapstructure:"cache"`
+}
+
+// ClusterConfig describes how to connect to one cluster of the registry
+type ClusterConfig struct {
+	Name       string `mapstructure:"name"`
+	InCluster  bool   `mapstructure:"in_cluster"`
+	ConfigPath string `mapstructure:"config_path"`
+	Context    string `mapstructure:"context"`
 }
 
 // CORSConfig holds CORS configuration
@@ -46,12 +69,127 @@ type CORSConfig struct {
 	MaxAge           int      `mapstructure:"max_age"`
 }
 
+// CacheConfig holds the response cache of expensive list endpoints
+type CacheConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	TTL     int  `mapstructure:"ttl"` // seconds a response is served from the cache
+}
+
+// AuthConfig holds bearer token authentication of the API. Tokens are JWTs signed by an
+// OIDC issuer, verified with the keys of a JWKS read from a URL, a file, or the issuer's
+// discovery document when neither is set.
+type AuthConfig struct {
+	Enabled         bool   `mapstructure:"enabled"`
+	Issuer          string `mapstructure:"issuer"`   // expected iss claim, checked when set
+	Audience        string `mapstructure:"audience"` // expected aud claim, required
+	JWKSURL         string `mapstructure:"jwks_url"`
+	JWKSFile        string `mapstructure:"jwks_file"`
+	UserClaim       string `mapstructure:"user_claim"`       // claim naming the user, e.g. email
+	GroupsClaim     string `mapstructure:"groups_claim"`     // claim listing the user's groups
+	RefreshInterval int    `mapstructure:"refresh_interval"` // seconds before keys from a URL are fetched again
+	Leeway          int    `mapstructure:"leeway"`           // seconds of clock skew tolerated on exp and nbf
+}
+
 // LoggingConfig holds logging configuration
 type LoggingConfig struct {
 	Level  string `mapstructure:"level"`
 	Format string `mapstructure:"format"`
 }
 
+// StreamConfig holds application change stream configuration
+type StreamConfig struct {
+	PollInterval      int `mapstructure:"poll_interval"`
+	BufferSize        int `mapstructure:"buffer_size"`
+	HeartbeatInterval int `mapstructure:"heartbeat_interval"`
+}
+
+// MetricsConfig holds Prometheus exporter configuration
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"`
+	Public  bool   `mapstructure:"public"` // serve metrics without authentication when auth is enabled
+}
+
+// AlertingConfig holds alert rule evaluation and notification configuration
+type AlertingConfig struct {
+	Enabled        bool                  `mapstructure:"enabled"`
+	Interval       int                   `mapstructure:"interval"`        // seconds between evaluations
+	RepeatInterval int                   `mapstructure:"repeat_interval"` // seconds before a firing alert is sent again, 0 to never repeat
+	SendResolved   bool                  `mapstructure:"send_resolved"`
+	Rules          []AlertRuleConfig     `mapstructure:"rules"`
+	Receivers      []AlertReceiverConfig `mapstructure:"receivers"`
+}
+
+// AlertRuleConfig describes one alert rule
+type AlertRuleConfig struct {
+	Name              string   `mapstructure:"name"`
+	Type              string   `mapstructure:"type"`      // status, restarts, argocd_out_of_sync or cluster_unreachable
+	Statuses          []string `mapstructure:"statuses"`  // status rules: application statuses that fire
+	Threshold         int      `mapstructure:"threshold"` // restarts rules: restarts within the window that fire
+	Window            int      `mapstructure:"window"`    // restarts rules: seconds
+	For               int      `mapstructure:"for"`       // seconds the condition must hold before firing
+	Severity          string   `mapstructure:"severity"`
+	Clusters          []string `mapstructure:"clusters"`
+	Namespaces        []string `mapstructure:"namespaces"`
+	ExcludeNamespaces []string `mapstructure:"exclude_namespaces"`
+	Receivers         []string `mapstructure:"receivers"` // receiver names, all receivers when empty
+}
+
+// AlertReceiverConfig describes a notification target
+type AlertReceiverConfig struct {
+	Name       string            `mapstructure:"name"`
+	Type       string            `mapstructure:"type"` // webhook or slack
+	URL        string            `mapstructure:"url"`
+	Headers    map[string]string `mapstructure:"headers"`
+	Timeout    int               `mapstructure:"timeout"`
+	MaxRetries int               `mapstructure:"max_retries"`
+}
+
+// StorageConfig holds persistence configuration for history, incidents and settings
+type StorageConfig struct {
+	Driver    string                 `mapstructure:"driver"` // bolt or memory
+	Path      string                 `mapstructure:"path"`   // bolt database file
+	Retention StorageRetentionConfig `mapstructure:"retention"`
+}
+
+// StorageRetentionConfig holds how long records are kept
+type StorageRetentionConfig struct {
+	History   int `mapstructure:"history"`   // days of status history, 0 keeps everything
+	Incidents int `mapstructure:"incidents"` // days after closing, 0 keeps everything
+	Interval  int `mapstructure:"interval"`  // seconds between retention runs
+}
+
+// HistoryConfig holds application health history configuration
+type HistoryConfig struct {
+	Enabled       bool     `mapstructure:"enabled"`
+	Interval      int      `mapstructure:"interval"`       // seconds between snapshots
+	UptimeWindows []string `mapstructure:"uptime_windows"` // default uptime windows, e.g. 24h, 7d
+	MaxPoints     int      `mapstructure:"max_points"`     // points returned before a series is downsampled
+}
+
+// GroupingConfig holds how pods, services and workloads are grouped into applications
+type GroupingConfig struct {
+	Keys          []GroupingKeyConfig       `mapstructure:"keys"`             // ordered application name sources, the first match wins
+	ArgoCDKeys    []GroupingKeyConfig       `mapstructure:"argocd_keys"`      // ordered sources of the managing ArgoCD application
+	GroupByPartOf bool                      `mapstructure:"group_by_part_of"` // group applications into systems by the part-of label
+	PartOfLabel   string                    `mapstructure:"part_of_label"`
+	Namespaces    []GroupingNamespaceConfig `mapstructure:"namespaces"` // overrides, the first matching namespace wins
+}
+
+// GroupingKeyConfig names a label or an annotation that holds an application name
+type GroupingKeyConfig struct {
+	Label      string `mapstructure:"label"`
+	Annotation string `mapstructure:"annotation"`
+	Pattern    string `mapstructure:"pattern"` // optional regular expression, its first capture group is the name
+}
+
+// GroupingNamespaceConfig overrides the grouping of matching namespaces
+type GroupingNamespaceConfig struct {
+	Namespace     string              `mapstructure:"namespace"`        // name or glob pattern, e.g. team-*
+	Keys          []GroupingKeyConfig `mapstructure:"keys"`             // replace the default keys when set
+	GroupByPartOf *bool               `mapstructure:"group_by_part_of"` // replaces the default when set
+}
+
 // Load reads configuration from environment variables and config files
 func Load() (*Config, error) {
 	// Set defaults
@@ -64,6 +202,9 @@ func Load() (*Config, error) {
 	viper.SetDefault("kubernetes.in_cluster", false)
 	viper.SetDefault("kubernetes.config_path", "")
 	viper.SetDefault("kubernetes.default_cluster", "default")
+	viper.SetDefault("kubernetes.cache.enabled", true)
+	viper.SetDefault("kubernetes.cache.resync_period", 600)
+	viper.SetDefault("kubernetes.cache.sync_timeout", 30)
 
 	viper.SetDefault("cors.allowed_origins", []string{"http://localhost:3000", "http://localhost:5173"})
 	viper.SetDefault("cors.allowed_methods", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"})
@@ -72,9 +213,45 @@ func Load() (*Config, error) {
 	viper.SetDefault("cors.allow_credentials", true)
 	viper.SetDefault("cors.max_age", 12)
 
+	viper.SetDefault("response_cache.enabled", true)
+	viper.SetDefault("response_cache.ttl", 5)
+
+	viper.SetDefault("auth.enabled", false)
+	viper.SetDefault("auth.user_claim", "sub")
+	viper.SetDefault("auth.groups_claim", "groups")
+	viper.SetDefault("auth.refresh_interval", 3600)
+	viper.SetDefault("auth.leeway", 60)
+
 	viper.SetDefault("logging.level", "info")
 	viper.SetDefault("logging.format", "json")
 
+	viper.SetDefault("stream.poll_interval", 5)
+	viper.SetDefault("stream.buffer_size", 1000)
+	viper.SetDefault("stream.heartbeat_interval", 15)
+
+	viper.SetDefault("metrics.enabled", true)
+	viper.SetDefault("metrics.path", "/metrics")
+	viper.SetDefault("metrics.public", false)
+
+	viper.SetDefault("alerting.enabled", false)
+	viper.SetDefault("alerting.interval", 30)
+	viper.SetDefault("alerting.repeat_interval", 14400)
+	viper.SetDefault("alerting.send_resolved", true)
+
+	viper.SetDefault("storage.driver", "bolt")
+	viper.SetDefault("storage.path", "data/k8s-monitor.db")
+	viper.SetDefault("storage.retention.history", 30)
+	viper.SetDefault("storage.retention.incidents", 90)
+	viper.SetDefault("storage.retention.interval", 3600)
+
+	viper.SetDefault("history.enabled", true)
+	viper.SetDefault("history.interval", 60)
+	viper.SetDefault("history.uptime_windows", []string{"24h", "7d", "30d"})
+	viper.SetDefault("history.max_points", 500)
+
+	viper.SetDefault("grouping.group_by_part_of", false)
+	viper.SetDefault("grouping.part_of_label", "app.kubernetes.io/part-of")
+
 	// Environment variable mapping
 	viper.SetEnvPrefix("K8S_DASHBOARD")
 	viper.AutomaticEnv()
@@ -115,6 +292,36 @@ func bindEnvVars() {
 	// Kubernetes configuration
 	viper.BindEnv("kubernetes.in_cluster", "K8S_DASHBOARD_KUBERNETES_IN_CLUSTER")
 	viper.BindEnv("kubernetes.config_path", "KUBECONFIG", "K8S_DASHBOARD_KUBERNETES_CONFIG_PATH")
+	viper.BindEnv("kubernetes.default_cluster", "K8S_DASHBOARD_KUBERNETES_DEFAULT_CLUSTER")
+	viper.BindEnv("kubernetes.cache.enabled", "K8S_DASHBOARD_KUBERNETES_CACHE_ENABLED")
+
+	// Metrics configuration
+	viper.BindEnv("metrics.enabled", "K8S_DASHBOARD_METRICS_ENABLED")
+	viper.BindEnv("metrics.public", "K8S_DASHBOARD_METRICS_PUBLIC")
+
+	// Alerting configuration
+	viper.BindEnv("alerting.enabled", "K8S_DASHBOARD_ALERTING_ENABLED")
+
+	// Storage configuration
+	viper.BindEnv("storage.driver", "K8S_DASHBOARD_STORAGE_DRIVER")
+	viper.BindEnv("storage.path", "K8S_DASHBOARD_STORAGE_PATH")
+
+	// History configuration
+	viper.BindEnv("history.enabled", "K8S_DASHBOARD_HISTORY_ENABLED")
+
+	// Grouping configuration
+	viper.BindEnv("grouping.group_by_part_of", "K8S_DASHBOARD_GROUPING_GROUP_BY_PART_OF")
+
+	// Response cache configuration
+	viper.BindEnv("response_cache.enabled", "K8S_DASHBOARD_RESPONSE_CACHE_ENABLED")
+	viper.BindEnv("response_cache.ttl", "K8S_DASHBOARD_RESPONSE_CACHE_TTL")
+
+	// Authentication configuration
+	viper.BindEnv("auth.enabled", "K8S_DASHBOARD_AUTH_ENABLED")
+	viper.BindEnv("auth.issuer", "K8S_DASHBOARD_AUTH_ISSUER")
+	viper.BindEnv("auth.audience", "K8S_DASHBOARD_AUTH_AUDIENCE")
+	viper.BindEnv("auth.jwks_url", "K8S_DASHBOARD_AUTH_JWKS_URL")
+	viper.BindEnv("auth.jwks_file", "K8S_DASHBOARD_AUTH_JWKS_FILE")
 
 	// CORS configuration
 	viper.BindEnv("cors.allowed_origins", "K8S_DASHBOARD_CORS_ALLOWED_ORIGINS")
@@ -128,6 +335,10 @@ func bindEnvVars() {
 		viper.Set("cors.allowed_origins", strings.Split(origins, ","))
 	}
 
+	if contexts := os.Getenv("K8S_DASHBOARD_KUBERNETES_CONTEXTS"); contexts != "" {
+		viper.Set("kubernetes.contexts", strings.Split(contexts, ","))
+	}
+
 	if port := os.Getenv("PORT"); port != "" {
 		if p, err := strconv.Atoi(port); err == nil {
 			viper.Set("server.port", p)
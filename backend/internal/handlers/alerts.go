@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/alerting"
+	"k8s-monitor/internal/models"
+	"k8s-monitor/pkg/utils"
+)
+
+// AlertHandler handles alert-related HTTP requests
+type AlertHandler struct {
+	engine *alerting.Engine
+	logger *logrus.Logger
+}
+
+// NewAlertHandler creates a new alert handler instance. engine is nil when alerting is disabled.
+func NewAlertHandler(engine *alerting.Engine, logger *logrus.Logger) *AlertHandler {
+	return &AlertHandler{
+		engine: engine,
+		logger: logger,
+	}
+}
+
+// List retrieves the currently firing alerts
+// @Summary List firing alerts
+// @Description Get the alerts that are currently firing, newest first
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param namespace query string false "Only return alerts for this namespace"
+// @Param cluster query string false "Only return alerts for this cluster"
+// @Success 200 {object} models.AlertListResponse
+// @Router /api/v1/alerts [get]
+func (h *AlertHandler) List(c *gin.Context) {
+	response := models.AlertListResponse{Alerts: []models.Alert{}}
+
+	if h.engine != nil {
+		response.Enabled = true
+
+		namespace := c.Query("namespace")
+		cluster := c.Query("cluster")
+		for _, alert := range h.engine.Active() {
+			if (namespace == "" || alert.Namespace == namespace) && (cluster == "" || alert.Cluster == cluster) {
+				response.Alerts = append(response.Alerts, alert)
+			}
+		}
+	}
+	response.Total = len(response.Alerts)
+
+	utils.WithComponent(h.logger, "alert-handler").WithField("total", response.Total).Debug("Fetched firing alerts")
+	models.RespondSuccess(c, response)
+}
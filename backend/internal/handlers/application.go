@@ -2,7 +2,7 @@ package handlers
 
 import (
 	"context"
-	"fmt"
+	"sort"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,15 +15,15 @@ import (
 
 // ApplicationHandler handles application-related HTTP requests
 type ApplicationHandler struct {
-	appService *services.ApplicationService
-	logger     *logrus.Logger
+	clusters *services.ClusterManager
+	logger   *logrus.Logger
 }
 
 // NewApplicationHandler creates a new application handler instance
-func NewApplicationHandler(appService *services.ApplicationService, logger *logrus.Logger) *ApplicationHandler {
+func NewApplicationHandler(clusters *services.ClusterManager, logger *logrus.Logger) *ApplicationHandler {
 	return &ApplicationHandler{
-		appService: appService,
-		logger:     logger,
+		clusters: clusters,
+		logger:   logger,
 	}
 }
 
@@ -33,10 +33,39 @@ func NewApplicationHandler(appService *services.ApplicationService, logger *logr
 // @Tags applications
 // @Accept json
 // @Produce json
+// @Param cluster query string false "Cluster name, or 'all' to aggregate every cluster"
+// @Param labelSelector query string false "Kubernetes label selector matched against application labels"
+// @Param status query string false "Application status" Enums(healthy, degraded, unhealthy, unknown)
+// @Param type query string false "Application type, e.g. deployment or statefulset"
+// @Param node query string false "Only applications with a pod on this node"
+// @Param search query string false "Case-insensitive match on the application name"
+// @Param page query int false "Page number, starting at 1"
+// @Param pageSize query int false "Page size, at most 500"
+// @Param sort query string false "Sort key" Enums(name, namespace, cluster, status, type, pods, restarts, age)
+// @Param order query string false "Sort order" Enums(asc, desc)
 // @Success 200 {object} models.ApplicationsResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
 // @Router /api/v1/applications [get]
 func (h *ApplicationHandler) List(c *gin.Context) {
+	filter, err := parseApplicationFilter(c)
+	if err != nil {
+		models.RespondBadRequest(c, "Invalid query parameters", err.Error())
+		return
+	}
+	page, err := parsePageQuery(c, applicationSortKeys)
+	if err != nil {
+		models.RespondBadRequest(c, "Invalid query parameters", err.Error())
+		return
+	}
+
+	clusters, ok := resolveClusters(c, h.clusters)
+	if !ok {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
 	defer cancel()
 
@@ -44,15 +73,20 @@ func (h *ApplicationHandler) List(c *gin.Context) {
 	logger.Info("Fetching all applications")
 
 	// Get applications from service layer
-	response, err := h.appService.GetApplications(ctx)
+	response, err := h.collectApplications(clusters, logger, filter, func(cluster *services.Cluster) (*models.ApplicationsResponse, error) {
+		return cluster.Applications.GetApplications(ctx)
+	})
 	if err != nil {
 		logger.WithError(err).Error("Failed to fetch applications")
-		models.RespondKubernetesError(c, "list applications", err)
+		respondError(c, "list applications", err)
 		return
 	}
+	response.Cluster = c.Query("cluster")
+	var meta *models.Meta
+	response.Applications, meta = paginate(response.Applications, page, applicationSortKeys)
 
 	logger.WithField("total", response.Total).Info("Successfully fetched applications")
-	models.RespondSuccess(c, response)
+	models.RespondSuccessWithMeta(c, response, meta)
 }
 
 // ListByNamespace retrieves applications from a specific namespace
@@ -62,8 +96,19 @@ func (h *ApplicationHandler) List(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param namespace path string true "Namespace name"
+// @Param cluster query string false "Cluster name, or 'all' to aggregate every cluster"
+// @Param labelSelector query string false "Kubernetes label selector matched against application labels"
+// @Param status query string false "Application status" Enums(healthy, degraded, unhealthy, unknown)
+// @Param type query string false "Application type, e.g. deployment or statefulset"
+// @Param node query string false "Only applications with a pod on this node"
+// @Param search query string false "Case-insensitive match on the application name"
+// @Param page query int false "Page number, starting at 1"
+// @Param pageSize query int false "Page size, at most 500"
+// @Param sort query string false "Sort key" Enums(name, namespace, cluster, status, type, pods, restarts, age)
+// @Param order query string false "Sort order" Enums(asc, desc)
 // @Success 200 {object} models.ApplicationsResponse
 // @Failure 400 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
 // @Failure 404 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
 // @Router /api/v1/applications/{namespace} [get]
@@ -74,6 +119,22 @@ func (h *ApplicationHandler) ListByNamespace(c *gin.Context) {
 		return
 	}
 
+	filter, err := parseApplicationFilter(c)
+	if err != nil {
+		models.RespondBadRequest(c, "Invalid query parameters", err.Error())
+		return
+	}
+	page, err := parsePageQuery(c, applicationSortKeys)
+	if err != nil {
+		models.RespondBadRequest(c, "Invalid query parameters", err.Error())
+		return
+	}
+
+	clusters, ok := resolveClusters(c, h.clusters)
+	if !ok {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
 	defer cancel()
 
@@ -81,29 +142,22 @@ func (h *ApplicationHandler) ListByNamespace(c *gin.Context) {
 	logger.Info("Fetching applications by namespace")
 
 	// Get applications from service layer
-	response, err := h.appService.GetApplicationsByNamespace(ctx, namespace)
+	response, err := h.collectApplications(clusters, logger, filter, func(cluster *services.Cluster) (*models.ApplicationsResponse, error) {
+		return cluster.Applications.GetApplicationsByNamespace(ctx, namespace)
+	})
 	if err != nil {
 		logger.WithError(err).Error("Failed to fetch applications")
-
-		// Check if it's a namespace access error
-		if fmt.Sprintf("%v", err) == fmt.Sprintf("access to namespace '%s' not allowed", namespace) {
-			models.RespondBadRequest(c, "Access to namespace not allowed",
-				fmt.Sprintf("Namespace '%s' is not in the allowed list", namespace))
-			return
-		}
-
-		// Check if it's a namespace not found error
-		if IsNamespaceNotFoundError(err) {
-			models.RespondNamespaceNotFound(c, namespace)
-			return
-		}
-
-		models.RespondKubernetesError(c, "list applications in namespace", err)
+		respondError(c, "list applications in namespace", err)
 		return
 	}
+	response.Namespace = namespace
+	response.Cluster = c.Query("cluster")
+	var meta *models.Meta
+	response.Applications, meta = paginate(response.Applications, page, applicationSortKeys)
+	meta.Namespace = namespace
 
 	logger.WithField("total", response.Total).Info("Successfully fetched applications by namespace")
-	models.RespondSuccess(c, response)
+	models.RespondSuccessWithMeta(c, response, meta)
 }
 
 // GetApplication retrieves a specific application by name and namespace
@@ -114,8 +168,10 @@ func (h *ApplicationHandler) ListByNamespace(c *gin.Context) {
 // @Produce json
 // @Param namespace path string true "Namespace name"
 // @Param name path string true "Application name"
+// @Param cluster query string false "Cluster name"
 // @Success 200 {object} models.Application
 // @Failure 400 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
 // @Failure 404 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
 // @Router /api/v1/applications/{namespace}/{name} [get]
@@ -128,41 +184,21 @@ func (h *ApplicationHandler) GetApplication(c *gin.Context) {
 		return
 	}
 
+	cluster, ok := resolveCluster(c, h.clusters)
+	if !ok {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
 	defer cancel()
 
 	logger := utils.WithApplication(h.logger, namespace, appName)
 	logger.Info("Fetching specific application")
 
-	// Get applications from the namespace
-	response, err := h.appService.GetApplicationsByNamespace(ctx, namespace)
+	foundApp, err := cluster.Applications.GetApplication(ctx, namespace, appName)
 	if err != nil {
-		logger.WithError(err).Error("Failed to fetch applications")
-
-		// Check if it's a namespace access error
-		if fmt.Sprintf("%v", err) == fmt.Sprintf("access to namespace '%s' not allowed", namespace) {
-			models.RespondBadRequest(c, "Access to namespace not allowed",
-				fmt.Sprintf("Namespace '%s' is not in the allowed list", namespace))
-			return
-		}
-
-		models.RespondKubernetesError(c, "get application", err)
-		return
-	}
-
-	// Find the specific application
-	var foundApp *models.Application
-	for _, app := range response.Applications {
-		if app.Name == appName {
-			foundApp = &app
-			break
-		}
-	}
-
-	if foundApp == nil {
-		models.RespondError(c, 404, models.ErrCodeResourceNotFound,
-			"Application not found",
-			fmt.Sprintf("Application '%s' not found in namespace '%s'", appName, namespace))
+		logger.WithError(err).Error("Failed to fetch application")
+		respondError(c, "get application", err)
 		return
 	}
 
@@ -178,8 +214,10 @@ func (h *ApplicationHandler) GetApplication(c *gin.Context) {
 // @Produce json
 // @Param namespace path string true "Namespace name"
 // @Param name path string true "Application name"
+// @Param cluster query string false "Cluster name"
 // @Success 200 {object} object{name=string,namespace=string,status=string,summary=models.ApplicationSummary}
 // @Failure 400 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
 // @Failure 404 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
 // @Router /api/v1/applications/{namespace}/{name}/status [get]
@@ -192,33 +230,21 @@ func (h *ApplicationHandler) GetApplicationStatus(c *gin.Context) {
 		return
 	}
 
+	cluster, ok := resolveCluster(c, h.clusters)
+	if !ok {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 	defer cancel()
 
 	logger := utils.WithApplication(h.logger, namespace, appName)
 	logger.Info("Fetching application status")
 
-	// Get applications from the namespace
-	response, err := h.appService.GetApplicationsByNamespace(ctx, namespace)
+	foundApp, err := cluster.Applications.GetApplication(ctx, namespace, appName)
 	if err != nil {
 		logger.WithError(err).Error("Failed to fetch application status")
-		models.RespondKubernetesError(c, "get application status", err)
-		return
-	}
-
-	// Find the specific application
-	var foundApp *models.Application
-	for _, app := range response.Applications {
-		if app.Name == appName {
-			foundApp = &app
-			break
-		}
-	}
-
-	if foundApp == nil {
-		models.RespondError(c, 404, models.ErrCodeResourceNotFound,
-			"Application not found",
-			fmt.Sprintf("Application '%s' not found in namespace '%s'", appName, namespace))
+		respondError(c, "get application status", err)
 		return
 	}
 
@@ -226,6 +252,7 @@ func (h *ApplicationHandler) GetApplicationStatus(c *gin.Context) {
 	statusResponse := map[string]interface{}{
 		"name":      foundApp.Name,
 		"namespace": foundApp.Namespace,
+		"cluster":   foundApp.Cluster,
 		"status":    foundApp.Status,
 		"summary":   foundApp.Summary,
 		"type":      foundApp.Type,
@@ -235,3 +262,42 @@ func (h *ApplicationHandler) GetApplicationStatus(c *gin.Context) {
 	logger.Info("Successfully fetched application status")
 	models.RespondSuccess(c, statusResponse)
 }
+
+// collectApplications fetches applications from every selected cluster and merges the ones
+// passing the filter into one response, summarizing only those
+func (h *ApplicationHandler) collectApplications(
+	clusters []*services.Cluster,
+	logger *logrus.Entry,
+	filter models.ApplicationFilter,
+	fetch func(cluster *services.Cluster) (*models.ApplicationsResponse, error),
+) (*models.ApplicationsResponse, error) {
+	merged := &models.ApplicationsResponse{MetricsAvailable: true}
+
+	clusterErrors, err := forEachCluster(clusters, logger, func(cluster *services.Cluster) error {
+		response, err := fetch(cluster)
+		if err != nil {
+			return err
+		}
+		for _, app := range response.Applications {
+			if filter.Matches(app) {
+				merged.Applications = append(merged.Applications, app)
+				merged.Summary.Count(app)
+			}
+		}
+		// Metrics are only reported available when every served cluster has them
+		merged.MetricsAvailable = merged.MetricsAvailable && response.MetricsAvailable
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep aggregated results ordered by cluster, then namespace and name
+	sort.SliceStable(merged.Applications, func(i, j int) bool {
+		return merged.Applications[i].Cluster < merged.Applications[j].Cluster
+	})
+
+	merged.Total = len(merged.Applications)
+	merged.ClusterErrors = clusterErrors
+	return merged, nil
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+	"k8s-monitor/internal/services"
+)
+
+func newTestLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+func newRunningPod(namespace, name, app string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels:    map[string]string{"app": app},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+// newTestClient creates a cluster client backed by fake clientsets
+func newTestClient(name string, cfg config.KubernetesConfig, objects ...runtime.Object) services.KubernetesClient {
+	return newTestClientForClientset(name, cfg, fake.NewSimpleClientset(objects...))
+}
+
+// newTestClientForClientset creates a cluster client backed by the given fake clientset
+func newTestClientForClientset(name string, cfg config.KubernetesConfig, clientset *fake.Clientset) services.KubernetesClient {
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}: "ApplicationList",
+			{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}:       "PodMetricsList",
+			{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "nodes"}:      "NodeMetricsList",
+		})
+	return services.NewKubernetesServiceForClients(name, clientset, dynamicClient, cfg, newTestLogger())
+}
+
+// newTestRouter registers the application routes against the given clusters
+func newTestRouter(clients ...services.KubernetesClient) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewApplicationHandler(services.NewClusterManagerFromClients(clients, newTestLogger()), newTestLogger())
+
+	router := gin.New()
+	router.GET("/api/v1/applications", handler.List)
+	router.GET("/api/v1/applications/:namespace", handler.ListByNamespace)
+	router.GET("/api/v1/applications/:namespace/:name", handler.GetApplication)
+	router.GET("/api/v1/applications/:namespace/:name/status", handler.GetApplicationStatus)
+	router.GET("/api/v1/applications/:namespace/:name/logs", handler.GetLogs)
+	return router
+}
+
+// performRequest runs a GET request and decodes the API envelope
+func performRequest(t *testing.T, router http.Handler, path string, data interface{}) (int, models.APIResponse) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+	envelope := models.APIResponse{Data: data}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("failed to decode response %q: %v", recorder.Body.String(), err)
+	}
+	return recorder.Code, envelope
+}
+
+func TestApplicationHandlerList(t *testing.T) {
+	router := newTestRouter(
+		newTestClient("prod", config.KubernetesConfig{},
+			newRunningPod("shop", "api-1", "api"),
+			newRunningPod("shop", "web-1", "web"),
+		),
+		newTestClient("dev", config.KubernetesConfig{},
+			newRunningPod("shop", "api-1", "api"),
+		),
+	)
+
+	tests := []struct {
+		name     string
+		path     string
+		status   int
+		total    int
+		clusters []string
+		errCode  string
+	}{
+		{name: "default cluster", path: "/api/v1/applications", status: http.StatusOK, total: 2, clusters: []string{"prod", "prod"}},
+		{name: "selected cluster", path: "/api/v1/applications?cluster=dev", status: http.StatusOK, total: 1, clusters: []string{"dev"}},
+		{name: "all clusters", path: "/api/v1/applications?cluster=all", status: http.StatusOK, total: 3, clusters: []string{"dev", "prod", "prod"}},
+		{name: "unknown cluster", path: "/api/v1/applications?cluster=qa", status: http.StatusNotFound, errCode: models.ErrCodeClusterNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response models.ApplicationsResponse
+			status, envelope := performRequest(t, router, tt.path, &response)
+
+			if status != tt.status {
+				t.Fatalf("status = %d, want %d", status, tt.status)
+			}
+			if tt.errCode != "" {
+				if envelope.Error == nil || envelope.Error.Code != tt.errCode {
+					t.Errorf("error = %+v, want code %s", envelope.Error, tt.errCode)
+				}
+				return
+			}
+
+			if response.Total != tt.total {
+				t.Fatalf("Total = %d, want %d", response.Total, tt.total)
+			}
+			for i, cluster := range tt.clusters {
+				if response.Applications[i].Cluster != cluster {
+					t.Errorf("applications[%d].Cluster = %q, want %q", i, response.Applications[i].Cluster, cluster)
+				}
+			}
+		})
+	}
+}
+
+func TestApplicationHandlerListFilters(t *testing.T) {
+	failed := newRunningPod("shop", "worker-1", "worker")
+	failed.Status.Phase = corev1.PodFailed
+	failed.Status.Conditions = nil
+	web := newRunningPod("shop", "web-1", "web")
+	web.Labels["tier"] = "frontend"
+	web.Spec.NodeName = "node-b"
+
+	router := newTestRouter(newTestClient("prod", config.KubernetesConfig{},
+		newRunningPod("shop", "api-1", "api"),
+		web,
+		failed,
+	))
+
+	tests := []struct {
+		name      string
+		path      string
+		status    int
+		apps      []string
+		healthy   int
+		unhealthy int
+	}{
+		{name: "status", path: "/api/v1/applications?status=unhealthy", status: http.StatusOK, apps: []string{"worker"}, unhealthy: 1},
+		{name: "label selector", path: "/api/v1/applications/shop?labelSelector=tier%3Dfrontend", status: http.StatusOK, apps: []string{"web"}, healthy: 1},
+		{name: "node", path: "/api/v1/applications?node=node-b", status: http.StatusOK, apps: []string{"web"}, healthy: 1},
+		{name: "search", path: "/api/v1/applications?search=AP", status: http.StatusOK, apps: []string{"api"}, healthy: 1},
+		{name: "type", path: "/api/v1/applications?type=statefulset", status: http.StatusOK},
+		{name: "invalid status", path: "/api/v1/applications?status=broken", status: http.StatusBadRequest},
+		{name: "invalid selector", path: "/api/v1/applications?labelSelector=tier%3D%3D%3D", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response models.ApplicationsResponse
+			status, _ := performRequest(t, router, tt.path, &response)
+
+			if status != tt.status {
+				t.Fatalf("status = %d, want %d", status, tt.status)
+			}
+			if status != http.StatusOK {
+				return
+			}
+
+			if response.Total != len(tt.apps) {
+				t.Fatalf("Total = %d, want %d", response.Total, len(tt.apps))
+			}
+			for i, name := range tt.apps {
+				if response.Applications[i].Name != name {
+					t.Errorf("applications[%d] = %q, want %q", i, response.Applications[i].Name, name)
+				}
+			}
+			if response.Summary.Healthy != tt.healthy || response.Summary.Unhealthy != tt.unhealthy {
+				t.Errorf("Summary = %+v, want %d healthy and %d unhealthy", response.Summary, tt.healthy, tt.unhealthy)
+			}
+		})
+	}
+}
+
+func TestApplicationHandlerGetApplication(t *testing.T) {
+	cfg := config.KubernetesConfig{}
+	cfg.Namespaces.Exclude = []string{"kube-system"}
+
+	router := newTestRouter(newTestClient("prod", cfg,
+		newRunningPod("shop", "api-1", "api"),
+		newRunningPod("shop", "api-2", "api"),
+	))
+
+	tests := []struct {
+		name    string
+		path    string
+		status  int
+		errCode string
+	}{
+		{name: "found", path: "/api/v1/applications/shop/api", status: http.StatusOK},
+		{name: "status", path: "/api/v1/applications/shop/api/status", status: http.StatusOK},
+		{name: "missing application", path: "/api/v1/applications/shop/web", status: http.StatusNotFound, errCode: models.ErrCodeResourceNotFound},
+		{name: "missing application status", path: "/api/v1/applications/shop/web/status", status: http.StatusNotFound, errCode: models.ErrCodeResourceNotFound},
+		{name: "missing container", path: "/api/v1/applications/shop/api/logs?container=sidecar", status: http.StatusNotFound, errCode: models.ErrCodeResourceNotFound},
+		{name: "excluded namespace", path: "/api/v1/applications/kube-system/dns", status: http.StatusForbidden, errCode: models.ErrCodeForbidden},
+		{name: "all clusters rejected", path: "/api/v1/applications/shop/api?cluster=all", status: http.StatusBadRequest, errCode: models.ErrCodeBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var app models.Application
+			status, envelope := performRequest(t, router, tt.path, &app)
+
+			if status != tt.status {
+				t.Fatalf("status = %d, want %d", status, tt.status)
+			}
+			if tt.errCode != "" {
+				if envelope.Error == nil || envelope.Error.Code != tt.errCode {
+					t.Errorf("error = %+v, want code %s", envelope.Error, tt.errCode)
+				}
+				return
+			}
+			if app.Name != "api" || app.Status != string(models.StatusHealthy) {
+				t.Errorf("got %s with status %q, want healthy api", app.Name, app.Status)
+			}
+		})
+	}
+}
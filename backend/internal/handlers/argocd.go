@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,84 +14,107 @@ import (
 
 // ArgoCDHandler handles ArgoCD application related HTTP requests
 type ArgoCDHandler struct {
-	k8sService *services.KubernetesService
-	logger     *logrus.Logger
+	clusters *services.ClusterManager
+	logger   *logrus.Logger
 }
 
 // NewArgoCDHandler creates a new ArgoCD handler instance
-func NewArgoCDHandler(k8sService *services.KubernetesService, logger *logrus.Logger) *ArgoCDHandler {
+func NewArgoCDHandler(clusters *services.ClusterManager, logger *logrus.Logger) *ArgoCDHandler {
 	return &ArgoCDHandler{
-		k8sService: k8sService,
-		logger:     logger,
+		clusters: clusters,
+		logger:   logger,
 	}
 }
 
 // List retrieves all ArgoCD applications across all accessible namespaces
 func (h *ArgoCDHandler) List(c *gin.Context) {
+	page, err := parsePageQuery(c, argoCDSortKeys)
+	if err != nil {
+		models.RespondBadRequest(c, "Invalid query parameters", err.Error())
+		return
+	}
+
+	clusters, ok := resolveClusters(c, h.clusters)
+	if !ok {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 	defer cancel()
 
 	logger := utils.WithComponent(h.logger, "argocd-handler")
 	logger.Info("Fetching all ArgoCD applications")
 
-	appList, err := h.k8sService.GetArgoApplications(ctx, "")
-	if err != nil {
-		logger.WithError(err).Error("Failed to fetch ArgoCD applications")
-		models.RespondKubernetesError(c, "list argocd applications", err)
-		return
-	}
-
 	var applications []models.ArgoCDApplication
 	summary := models.ArgoCDSummary{}
 
-	for _, app := range appList.Items {
-		if !h.k8sService.IsNamespaceAllowed(app.GetNamespace()) {
-			continue
-		}
-
-		argoCDApp := models.FromArgoApplication(&app)
-		applications = append(applications, argoCDApp)
-
-		// Update summary based on computed status
-		switch argoCDApp.Status {
-		case "healthy":
-			summary.Healthy++
-		case "degraded":
-			summary.Degraded++
-		case "progressing":
-			summary.Progressing++
-		default:
-			summary.Unknown++
+	clusterErrors, err := forEachCluster(clusters, logger, func(cluster *services.Cluster) error {
+		appList, err := cluster.K8s.GetArgoApplications(ctx, "")
+		if err != nil {
+			return err
 		}
 
-		// Also track sync status
-		switch argoCDApp.SyncStatus {
-		case "Synced":
-			summary.Synced++
-		case "OutOfSync":
-			summary.OutOfSync++
-		}
-
-		switch argoCDApp.HealthStatus {
-		case "Healthy":
-			summary.Healthy++
-		case "Degraded":
-			summary.Degraded++
-		case "Progressing":
-			summary.Progressing++
-		default:
-			summary.Unknown++
+		for _, app := range appList.Items {
+			if !cluster.K8s.IsNamespaceAllowed(app.GetNamespace()) {
+				continue
+			}
+
+			argoCDApp := models.FromArgoApplication(&app)
+			argoCDApp.Cluster = cluster.Name
+			applications = append(applications, argoCDApp)
+
+			// Update summary based on computed status
+			switch argoCDApp.Status {
+			case "healthy":
+				summary.Healthy++
+			case "degraded":
+				summary.Degraded++
+			case "progressing":
+				summary.Progressing++
+			default:
+				summary.Unknown++
+			}
+
+			// Also track sync status
+			switch argoCDApp.SyncStatus {
+			case "Synced":
+				summary.Synced++
+			case "OutOfSync":
+				summary.OutOfSync++
+			}
+
+			switch argoCDApp.HealthStatus {
+			case "Healthy":
+				summary.Healthy++
+			case "Degraded":
+				summary.Degraded++
+			case "Progressing":
+				summary.Progressing++
+			default:
+				summary.Unknown++
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch ArgoCD applications")
+		models.RespondKubernetesError(c, "list argocd applications", err)
+		return
 	}
 
+	total := len(applications)
+	applications, meta := paginate(applications, page, argoCDSortKeys)
+
 	response := models.ArgoCDApplicationsResponse{
-		Applications: applications,
-		Total:        len(applications),
-		Summary:      summary,
+		Applications:  applications,
+		Total:         total,
+		Cluster:       c.Query("cluster"),
+		Summary:       summary,
+		ClusterErrors: clusterErrors,
 	}
 
-	logger.WithField("total", len(applications)).Info("Successfully fetched ArgoCD applications")
-	models.RespondSuccess(c, response)
+	logger.WithField("total", total).Info("Successfully fetched ArgoCD applications")
+	models.RespondSuccessWithMeta(c, response, meta)
 }
 
 // ListByNamespace retrieves ArgoCD applications from a specific namespace
@@ -103,73 +125,96 @@ func (h *ArgoCDHandler) ListByNamespace(c *gin.Context) {
 		return
 	}
 
+	page, err := parsePageQuery(c, argoCDSortKeys)
+	if err != nil {
+		models.RespondBadRequest(c, "Invalid query parameters", err.Error())
+		return
+	}
+
+	clusters, ok := resolveClusters(c, h.clusters)
+	if !ok {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 	defer cancel()
 
 	logger := utils.WithNamespace(h.logger, namespace)
 	logger.Info("Fetching ArgoCD applications by namespace")
 
-	if !h.k8sService.IsNamespaceAllowed(namespace) {
-		models.RespondBadRequest(c, "Access to namespace not allowed",
-			fmt.Sprintf("Namespace '%s' is not in the allowed list", namespace))
-		return
-	}
-
-	appList, err := h.k8sService.GetArgoApplications(ctx, namespace)
-	if err != nil {
-		logger.WithError(err).Error("Failed to fetch ArgoCD applications")
-		models.RespondKubernetesError(c, "list argocd applications in namespace", err)
+	if !clusters[0].K8s.IsNamespaceAllowed(namespace) {
+		models.RespondNamespaceForbidden(c, namespace)
 		return
 	}
 
 	var applications []models.ArgoCDApplication
 	summary := models.ArgoCDSummary{}
 
-	for _, app := range appList.Items {
-		argoCDApp := models.FromArgoApplication(&app)
-		applications = append(applications, argoCDApp)
-
-		// Update summary based on computed status
-		switch argoCDApp.Status {
-		case "healthy":
-			summary.Healthy++
-		case "degraded":
-			summary.Degraded++
-		case "progressing":
-			summary.Progressing++
-		default:
-			summary.Unknown++
+	clusterErrors, err := forEachCluster(clusters, logger, func(cluster *services.Cluster) error {
+		appList, err := cluster.K8s.GetArgoApplications(ctx, namespace)
+		if err != nil {
+			return err
 		}
 
-		// Also track sync status
-		switch argoCDApp.SyncStatus {
-		case "Synced":
-			summary.Synced++
-		case "OutOfSync":
-			summary.OutOfSync++
-		}
-
-		switch argoCDApp.HealthStatus {
-		case "Healthy":
-			summary.Healthy++
-		case "Degraded":
-			summary.Degraded++
-		case "Progressing":
-			summary.Progressing++
-		default:
-			summary.Unknown++
+		for _, app := range appList.Items {
+			argoCDApp := models.FromArgoApplication(&app)
+			argoCDApp.Cluster = cluster.Name
+			applications = append(applications, argoCDApp)
+
+			// Update summary based on computed status
+			switch argoCDApp.Status {
+			case "healthy":
+				summary.Healthy++
+			case "degraded":
+				summary.Degraded++
+			case "progressing":
+				summary.Progressing++
+			default:
+				summary.Unknown++
+			}
+
+			// Also track sync status
+			switch argoCDApp.SyncStatus {
+			case "Synced":
+				summary.Synced++
+			case "OutOfSync":
+				summary.OutOfSync++
+			}
+
+			switch argoCDApp.HealthStatus {
+			case "Healthy":
+				summary.Healthy++
+			case "Degraded":
+				summary.Degraded++
+			case "Progressing":
+				summary.Progressing++
+			default:
+				summary.Unknown++
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch ArgoCD applications")
+		models.RespondKubernetesError(c, "list argocd applications in namespace", err)
+		return
 	}
 
+	total := len(applications)
+	applications, meta := paginate(applications, page, argoCDSortKeys)
+	meta.Namespace = namespace
+
 	response := models.ArgoCDApplicationsResponse{
-		Applications: applications,
-		Total:        len(applications),
-		Namespace:    namespace,
-		Summary:      summary,
+		Applications:  applications,
+		Total:         total,
+		Namespace:     namespace,
+		Cluster:       c.Query("cluster"),
+		Summary:       summary,
+		ClusterErrors: clusterErrors,
 	}
 
-	logger.WithField("total", len(applications)).Info("Successfully fetched ArgoCD applications by namespace")
-	models.RespondSuccess(c, response)
+	logger.WithField("total", total).Info("Successfully fetched ArgoCD applications by namespace")
+	models.RespondSuccessWithMeta(c, response, meta)
 }
 
 // GetApplication retrieves a specific ArgoCD application by name and namespace
@@ -182,6 +227,11 @@ func (h *ArgoCDHandler) GetApplication(c *gin.Context) {
 		return
 	}
 
+	cluster, ok := resolveCluster(c, h.clusters)
+	if !ok {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 	defer cancel()
 
@@ -192,13 +242,12 @@ func (h *ArgoCDHandler) GetApplication(c *gin.Context) {
 	})
 	logger.Info("Fetching specific ArgoCD application")
 
-	if !h.k8sService.IsNamespaceAllowed(namespace) {
-		models.RespondBadRequest(c, "Access to namespace not allowed",
-			fmt.Sprintf("Namespace '%s' is not in the allowed list", namespace))
+	if !cluster.K8s.IsNamespaceAllowed(namespace) {
+		models.RespondNamespaceForbidden(c, namespace)
 		return
 	}
 
-	app, err := h.k8sService.GetArgoApplication(ctx, namespace, appName)
+	app, err := cluster.K8s.GetArgoApplication(ctx, namespace, appName)
 	if err != nil {
 		logger.WithError(err).Error("Failed to fetch ArgoCD application")
 		models.RespondKubernetesError(c, "get argocd application", err)
@@ -206,6 +255,7 @@ func (h *ArgoCDHandler) GetApplication(c *gin.Context) {
 	}
 
 	argoCDApp := models.FromArgoApplication(app)
+	argoCDApp.Cluster = cluster.Name
 
 	logger.Info("Successfully fetched ArgoCD application")
 	models.RespondSuccess(c, argoCDApp)
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/models"
+	"k8s-monitor/internal/services"
+	"k8s-monitor/pkg/utils"
+)
+
+// ClusterHandler handles cluster registry HTTP requests
+type ClusterHandler struct {
+	clusters *services.ClusterManager
+	logger   *logrus.Logger
+}
+
+// NewClusterHandler creates a new cluster handler instance
+func NewClusterHandler(clusters *services.ClusterManager, logger *logrus.Logger) *ClusterHandler {
+	return &ClusterHandler{
+		clusters: clusters,
+		logger:   logger,
+	}
+}
+
+// List retrieves all configured clusters with their connectivity
+// @Summary List clusters
+// @Description Get the clusters configured for the monitor and whether each one is reachable
+// @Tags clusters
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.ClusterListResponse
+// @Router /api/v1/clusters [get]
+func (h *ClusterHandler) List(c *gin.Context) {
+	logger := utils.WithComponent(h.logger, "cluster-handler")
+	logger.Info("Fetching clusters")
+
+	clusters := h.clusters.Status()
+
+	response := models.ClusterListResponse{
+		Clusters:       clusters,
+		Total:          len(clusters),
+		DefaultCluster: h.clusters.DefaultName(),
+	}
+
+	logger.WithField("total", len(clusters)).Info("Successfully fetched clusters")
+	models.RespondSuccess(c, response)
+}
+
+// resolveClusters returns the clusters selected by the "cluster" query parameter,
+// responding with an error and returning false when the selection is invalid
+func resolveClusters(c *gin.Context, clusters *services.ClusterManager) ([]*services.Cluster, bool) {
+	selector := c.DefaultQuery("cluster", clusters.DefaultName())
+
+	selected, err := clusters.Resolve(selector)
+	if err != nil {
+		respondClusterError(c, selector, err)
+		return nil, false
+	}
+	if len(selected) == 0 {
+		models.RespondClusterUnavailable(c, selector, services.ErrClusterUnavailable)
+		return nil, false
+	}
+	return selected, true
+}
+
+// resolveCluster returns the single cluster selected by the "cluster" query parameter
+func resolveCluster(c *gin.Context, clusters *services.ClusterManager) (*services.Cluster, bool) {
+	selector := c.DefaultQuery("cluster", clusters.DefaultName())
+	if selector == services.AllClusters {
+		models.RespondBadRequest(c, "A single cluster must be selected",
+			"The 'all' cluster selector is only supported on list endpoints")
+		return nil, false
+	}
+
+	cluster, err := clusters.Get(selector)
+	if err != nil {
+		respondClusterError(c, selector, err)
+		return nil, false
+	}
+	return cluster, true
+}
+
+// respondClusterError maps cluster registry errors to API responses
+func respondClusterError(c *gin.Context, selector string, err error) {
+	if errors.Is(err, services.ErrClusterNotFound) {
+		models.RespondClusterNotFound(c, selector)
+		return
+	}
+	models.RespondClusterUnavailable(c, selector, err)
+}
+
+// forEachCluster runs fetch against every selected cluster. When a single cluster is
+// selected its error is returned as-is; when aggregating, failing clusters are logged
+// and reported in the returned map so the remaining clusters can still be served.
+func forEachCluster(clusters []*services.Cluster, logger *logrus.Entry, fetch func(cluster *services.Cluster) error) (map[string]string, error) {
+	if len(clusters) == 1 {
+		return nil, fetch(clusters[0])
+	}
+
+	var clusterErrors map[string]string
+	var firstErr error
+	for _, cluster := range clusters {
+		if err := fetch(cluster); err != nil {
+			logger.WithError(err).WithField("cluster", cluster.Name).Warn("Failed to fetch from cluster")
+			if clusterErrors == nil {
+				clusterErrors = make(map[string]string)
+				firstErr = err
+			}
+			clusterErrors[cluster.Name] = err.Error()
+		}
+	}
+
+	// Fail only when no cluster could be served
+	if len(clusterErrors) == len(clusters) {
+		return clusterErrors, firstErr
+	}
+	return clusterErrors, nil
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"k8s-monitor/internal/models"
+	"k8s-monitor/internal/services"
+)
+
+// respondError maps an error from the services layer to an API response. Errors of the
+// services are reported by their sentinel, and errors of the Kubernetes API by their
+// status, so that a cluster's 403, 404, 409, 429 and timeouts keep their meaning.
+func respondError(c *gin.Context, operation string, err error) {
+	switch {
+	case errors.Is(err, services.ErrNamespaceNotAllowed):
+		models.RespondError(c, http.StatusForbidden, models.ErrCodeForbidden, "Access to namespace not allowed", err.Error())
+	case errors.Is(err, services.ErrApplicationNotFound):
+		models.RespondError(c, http.StatusNotFound, models.ErrCodeResourceNotFound, "Application not found", err.Error())
+	case errors.Is(err, services.ErrContainerNotFound):
+		models.RespondError(c, http.StatusNotFound, models.ErrCodeResourceNotFound, "Container not found", err.Error())
+	default:
+		models.RespondKubernetesError(c, operation, err)
+	}
+}
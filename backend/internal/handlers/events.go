@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"k8s-monitor/internal/models"
+	"k8s-monitor/internal/services"
+	"k8s-monitor/pkg/utils"
+)
+
+// GetEvents retrieves the event timeline of a pod
+// @Summary Get pod events
+// @Description Get the Kubernetes events of a pod, deduplicated by reason with counts and first/last seen times, ordered as a timeline
+// @Tags pods
+// @Accept json
+// @Produce json
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Pod name"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} models.EventsResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/v1/pods/{namespace}/{name}/events [get]
+func (h *PodHandler) GetEvents(c *gin.Context) {
+	namespace := c.Param("namespace")
+	podName := c.Param("name")
+
+	if namespace == "" || podName == "" {
+		models.RespondBadRequest(c, "Namespace and pod name are required", "")
+		return
+	}
+
+	cluster, ok := resolveCluster(c, h.clusters)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	logger := utils.WithPod(h.logger, namespace, podName)
+
+	// Check if namespace is allowed
+	if !cluster.K8s.IsNamespaceAllowed(namespace) {
+		models.RespondNamespaceForbidden(c, namespace)
+		return
+	}
+
+	logger.Info("Fetching pod events")
+
+	// Events of deleted pods are still served, they usually explain the deletion
+	response, err := services.GetPodEvents(ctx, cluster.K8s, namespace, podName)
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch pod events")
+		models.RespondKubernetesError(c, "get pod events", err)
+		return
+	}
+
+	logger.WithField("total", response.Total).Info("Successfully fetched pod events")
+	models.RespondSuccess(c, response)
+}
+
+// GetEvents retrieves the event timeline of an application
+// @Summary Get application events
+// @Description Get the Kubernetes events of an application's pods, their owning ReplicaSets, Deployments and StatefulSets, and its services, deduplicated by reason with counts and first/last seen times, ordered as a timeline
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Application name"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} models.EventsResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/v1/applications/{namespace}/{name}/events [get]
+func (h *ApplicationHandler) GetEvents(c *gin.Context) {
+	namespace := c.Param("namespace")
+	appName := c.Param("name")
+
+	if namespace == "" || appName == "" {
+		models.RespondBadRequest(c, "Namespace and application name are required", "")
+		return
+	}
+
+	cluster, ok := resolveCluster(c, h.clusters)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	logger := utils.WithApplication(h.logger, namespace, appName)
+
+	// Check if namespace is allowed
+	if !cluster.K8s.IsNamespaceAllowed(namespace) {
+		models.RespondNamespaceForbidden(c, namespace)
+		return
+	}
+
+	logger.Info("Fetching application events")
+
+	response, err := cluster.Applications.GetApplicationEvents(ctx, namespace, appName)
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch application events")
+		respondError(c, "get application events", err)
+		return
+	}
+
+	logger.WithField("total", response.Total).Info("Successfully fetched application events")
+	models.RespondSuccess(c, response)
+}
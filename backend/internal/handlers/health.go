@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -20,15 +22,15 @@ var (
 
 // HealthHandler handles health check requests
 type HealthHandler struct {
-	k8sService *services.KubernetesService
-	logger     *logrus.Logger
+	clusters *services.ClusterManager
+	logger   *logrus.Logger
 }
 
 // NewHealthHandler creates a new health handler instance
-func NewHealthHandler(k8sService *services.KubernetesService, logger *logrus.Logger) *HealthHandler {
+func NewHealthHandler(clusters *services.ClusterManager, logger *logrus.Logger) *HealthHandler {
 	return &HealthHandler{
-		k8sService: k8sService,
-		logger:     logger,
+		clusters: clusters,
+		logger:   logger,
 	}
 }
 
@@ -55,15 +57,38 @@ func (h *HealthHandler) Check(c *gin.Context) {
 		Timestamp: time.Now(),
 	}
 
-	// Perform Kubernetes connectivity check
+	// Perform Kubernetes connectivity check for every cluster; only the
+	// default cluster determines the overall status
 	k8sStatus := "healthy"
-	if err := h.k8sService.HealthCheck(); err != nil {
-		h.logger.WithError(err).Error("Kubernetes health check failed")
-		k8sStatus = "unhealthy"
-		response.Status = "unhealthy"
-		response.Checks["kubernetes"] = fmt.Sprintf("Failed: %v", err)
-	} else {
-		response.Checks["kubernetes"] = "Connected"
+	for _, cluster := range h.clusters.Status() {
+		checkName := "cluster:" + cluster.Name
+		if cluster.Default {
+			checkName = "kubernetes"
+		}
+
+		if cluster.Status != models.ClusterStatusConnected {
+			h.logger.WithFields(logrus.Fields{"cluster": cluster.Name, "error": cluster.Error}).
+				Error("Kubernetes health check failed")
+			response.Checks[checkName] = fmt.Sprintf("Failed: %s", cluster.Error)
+			if cluster.Default {
+				k8sStatus = "unhealthy"
+				response.Status = "unhealthy"
+			}
+			continue
+		}
+		response.Checks[checkName] = "Connected"
+
+		if !cluster.Default {
+			continue
+		}
+
+		// Report informer cache sync state
+		if cluster.Cache == nil {
+			response.Checks["cache"] = "Disabled"
+		} else {
+			response.Cache = cluster.Cache
+			response.Checks["cache"] = formatCacheStatus(cluster.Cache)
+		}
 	}
 	response.KubernetesStatus = k8sStatus
 
@@ -86,6 +111,23 @@ func (h *HealthHandler) Check(c *gin.Context) {
 	}
 }
 
+// formatCacheStatus summarizes informer sync state into a readable string
+func formatCacheStatus(status map[string]bool) string {
+	var pending []string
+	for resource, synced := range status {
+		if !synced {
+			pending = append(pending, resource)
+		}
+	}
+
+	if len(pending) == 0 {
+		return "Synced"
+	}
+
+	sort.Strings(pending)
+	return "Syncing: " + strings.Join(pending, ", ")
+}
+
 // formatUptime formats uptime duration into a readable string
 func formatUptime(d time.Duration) string {
 	days := int(d.Hours()) / 24
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/history"
+	"k8s-monitor/internal/models"
+	"k8s-monitor/internal/services"
+	"k8s-monitor/internal/storage"
+	"k8s-monitor/pkg/utils"
+)
+
+// defaultHistoryRange is the range returned when neither range nor from is given
+const defaultHistoryRange = 24 * time.Hour
+
+// HistoryHandler handles application health history requests
+type HistoryHandler struct {
+	history  *history.Service
+	clusters *services.ClusterManager
+	logger   *logrus.Logger
+}
+
+// NewHistoryHandler creates a new history handler instance
+func NewHistoryHandler(history *history.Service, clusters *services.ClusterManager, logger *logrus.Logger) *HistoryHandler {
+	return &HistoryHandler{
+		history:  history,
+		clusters: clusters,
+		logger:   logger,
+	}
+}
+
+// GetHistory retrieves the health history and uptime of an application
+// @Summary Get application history
+// @Description Get the recorded status of an application as a time series, downsampled for long ranges, with uptime percentages over windows ending now
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Application name"
+// @Param cluster query string false "Cluster name"
+// @Param range query string false "Range ending at 'to', such as 6h or 7d (default 24h)"
+// @Param from query string false "Start of the range (RFC3339), overrides range"
+// @Param to query string false "End of the range (RFC3339, default now)"
+// @Param step query string false "Minimum step between points, such as 5m or 1h"
+// @Param windows query string false "Comma-separated uptime windows, such as 24h,7d,30d"
+// @Success 200 {object} models.ApplicationHistoryResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/v1/applications/{namespace}/{name}/history [get]
+func (h *HistoryHandler) GetHistory(c *gin.Context) {
+	namespace := c.Param("namespace")
+	appName := c.Param("name")
+
+	if namespace == "" || appName == "" {
+		models.RespondBadRequest(c, "Namespace and application name are required", "")
+		return
+	}
+
+	// History outlives cluster outages, so the cluster only has to be known, not available
+	cluster := c.DefaultQuery("cluster", h.clusters.DefaultName())
+	if cluster == services.AllClusters {
+		models.RespondBadRequest(c, "A single cluster must be selected",
+			"The 'all' cluster selector is only supported on list endpoints")
+		return
+	}
+
+	query, err := parseHistoryQuery(c, time.Now())
+	if err != nil {
+		models.RespondBadRequest(c, "Invalid history query", err.Error())
+		return
+	}
+
+	logger := utils.WithApplication(h.logger, namespace, appName).WithField("cluster", cluster)
+
+	key := storage.ApplicationKey{Cluster: cluster, Namespace: namespace, Name: appName}
+	response, err := h.history.ApplicationHistory(c.Request.Context(), key, query, time.Now())
+	if err != nil {
+		logger.WithError(err).Error("Failed to read application history")
+		models.RespondInternalError(c, "Failed to read application history", err.Error())
+		return
+	}
+
+	logger.WithField("points", len(response.Points)).Debug("Fetched application history")
+	models.RespondSuccess(c, response)
+}
+
+// parseHistoryQuery reads the range, step and windows query parameters
+func parseHistoryQuery(c *gin.Context, now time.Time) (history.Query, error) {
+	query := history.Query{To: now}
+
+	if value := c.Query("to"); value != "" {
+		to, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return query, fmt.Errorf("parameter 'to' must be an RFC3339 timestamp")
+		}
+		query.To = to
+	}
+
+	span := defaultHistoryRange
+	if value := c.Query("range"); value != "" {
+		window, err := history.ParseWindow(value)
+		if err != nil {
+			return query, err
+		}
+		span = window.Duration
+	}
+	query.From = query.To.Add(-span)
+
+	if value := c.Query("from"); value != "" {
+		from, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return query, fmt.Errorf("parameter 'from' must be an RFC3339 timestamp")
+		}
+		query.From = from
+	}
+	if !query.From.Before(query.To) {
+		return query, fmt.Errorf("parameter 'from' must be before 'to'")
+	}
+
+	if value := c.Query("step"); value != "" {
+		step, err := history.ParseWindow(value)
+		if err != nil {
+			return query, err
+		}
+		query.Step = step.Duration
+	}
+
+	if value := c.Query("windows"); value != "" {
+		windows, err := history.ParseWindows(strings.Split(value, ","))
+		if err != nil {
+			return query, err
+		}
+		query.Windows = windows
+	}
+
+	return query, nil
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/incidents"
+	"k8s-monitor/internal/models"
+	"k8s-monitor/internal/storage"
+	"k8s-monitor/pkg/utils"
+)
+
+// IncidentHandler handles incident-related HTTP requests
+type IncidentHandler struct {
+	tracker *incidents.Tracker
+	logger  *logrus.Logger
+}
+
+// NewIncidentHandler creates a new incident handler instance
+func NewIncidentHandler(tracker *incidents.Tracker, logger *logrus.Logger) *IncidentHandler {
+	return &IncidentHandler{
+		tracker: tracker,
+		logger:  logger,
+	}
+}
+
+// List retrieves recorded incidents
+// @Summary List incidents
+// @Description Get the incidents opened when an application left the healthy status, most recent first
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Param namespace query string false "Only return incidents for this namespace"
+// @Param app query string false "Only return incidents for this application"
+// @Param cluster query string false "Only return incidents for this cluster"
+// @Param status query string false "Only return open or closed incidents" Enums(open, closed)
+// @Success 200 {object} models.IncidentListResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/v1/incidents [get]
+func (h *IncidentHandler) List(c *gin.Context) {
+	filter := storage.IncidentFilter{
+		Cluster:     c.Query("cluster"),
+		Namespace:   c.Query("namespace"),
+		Application: c.Query("app"),
+		Status:      models.IncidentStatus(c.Query("status")),
+	}
+	if filter.Status != "" && filter.Status != models.IncidentOpen && filter.Status != models.IncidentClosed {
+		models.RespondBadRequest(c, "Invalid incident status", "Parameter 'status' must be 'open' or 'closed'")
+		return
+	}
+
+	logger := utils.WithComponent(h.logger, "incident-handler")
+
+	incidentList, err := h.tracker.List(c.Request.Context(), filter)
+	if err != nil {
+		logger.WithError(err).Error("Failed to list incidents")
+		models.RespondInternalError(c, "Failed to list incidents", err.Error())
+		return
+	}
+
+	response := models.IncidentListResponse{Incidents: incidentList}
+	response.Total = len(response.Incidents)
+	for _, incident := range response.Incidents {
+		if incident.Status == models.IncidentOpen {
+			response.Open++
+		}
+	}
+
+	logger.WithField("total", response.Total).Debug("Fetched incidents")
+	models.RespondSuccess(c, response)
+}
+
+// GetIncident retrieves one incident
+// @Summary Get incident
+// @Description Get one incident with its status updates, affected pods and related events
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Param id path string true "Incident ID"
+// @Success 200 {object} models.Incident
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/v1/incidents/{id} [get]
+func (h *IncidentHandler) GetIncident(c *gin.Context) {
+	id := c.Param("id")
+
+	incident, err := h.tracker.Get(c.Request.Context(), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		models.RespondNotFound(c, "Incident not found", "No incident with ID '"+id+"'")
+		return
+	}
+	if err != nil {
+		utils.WithComponent(h.logger, "incident-handler").WithError(err).Error("Failed to get incident")
+		models.RespondInternalError(c, "Failed to get incident", err.Error())
+		return
+	}
+
+	models.RespondSuccess(c, incident)
+}
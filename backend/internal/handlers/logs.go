@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/models"
+	"k8s-monitor/internal/services"
+	"k8s-monitor/pkg/utils"
+)
+
+const (
+	// defaultLogTailLines applies when neither tailLines nor sinceSeconds is given
+	defaultLogTailLines int64 = 1000
+	// maxLogBytes bounds non-follow log responses
+	maxLogBytes int64 = 10 * 1024 * 1024
+)
+
+// GetLogs retrieves or follows the logs of a pod container
+// @Summary Get pod logs
+// @Description Get the logs of a pod container. With follow=true the logs are streamed as chunked plain text, or as server-sent events when the client accepts text/event-stream or passes format=sse.
+// @Tags pods
+// @Produce json
+// @Produce plain
+// @Produce text/event-stream
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Pod name"
+// @Param container query string false "Container name, defaults to the pod's default container"
+// @Param tailLines query integer false "Number of lines from the end of the logs"
+// @Param sinceSeconds query integer false "Only return logs newer than this many seconds"
+// @Param previous query boolean false "Return logs of the previous terminated container instance"
+// @Param timestamps query boolean false "Include timestamps"
+// @Param follow query boolean false "Stream new log lines as they are written"
+// @Param format query string false "Set to 'sse' to stream server-sent events"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} models.PodLogsResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/v1/pods/{namespace}/{name}/logs [get]
+func (h *PodHandler) GetLogs(c *gin.Context) {
+	namespace := c.Param("namespace")
+	podName := c.Param("name")
+
+	if namespace == "" || podName == "" {
+		models.RespondBadRequest(c, "Namespace and pod name are required", "")
+		return
+	}
+
+	cluster, ok := resolveCluster(c, h.clusters)
+	if !ok {
+		return
+	}
+
+	opts, err := parseLogOptions(c)
+	if err != nil {
+		models.RespondValidationError(c, err.Error())
+		return
+	}
+
+	logger := utils.WithPod(h.logger, namespace, podName).WithField("container", opts.Container)
+
+	// Check if namespace is allowed
+	if !cluster.K8s.IsNamespaceAllowed(namespace) {
+		models.RespondNamespaceForbidden(c, namespace)
+		return
+	}
+
+	if opts.Follow {
+		h.followLogs(c, cluster, namespace, podName, opts, logger)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	logger.Info("Fetching pod logs")
+
+	stream, container, err := services.OpenPodLogs(ctx, cluster.K8s, namespace, podName, opts)
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch pod logs")
+		respondError(c, "get pod logs", err)
+		return
+	}
+	defer stream.Close()
+
+	lines := []models.LogLine{}
+	err = services.ReadLogLines(stream, opts.Timestamps, func(line models.LogLine) error {
+		lines = append(lines, line)
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to read pod logs")
+		models.RespondKubernetesError(c, "read pod logs", err)
+		return
+	}
+
+	response := models.PodLogsResponse{
+		Pod:       podName,
+		Namespace: namespace,
+		Cluster:   cluster.Name,
+		Container: container,
+		Previous:  opts.Previous,
+		Lines:     lines,
+		Total:     len(lines),
+	}
+
+	logger.WithField("total", len(lines)).Info("Successfully fetched pod logs")
+	models.RespondSuccess(c, response)
+}
+
+// followLogs streams new log lines until the client disconnects or the container stops
+func (h *PodHandler) followLogs(c *gin.Context, cluster *services.Cluster, namespace, podName string, opts services.LogOptions, logger *logrus.Entry) {
+	ctx := c.Request.Context()
+
+	stream, container, err := services.OpenPodLogs(ctx, cluster.K8s, namespace, podName, opts)
+	if err != nil {
+		logger.WithError(err).Error("Failed to follow pod logs")
+		respondError(c, "get pod logs", err)
+		return
+	}
+	defer stream.Close()
+
+	logger = logger.WithField("container", container)
+	logger.Info("Following pod logs")
+
+	err = writeLogStream(c, func(fn func(models.LogLine) error) error {
+		return services.ReadLogLines(stream, opts.Timestamps, fn)
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Pod log stream ended with error")
+	}
+
+	logger.Info("Stopped following pod logs")
+}
+
+// GetLogs retrieves or follows the merged logs of every pod and container of an application
+// @Summary Get application logs
+// @Description Merge the logs of all pods and containers grouped under an application. Each line carries its pod and container. Lines can be filtered server-side by substring and regular expression. Without follow the lines are ordered by timestamp; with follow=true they are streamed as chunked plain text prefixed with [pod/container], or as server-sent events when the client accepts text/event-stream or passes format=sse.
+// @Tags applications
+// @Produce json
+// @Produce plain
+// @Produce text/event-stream
+// @Param namespace path string true "Namespace name"
+// @Param name path string true "Application name"
+// @Param container query string false "Only include containers with this name"
+// @Param tailLines query integer false "Number of lines from the end of each container's logs"
+// @Param sinceSeconds query integer false "Only return logs newer than this many seconds"
+// @Param previous query boolean false "Return logs of the previous terminated container instances"
+// @Param timestamps query boolean false "Include timestamps"
+// @Param follow query boolean false "Stream new log lines as they are written"
+// @Param contains query string false "Only return lines containing this substring"
+// @Param regex query string false "Only return lines matching this regular expression"
+// @Param format query string false "Set to 'sse' to stream server-sent events"
+// @Param cluster query string false "Cluster name"
+// @Success 200 {object} models.ApplicationLogsResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/v1/applications/{namespace}/{name}/logs [get]
+func (h *ApplicationHandler) GetLogs(c *gin.Context) {
+	namespace := c.Param("namespace")
+	appName := c.Param("name")
+
+	if namespace == "" || appName == "" {
+		models.RespondBadRequest(c, "Namespace and application name are required", "")
+		return
+	}
+
+	cluster, ok := resolveCluster(c, h.clusters)
+	if !ok {
+		return
+	}
+
+	opts, err := parseLogOptions(c)
+	if err != nil {
+		models.RespondValidationError(c, err.Error())
+		return
+	}
+
+	filter, err := parseLogFilter(c)
+	if err != nil {
+		models.RespondValidationError(c, err.Error())
+		return
+	}
+
+	logger := utils.WithApplication(h.logger, namespace, appName).WithField("container", opts.Container)
+
+	// Check if namespace is allowed
+	if !cluster.K8s.IsNamespaceAllowed(namespace) {
+		models.RespondNamespaceForbidden(c, namespace)
+		return
+	}
+
+	lookupCtx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	sources, err := cluster.Applications.GetApplicationLogSources(lookupCtx, namespace, appName, opts.Container)
+	cancel()
+	if err != nil {
+		logger.WithError(err).Error("Failed to resolve application pods")
+		respondError(c, "get application pods", err)
+		return
+	}
+
+	logger = logger.WithField("streams", len(sources))
+
+	// Applications scaled to zero have no logs to read
+	if len(sources) == 0 && !opts.Follow {
+		logger.Info("Application has no pods")
+		models.RespondSuccess(c, models.ApplicationLogsResponse{
+			Application: appName,
+			Namespace:   namespace,
+			Cluster:     cluster.Name,
+			Sources:     []models.LogSource{},
+			Lines:       []models.LogLine{},
+		})
+		return
+	}
+
+	if opts.Follow {
+		logger.Info("Following application logs")
+		err = writeLogStream(c, func(fn func(models.LogLine) error) error {
+			_, err := services.StreamLogs(c.Request.Context(), cluster.K8s, namespace, sources, opts, filter, fn)
+			return err
+		})
+		if err != nil {
+			logger.WithError(err).Warn("Application log stream ended with error")
+		}
+		logger.Info("Stopped following application logs")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	logger.Info("Fetching application logs")
+
+	// Timestamps are needed to merge the streams in order
+	includeTimestamps := opts.Timestamps
+	opts.Timestamps = true
+	limit := max(maxLogBytes/int64(len(sources)), 64*1024)
+	opts.LimitBytes = &limit
+
+	lines := []models.LogLine{}
+	sourceErrors, err := services.StreamLogs(ctx, cluster.K8s, namespace, sources, opts, filter, func(line models.LogLine) error {
+		lines = append(lines, line)
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch application logs")
+		models.RespondKubernetesError(c, "get application logs", err)
+		return
+	}
+
+	sortLogLines(lines)
+	if !includeTimestamps {
+		for i := range lines {
+			lines[i].Timestamp = nil
+		}
+	}
+
+	response := models.ApplicationLogsResponse{
+		Application: appName,
+		Namespace:   namespace,
+		Cluster:     cluster.Name,
+		Sources:     sources,
+		Lines:       lines,
+		Total:       len(lines),
+		Errors:      sourceErrors,
+	}
+
+	logger.WithField("total", len(lines)).Info("Successfully fetched application logs")
+	models.RespondSuccess(c, response)
+}
+
+// sortLogLines merges the lines of several streams by timestamp. Lines without a
+// timestamp go last, in the order they were read.
+func sortLogLines(lines []models.LogLine) {
+	sort.SliceStable(lines, func(i, j int) bool {
+		a, b := lines[i].Timestamp, lines[j].Timestamp
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return a.Before(*b)
+	})
+}
+
+// writeLogStream starts a streaming response and writes every line produced by read,
+// as server-sent events when requested and as chunked plain text otherwise
+func writeLogStream(c *gin.Context, read func(fn func(models.LogLine) error) error) error {
+	useSSE := wantsEventStream(c)
+	if useSSE {
+		startStream(c, "text/event-stream")
+	} else {
+		startStream(c, "text/plain; charset=utf-8")
+	}
+
+	var sequence uint64
+	return read(func(line models.LogLine) error {
+		if useSSE {
+			sequence++
+			c.Render(-1, sse.Event{
+				Id:    strconv.FormatUint(sequence, 10),
+				Event: "log",
+				Data:  line,
+			})
+		} else if _, err := io.WriteString(c.Writer, line.String()+"\n"); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return c.Request.Context().Err()
+	})
+}
+
+// parseLogOptions reads log options from the query string
+func parseLogOptions(c *gin.Context) (services.LogOptions, error) {
+	opts := services.LogOptions{Container: c.Query("container")}
+
+	var err error
+	if opts.TailLines, err = queryPositiveInt64(c, "tailLines"); err != nil {
+		return opts, err
+	}
+	if opts.SinceSeconds, err = queryPositiveInt64(c, "sinceSeconds"); err != nil {
+		return opts, err
+	}
+	if opts.Previous, err = queryBool(c, "previous"); err != nil {
+		return opts, err
+	}
+	if opts.Timestamps, err = queryBool(c, "timestamps"); err != nil {
+		return opts, err
+	}
+	if opts.Follow, err = queryBool(c, "follow"); err != nil {
+		return opts, err
+	}
+
+	if opts.TailLines == nil && opts.SinceSeconds == nil {
+		tail := defaultLogTailLines
+		opts.TailLines = &tail
+	}
+	if !opts.Follow {
+		limit := maxLogBytes
+		opts.LimitBytes = &limit
+	}
+
+	return opts, nil
+}
+
+// parseLogFilter reads the contains and regex filters from the query string
+func parseLogFilter(c *gin.Context) (services.LogFilter, error) {
+	filter := services.LogFilter{Contains: c.Query("contains")}
+
+	if expr := c.Query("regex"); expr != "" {
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return filter, fmt.Errorf("invalid regex: %w", err)
+		}
+		filter.Pattern = pattern
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s-monitor/internal/models"
+)
+
+func TestSortLogLines(t *testing.T) {
+	at := func(seconds int) *time.Time {
+		ts := time.Date(2025, 1, 1, 0, 0, seconds, 0, time.UTC)
+		return &ts
+	}
+	lines := []models.LogLine{
+		{Message: "untimed-1"},
+		{Timestamp: at(3), Message: "c"},
+		{Timestamp: at(1), Message: "a"},
+		{Message: "untimed-2"},
+		{Timestamp: at(2), Message: "b"},
+	}
+
+	sortLogLines(lines)
+
+	var got []string
+	for _, line := range lines {
+		got = append(got, line.Message)
+	}
+	if want := "a,b,c,untimed-1,untimed-2"; strings.Join(got, ",") != want {
+		t.Errorf("sorted lines = %v, want %s", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/models"
+	"k8s-monitor/internal/services"
+	"k8s-monitor/pkg/utils"
+)
+
+// NodeHandler handles node-related HTTP requests
+type NodeHandler struct {
+	clusters *services.ClusterManager
+	logger   *logrus.Logger
+}
+
+// NewNodeHandler creates a new node handler instance
+func NewNodeHandler(clusters *services.ClusterManager, logger *logrus.Logger) *NodeHandler {
+	return &NodeHandler{
+		clusters: clusters,
+		logger:   logger,
+	}
+}
+
+// List retrieves all cluster nodes with their resource usage
+// @Summary List nodes
+// @Description Get the cluster nodes with capacity, CPU and memory usage from metrics-server, and the requests and limits of the pods scheduled on them. metricsAvailable is false when metrics-server is not installed.
+// @Tags nodes
+// @Accept json
+// @Produce json
+// @Param cluster query string false "Cluster name, or 'all' to aggregate every cluster"
+// @Success 200 {object} models.NodeListResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/v1/nodes [get]
+func (h *NodeHandler) List(c *gin.Context) {
+	clusters, ok := resolveClusters(c, h.clusters)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	logger := utils.WithComponent(h.logger, "node-handler")
+	logger.Info("Fetching nodes")
+
+	response := models.NodeListResponse{
+		Cluster:          c.Query("cluster"),
+		MetricsAvailable: true,
+	}
+
+	clusterErrors, err := forEachCluster(clusters, logger, func(cluster *services.Cluster) error {
+		nodes, err := services.GetNodes(ctx, cluster.K8s, logger.WithField("cluster", cluster.Name))
+		if err != nil {
+			return err
+		}
+		response.Nodes = append(response.Nodes, nodes.Nodes...)
+		response.MetricsAvailable = response.MetricsAvailable && nodes.MetricsAvailable
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch nodes")
+		models.RespondKubernetesError(c, "list nodes", err)
+		return
+	}
+
+	response.Total = len(response.Nodes)
+	response.ClusterErrors = clusterErrors
+
+	logger.WithField("total", response.Total).Info("Successfully fetched nodes")
+	models.RespondSuccess(c, response)
+}
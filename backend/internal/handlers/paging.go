@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"k8s-monitor/internal/models"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// sortKeys maps the values of the "sort" parameter of a list endpoint to comparisons
+type sortKeys[T any] map[string]func(a, b T) int
+
+// names returns the sorted sort keys, for error messages
+func (k sortKeys[T]) names() string {
+	names := make([]string, 0, len(k))
+	for name := range k {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// pageQuery holds the paging and sorting parameters of a list endpoint. Lists are only
+// paged when page or pageSize is set, and keep their natural order unless sort is set.
+type pageQuery struct {
+	page     int
+	pageSize int
+	sort     string
+	desc     bool
+}
+
+// paged reports whether a page was requested
+func (q pageQuery) paged() bool {
+	return q.pageSize > 0
+}
+
+// parsePageQuery parses the page, pageSize, sort and order parameters
+func parsePageQuery[T any](c *gin.Context, keys sortKeys[T]) (pageQuery, error) {
+	var q pageQuery
+
+	page, err := queryPositiveInt(c, "page")
+	if err != nil {
+		return q, err
+	}
+	pageSize, err := queryPositiveInt(c, "pageSize")
+	if err != nil {
+		return q, err
+	}
+	if pageSize > maxPageSize {
+		return q, fmt.Errorf("parameter 'pageSize' must be at most %d", maxPageSize)
+	}
+	if page > 0 || pageSize > 0 {
+		q.page = max(page, 1)
+		q.pageSize = cmp.Or(pageSize, defaultPageSize)
+	}
+
+	q.sort = c.Query("sort")
+	if _, ok := keys[q.sort]; q.sort != "" && !ok {
+		return q, fmt.Errorf("parameter 'sort' must be one of %s", keys.names())
+	}
+
+	switch order := strings.ToLower(c.Query("order")); order {
+	case "", "asc":
+	case "desc":
+		q.desc = true
+	default:
+		return q, fmt.Errorf("parameter 'order' must be asc or desc")
+	}
+
+	return q, nil
+}
+
+// paginate sorts items as requested and returns the requested page, with the metadata
+// describing it. Sorting is stable, so ties keep their natural order.
+func paginate[T any](items []T, q pageQuery, keys sortKeys[T]) ([]T, *models.Meta) {
+	if compare, ok := keys[q.sort]; ok {
+		slices.SortStableFunc(items, func(a, b T) int {
+			if q.desc {
+				return compare(b, a)
+			}
+			return compare(a, b)
+		})
+	}
+
+	meta := &models.Meta{Total: len(items), Sort: q.sort}
+	if q.sort != "" {
+		meta.Order = "asc"
+		if q.desc {
+			meta.Order = "desc"
+		}
+	}
+	if !q.paged() {
+		return items, meta
+	}
+
+	meta.Page = q.page
+	meta.PageSize = q.pageSize
+	meta.TotalPages = (len(items) + q.pageSize - 1) / q.pageSize
+
+	start := min((q.page-1)*q.pageSize, len(items))
+	end := min(start+q.pageSize, len(items))
+	return items[start:end], meta
+}
+
+// queryPositiveInt parses an optional positive integer query parameter, returning 0 when absent
+func queryPositiveInt(c *gin.Context, name string) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		return 0, fmt.Errorf("parameter '%s' must be a positive integer", name)
+	}
+	return parsed, nil
+}
+
+// Sort keys of the list endpoints
+var (
+	podSortKeys = sortKeys[models.PodStatus]{
+		"name":      func(a, b models.PodStatus) int { return strings.Compare(a.Name, b.Name) },
+		"namespace": func(a, b models.PodStatus) int { return strings.Compare(a.Namespace, b.Namespace) },
+		"cluster":   func(a, b models.PodStatus) int { return strings.Compare(a.Cluster, b.Cluster) },
+		"status":    func(a, b models.PodStatus) int { return strings.Compare(a.Status, b.Status) },
+		"node":      func(a, b models.PodStatus) int { return strings.Compare(a.Node, b.Node) },
+		"restarts":  func(a, b models.PodStatus) int { return cmp.Compare(a.Restarts, b.Restarts) },
+		"age":       func(a, b models.PodStatus) int { return b.CreatedAt.Compare(a.CreatedAt) },
+	}
+
+	applicationSortKeys = sortKeys[models.Application]{
+		"name":      func(a, b models.Application) int { return strings.Compare(a.Name, b.Name) },
+		"namespace": func(a, b models.Application) int { return strings.Compare(a.Namespace, b.Namespace) },
+		"cluster":   func(a, b models.Application) int { return strings.Compare(a.Cluster, b.Cluster) },
+		"status": func(a, b models.Application) int {
+			return cmp.Compare(models.ApplicationStatus(a.Status).Rank(), models.ApplicationStatus(b.Status).Rank())
+		},
+		"type":     func(a, b models.Application) int { return strings.Compare(a.Type, b.Type) },
+		"pods":     func(a, b models.Application) int { return cmp.Compare(a.Summary.TotalPods, b.Summary.TotalPods) },
+		"restarts": func(a, b models.Application) int { return cmp.Compare(a.Summary.RestartCount, b.Summary.RestartCount) },
+		"age":      func(a, b models.Application) int { return b.CreatedAt.Compare(a.CreatedAt) },
+	}
+
+	namespaceSortKeys = sortKeys[models.NamespaceInfo]{
+		"name":    func(a, b models.NamespaceInfo) int { return strings.Compare(a.Name, b.Name) },
+		"cluster": func(a, b models.NamespaceInfo) int { return strings.Compare(a.Cluster, b.Cluster) },
+		"status":  func(a, b models.NamespaceInfo) int { return strings.Compare(a.Status, b.Status) },
+		"pods":    func(a, b models.NamespaceInfo) int { return cmp.Compare(a.PodCount, b.PodCount) },
+		"age":     func(a, b models.NamespaceInfo) int { return b.CreatedAt.Compare(a.CreatedAt) },
+	}
+
+	argoCDSortKeys = sortKeys[models.ArgoCDApplication]{
+		"name":      func(a, b models.ArgoCDApplication) int { return strings.Compare(a.Name, b.Name) },
+		"namespace": func(a, b models.ArgoCDApplication) int { return strings.Compare(a.Namespace, b.Namespace) },
+		"cluster":   func(a, b models.ArgoCDApplication) int { return strings.Compare(a.Cluster, b.Cluster) },
+		"status":    func(a, b models.ArgoCDApplication) int { return strings.Compare(a.Status, b.Status) },
+		"sync":      func(a, b models.ArgoCDApplication) int { return strings.Compare(a.SyncStatus, b.SyncStatus) },
+		"health":    func(a, b models.ArgoCDApplication) int { return strings.Compare(a.HealthStatus, b.HealthStatus) },
+		"age":       func(a, b models.ArgoCDApplication) int { return b.CreatedAt.Compare(a.CreatedAt) },
+	}
+)
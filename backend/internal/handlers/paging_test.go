@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"k8s-monitor/internal/models"
+)
+
+func TestPaginate(t *testing.T) {
+	pods := []models.PodStatus{
+		{Name: "web-1", Restarts: 4},
+		{Name: "api-1", Restarts: 0},
+		{Name: "api-2", Restarts: 4},
+		{Name: "db-0", Restarts: 1},
+		{Name: "cache-0", Restarts: 2},
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    []string
+		meta    models.Meta
+		wantErr bool
+	}{
+		{name: "natural order", want: []string{"web-1", "api-1", "api-2", "db-0", "cache-0"}, meta: models.Meta{Total: 5}},
+		{name: "sorted", query: "sort=name", want: []string{"api-1", "api-2", "cache-0", "db-0", "web-1"}, meta: models.Meta{Total: 5, Sort: "name", Order: "asc"}},
+		{name: "stable descending", query: "sort=restarts&order=desc", want: []string{"web-1", "api-2", "cache-0", "db-0", "api-1"}, meta: models.Meta{Total: 5, Sort: "restarts", Order: "desc"}},
+		{name: "page", query: "sort=name&page=2&pageSize=2", want: []string{"cache-0", "db-0"}, meta: models.Meta{Total: 5, Page: 2, PageSize: 2, TotalPages: 3, Sort: "name", Order: "asc"}},
+		{name: "last page", query: "page=3&pageSize=2", want: []string{"cache-0"}, meta: models.Meta{Total: 5, Page: 3, PageSize: 2, TotalPages: 3}},
+		{name: "past the end", query: "page=9&pageSize=2", want: []string{}, meta: models.Meta{Total: 5, Page: 9, PageSize: 2, TotalPages: 3}},
+		{name: "default page size", query: "page=1", want: []string{"web-1", "api-1", "api-2", "db-0", "cache-0"}, meta: models.Meta{Total: 5, Page: 1, PageSize: defaultPageSize, TotalPages: 1}},
+		{name: "unknown sort key", query: "sort=memory", wantErr: true},
+		{name: "invalid order", query: "sort=name&order=up", wantErr: true},
+		{name: "invalid page", query: "page=0", wantErr: true},
+		{name: "page size too large", query: "pageSize=1000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil)
+
+			q, err := parsePageQuery(c, podSortKeys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePageQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			page, meta := paginate(append([]models.PodStatus(nil), pods...), q, podSortKeys)
+			if len(page) != len(tt.want) {
+				t.Fatalf("got %d pods, want %v", len(page), tt.want)
+			}
+			for i, name := range tt.want {
+				if page[i].Name != name {
+					t.Errorf("page[%d] = %q, want %q", i, page[i].Name, name)
+				}
+			}
+			if *meta != tt.meta {
+				t.Errorf("meta = %+v, want %+v", *meta, tt.meta)
+			}
+		})
+	}
+}
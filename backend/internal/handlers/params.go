@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"k8s-monitor/internal/models"
+	"k8s-monitor/internal/services"
+)
+
+// queryBool parses an optional boolean query parameter
+func queryBool(c *gin.Context, name string) (bool, error) {
+	value := c.Query(name)
+	if value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("parameter '%s' must be a boolean", name)
+	}
+	return parsed, nil
+}
+
+// queryPositiveInt64 parses an optional positive integer query parameter, returning nil when absent
+func queryPositiveInt64(c *gin.Context, name string) (*int64, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || parsed <= 0 {
+		return nil, fmt.Errorf("parameter '%s' must be a positive integer", name)
+	}
+	return &parsed, nil
+}
+
+// podListQuery holds the parameters of pod list endpoints
+type podListQuery struct {
+	listOptions metav1.ListOptions // selectors and continuation, pushed down to the pod listing
+	filter      models.PodFilter   // matched after conversion
+	page        pageQuery
+}
+
+// continued reports whether pods are listed in chunks with continue tokens
+func (q podListQuery) continued() bool {
+	return q.listOptions.Limit > 0 || q.listOptions.Continue != ""
+}
+
+// parsePodListQuery parses the filters, paging and continuation of pod list endpoints.
+// Label and field selectors and the node are pushed down to the pod listing; status and
+// search are matched afterwards. Continued lists are chunked by the API server, so they
+// cannot also be paged or sorted here.
+func parsePodListQuery(c *gin.Context) (podListQuery, error) {
+	var q podListQuery
+
+	if value := c.Query("labelSelector"); value != "" {
+		selector, err := labels.Parse(value)
+		if err != nil {
+			return q, fmt.Errorf("parameter 'labelSelector' is invalid: %w", err)
+		}
+		q.listOptions.LabelSelector = selector.String()
+	}
+
+	fieldSelector, err := fields.ParseSelector(c.Query("fieldSelector"))
+	if err != nil {
+		return q, fmt.Errorf("parameter 'fieldSelector' is invalid: %w", err)
+	}
+	if node := c.Query("node"); node != "" {
+		fieldSelector = fields.AndSelectors(fieldSelector, fields.OneTermEqualSelector("spec.nodeName", node))
+	}
+	if err := services.ValidatePodFieldSelector(fieldSelector); err != nil {
+		return q, fmt.Errorf("parameter 'fieldSelector' is invalid: %w", err)
+	}
+	if !fieldSelector.Empty() {
+		q.listOptions.FieldSelector = fieldSelector.String()
+	}
+
+	q.filter = models.PodFilter{
+		Status: c.Query("status"),
+		Search: c.Query("search"),
+	}
+
+	if q.page, err = parsePageQuery(c, podSortKeys); err != nil {
+		return q, err
+	}
+
+	limit, err := queryPositiveInt64(c, "limit")
+	if err != nil {
+		return q, err
+	}
+	if limit != nil {
+		q.listOptions.Limit = *limit
+	}
+	q.listOptions.Continue = c.Query("continue")
+	if q.continued() && (q.page.paged() || q.page.sort != "") {
+		return q, fmt.Errorf("parameters 'limit' and 'continue' cannot be combined with paging or sorting")
+	}
+
+	return q, nil
+}
+
+// parseApplicationFilter parses the filters of application list endpoints
+func parseApplicationFilter(c *gin.Context) (models.ApplicationFilter, error) {
+	filter := models.ApplicationFilter{
+		Status: strings.ToLower(c.Query("status")),
+		Type:   strings.ToLower(c.Query("type")),
+		Node:   c.Query("node"),
+		Search: c.Query("search"),
+	}
+
+	if value := c.Query("labelSelector"); value != "" {
+		selector, err := labels.Parse(value)
+		if err != nil {
+			return filter, fmt.Errorf("parameter 'labelSelector' is invalid: %w", err)
+		}
+		filter.Selector = selector
+	}
+
+	switch models.ApplicationStatus(filter.Status) {
+	case "", models.StatusHealthy, models.StatusDegraded, models.StatusUnhealthy, models.StatusUnknown:
+	default:
+		return filter, fmt.Errorf("parameter 'status' must be one of healthy, degraded, unhealthy or unknown")
+	}
+
+	return filter, nil
+}
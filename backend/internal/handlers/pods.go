@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"context"
-	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"k8s-monitor/internal/models"
 	"k8s-monitor/internal/services"
@@ -15,15 +17,15 @@ import (
 
 // PodHandler handles pod-related HTTP requests
 type PodHandler struct {
-	k8sService *services.KubernetesService
-	logger     *logrus.Logger
+	clusters *services.ClusterManager
+	logger   *logrus.Logger
 }
 
 // NewPodHandler creates a new pod handler instance
-func NewPodHandler(k8sService *services.KubernetesService, logger *logrus.Logger) *PodHandler {
+func NewPodHandler(clusters *services.ClusterManager, logger *logrus.Logger) *PodHandler {
 	return &PodHandler{
-		k8sService: k8sService,
-		logger:     logger,
+		clusters: clusters,
+		logger:   logger,
 	}
 }
 
@@ -33,60 +35,107 @@ func NewPodHandler(k8sService *services.KubernetesService, logger *logrus.Logger
 // @Tags pods
 // @Accept json
 // @Produce json
+// @Param cluster query string false "Cluster name, or 'all' to aggregate every cluster"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param fieldSelector query string false "Kubernetes field selector, e.g. status.phase=Running"
+// @Param node query string false "Node name"
+// @Param status query string false "Pod status, e.g. Running or CrashLoopBackOff"
+// @Param search query string false "Case-insensitive match on the pod name"
+// @Param page query int false "Page number, starting at 1"
+// @Param pageSize query int false "Page size, at most 500"
+// @Param sort query string false "Sort key" Enums(name, namespace, cluster, status, node, restarts, age)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Param limit query int false "Maximum number of pods listed by the API server, for continued lists. Chunks carry no usage metrics."
+// @Param continue query string false "Continue token of the previous chunk, from meta.continue"
 // @Success 200 {object} models.PodListResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 410 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
 // @Router /api/v1/pods [get]
 func (h *PodHandler) List(c *gin.Context) {
+	query, err := parsePodListQuery(c)
+	if err != nil {
+		models.RespondBadRequest(c, "Invalid query parameters", err.Error())
+		return
+	}
+
+	clusters, ok := resolveClusters(c, h.clusters)
+	if !ok {
+		return
+	}
+	if query.continued() && len(clusters) > 1 {
+		models.RespondBadRequest(c, "Invalid query parameters", "Continue tokens are only supported for a single cluster")
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 	defer cancel()
 
 	logger := utils.WithComponent(h.logger, "pod-handler")
 	logger.Info("Fetching all pods")
 
-	// Get all pods
-	podList, err := h.k8sService.GetAllPods(ctx)
-	if err != nil {
-		logger.WithError(err).Error("Failed to fetch pods")
-		models.RespondKubernetesError(c, "list pods", err)
-		return
-	}
-
 	// Convert to our model format
 	var pods []models.PodStatus
 	summary := models.PodSummary{}
-
-	for _, pod := range podList.Items {
-		// Check if namespace is allowed
-		if !h.k8sService.IsNamespaceAllowed(pod.Namespace) {
-			continue
+	metricsAvailable := true
+	continueToken := ""
+
+	clusterErrors, err := forEachCluster(clusters, logger, func(cluster *services.Cluster) error {
+		// Get all pods matching the selectors
+		podList, err := cluster.K8s.ListPods(ctx, "", query.listOptions)
+		if err != nil {
+			return err
 		}
-
-		podStatus := models.FromK8sPod(&pod)
-		pods = append(pods, podStatus)
-
-		// Update summary
-		switch podStatus.Status {
-		case "Running":
-			summary.Running++
-		case "Pending":
-			summary.Pending++
-		case "Succeeded":
-			summary.Succeeded++
-		case "Failed":
-			summary.Failed++
-		default:
-			summary.Unknown++
+		continueToken = podList.Continue
+
+		metrics := podListMetrics(ctx, cluster, "", query, logger)
+		metricsAvailable = metricsAvailable && metrics.Available
+		owners := podListOwners(ctx, cluster, "", query)
+
+		for _, pod := range podList.Items {
+			// Check if namespace is allowed
+			if !cluster.K8s.IsNamespaceAllowed(pod.Namespace) {
+				continue
+			}
+
+			podStatus := models.FromK8sPod(&pod)
+			if !query.filter.Matches(podStatus) {
+				continue
+			}
+			podStatus.Cluster = cluster.Name
+			owners.Apply(ctx, pod, &podStatus)
+			metrics.Apply(&podStatus)
+			pods = append(pods, podStatus)
+			summary.Add(podStatus.Status)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch pods")
+		if apierrors.IsResourceExpired(err) {
+			respondContinueExpired(c)
+			return
 		}
+		models.RespondKubernetesError(c, "list pods", err)
+		return
 	}
 
+	total := len(pods)
+	pods, meta := paginate(pods, query.page, podSortKeys)
+	meta.Continue = continueToken
+
 	response := models.PodListResponse{
-		Pods:    pods,
-		Total:   len(pods),
-		Summary: summary,
+		Pods:             pods,
+		Total:            total,
+		Cluster:          c.Query("cluster"),
+		Summary:          summary,
+		MetricsAvailable: metricsAvailable,
+		ClusterErrors:    clusterErrors,
 	}
 
-	logger.WithField("total", len(pods)).Info("Successfully fetched pods")
-	models.RespondSuccess(c, response)
+	logger.WithField("total", total).Info("Successfully fetched pods")
+	models.RespondSuccessWithMeta(c, response, meta)
 }
 
 // ListByNamespace retrieves pods from a specific namespace
@@ -96,9 +145,23 @@ func (h *PodHandler) List(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param namespace path string true "Namespace name"
+// @Param cluster query string false "Cluster name, or 'all' to aggregate every cluster"
+// @Param labelSelector query string false "Kubernetes label selector, e.g. app=web,tier!=cache"
+// @Param fieldSelector query string false "Kubernetes field selector, e.g. status.phase=Running"
+// @Param node query string false "Node name"
+// @Param status query string false "Pod status, e.g. Running or CrashLoopBackOff"
+// @Param search query string false "Case-insensitive match on the pod name"
+// @Param page query int false "Page number, starting at 1"
+// @Param pageSize query int false "Page size, at most 500"
+// @Param sort query string false "Sort key" Enums(name, namespace, cluster, status, node, restarts, age)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Param limit query int false "Maximum number of pods listed by the API server, for continued lists. Chunks carry no usage metrics."
+// @Param continue query string false "Continue token of the previous chunk, from meta.continue"
 // @Success 200 {object} models.PodListResponse
 // @Failure 400 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
 // @Failure 404 {object} models.APIResponse
+// @Failure 410 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
 // @Router /api/v1/pods/{namespace} [get]
 func (h *PodHandler) ListByNamespace(c *gin.Context) {
@@ -108,6 +171,21 @@ func (h *PodHandler) ListByNamespace(c *gin.Context) {
 		return
 	}
 
+	query, err := parsePodListQuery(c)
+	if err != nil {
+		models.RespondBadRequest(c, "Invalid query parameters", err.Error())
+		return
+	}
+
+	clusters, ok := resolveClusters(c, h.clusters)
+	if !ok {
+		return
+	}
+	if query.continued() && len(clusters) > 1 {
+		models.RespondBadRequest(c, "Invalid query parameters", "Continue tokens are only supported for a single cluster")
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 	defer cancel()
 
@@ -115,59 +193,69 @@ func (h *PodHandler) ListByNamespace(c *gin.Context) {
 	logger.Info("Fetching pods by namespace")
 
 	// Check if namespace is allowed
-	if !h.k8sService.IsNamespaceAllowed(namespace) {
-		models.RespondBadRequest(c, "Access to namespace not allowed",
-			fmt.Sprintf("Namespace '%s' is not in the allowed list", namespace))
+	if !clusters[0].K8s.IsNamespaceAllowed(namespace) {
+		models.RespondNamespaceForbidden(c, namespace)
 		return
 	}
 
-	// Get pods from the specified namespace
-	podList, err := h.k8sService.GetPods(ctx, namespace)
+	// Convert to our model format
+	var pods []models.PodStatus
+	summary := models.PodSummary{}
+	metricsAvailable := true
+	continueToken := ""
+
+	clusterErrors, err := forEachCluster(clusters, logger, func(cluster *services.Cluster) error {
+		// Get pods from the specified namespace matching the selectors
+		podList, err := cluster.K8s.ListPods(ctx, namespace, query.listOptions)
+		if err != nil {
+			return err
+		}
+		continueToken = podList.Continue
+
+		metrics := podListMetrics(ctx, cluster, namespace, query, logger)
+		metricsAvailable = metricsAvailable && metrics.Available
+		owners := podListOwners(ctx, cluster, namespace, query)
+
+		for _, pod := range podList.Items {
+			podStatus := models.FromK8sPod(&pod)
+			if !query.filter.Matches(podStatus) {
+				continue
+			}
+			podStatus.Cluster = cluster.Name
+			owners.Apply(ctx, pod, &podStatus)
+			metrics.Apply(&podStatus)
+			pods = append(pods, podStatus)
+			summary.Add(podStatus.Status)
+		}
+		return nil
+	})
 	if err != nil {
 		logger.WithError(err).Error("Failed to fetch pods")
-
-		// Check if it's a namespace not found error
-		if IsNamespaceNotFoundError(err) {
-			models.RespondNamespaceNotFound(c, namespace)
+		if apierrors.IsResourceExpired(err) {
+			respondContinueExpired(c)
 			return
 		}
-
 		models.RespondKubernetesError(c, "list pods in namespace", err)
 		return
 	}
 
-	// Convert to our model format
-	var pods []models.PodStatus
-	summary := models.PodSummary{}
-
-	for _, pod := range podList.Items {
-		podStatus := models.FromK8sPod(&pod)
-		pods = append(pods, podStatus)
-
-		// Update summary
-		switch podStatus.Status {
-		case "Running":
-			summary.Running++
-		case "Pending":
-			summary.Pending++
-		case "Succeeded":
-			summary.Succeeded++
-		case "Failed":
-			summary.Failed++
-		default:
-			summary.Unknown++
-		}
-	}
+	total := len(pods)
+	pods, meta := paginate(pods, query.page, podSortKeys)
+	meta.Continue = continueToken
+	meta.Namespace = namespace
 
 	response := models.PodListResponse{
-		Pods:      pods,
-		Total:     len(pods),
-		Namespace: namespace,
-		Summary:   summary,
+		Pods:             pods,
+		Total:            total,
+		Namespace:        namespace,
+		Cluster:          c.Query("cluster"),
+		Summary:          summary,
+		MetricsAvailable: metricsAvailable,
+		ClusterErrors:    clusterErrors,
 	}
 
-	logger.WithField("total", len(pods)).Info("Successfully fetched pods by namespace")
-	models.RespondSuccess(c, response)
+	logger.WithField("total", total).Info("Successfully fetched pods by namespace")
+	models.RespondSuccessWithMeta(c, response, meta)
 }
 
 // ListNamespaces retrieves all accessible namespaces
@@ -176,48 +264,83 @@ func (h *PodHandler) ListByNamespace(c *gin.Context) {
 // @Tags namespaces
 // @Accept json
 // @Produce json
+// @Param cluster query string false "Cluster name, or 'all' to aggregate every cluster"
+// @Param page query int false "Page number, starting at 1"
+// @Param pageSize query int false "Page size, at most 500"
+// @Param sort query string false "Sort key" Enums(name, cluster, status, pods, age)
+// @Param order query string false "Sort order" Enums(asc, desc)
 // @Success 200 {object} models.NamespaceListResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
 // @Router /api/v1/namespaces [get]
 func (h *PodHandler) ListNamespaces(c *gin.Context) {
+	page, err := parsePageQuery(c, namespaceSortKeys)
+	if err != nil {
+		models.RespondBadRequest(c, "Invalid query parameters", err.Error())
+		return
+	}
+
+	clusters, ok := resolveClusters(c, h.clusters)
+	if !ok {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 	defer cancel()
 
 	logger := utils.WithComponent(h.logger, "pod-handler")
 	logger.Info("Fetching namespaces")
 
-	// Get all namespaces
-	namespaceList, err := h.k8sService.GetNamespaces(ctx)
-	if err != nil {
-		logger.WithError(err).Error("Failed to fetch namespaces")
-		models.RespondKubernetesError(c, "list namespaces", err)
-		return
-	}
-
-	// Convert to our model format and filter allowed namespaces
 	var namespaces []models.NamespaceInfo
-	for _, ns := range namespaceList.Items {
-		if !h.k8sService.IsNamespaceAllowed(ns.Name) {
-			continue
-		}
 
-		nsInfo := models.FromK8sNamespace(&ns)
+	clusterErrors, err := forEachCluster(clusters, logger, func(cluster *services.Cluster) error {
+		// Get all namespaces
+		namespaceList, err := cluster.K8s.GetNamespaces(ctx)
+		if err != nil {
+			return err
+		}
 
-		// Optionally get pod count for this namespace
-		if podList, err := h.k8sService.GetPods(ctx, ns.Name); err == nil {
-			nsInfo.PodCount = len(podList.Items)
+		// Filter allowed namespaces
+		var allowed []corev1.Namespace
+		var names []string
+		for _, ns := range namespaceList.Items {
+			if cluster.K8s.IsNamespaceAllowed(ns.Name) {
+				allowed = append(allowed, ns)
+				names = append(names, ns.Name)
+			}
 		}
 
-		namespaces = append(namespaces, nsInfo)
+		// Pods are counted with one list rather than one per namespace
+		podCounts := services.CountPods(ctx, cluster.K8s, names, logger)
+
+		// Convert to our model format
+		for _, ns := range allowed {
+			nsInfo := models.FromK8sNamespace(&ns)
+			nsInfo.Cluster = cluster.Name
+			nsInfo.PodCount = podCounts[ns.Name]
+			namespaces = append(namespaces, nsInfo)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch namespaces")
+		models.RespondKubernetesError(c, "list namespaces", err)
+		return
 	}
 
+	total := len(namespaces)
+	namespaces, meta := paginate(namespaces, page, namespaceSortKeys)
+
 	response := models.NamespaceListResponse{
-		Namespaces: namespaces,
-		Total:      len(namespaces),
+		Namespaces:    namespaces,
+		Total:         total,
+		Cluster:       c.Query("cluster"),
+		ClusterErrors: clusterErrors,
 	}
 
-	logger.WithField("total", len(namespaces)).Info("Successfully fetched namespaces")
-	models.RespondSuccess(c, response)
+	logger.WithField("total", total).Info("Successfully fetched namespaces")
+	models.RespondSuccessWithMeta(c, response, meta)
 }
 
 // GetPod retrieves a specific pod by name and namespace
@@ -228,8 +351,10 @@ func (h *PodHandler) ListNamespaces(c *gin.Context) {
 // @Produce json
 // @Param namespace path string true "Namespace name"
 // @Param name path string true "Pod name"
+// @Param cluster query string false "Cluster name"
 // @Success 200 {object} models.PodStatus
 // @Failure 400 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
 // @Failure 404 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
 // @Router /api/v1/pods/{namespace}/{name} [get]
@@ -242,6 +367,11 @@ func (h *PodHandler) GetPod(c *gin.Context) {
 		return
 	}
 
+	cluster, ok := resolveCluster(c, h.clusters)
+	if !ok {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 	defer cancel()
 
@@ -249,46 +379,49 @@ func (h *PodHandler) GetPod(c *gin.Context) {
 	logger.Info("Fetching specific pod")
 
 	// Check if namespace is allowed
-	if !h.k8sService.IsNamespaceAllowed(namespace) {
-		models.RespondBadRequest(c, "Access to namespace not allowed",
-			fmt.Sprintf("Namespace '%s' is not in the allowed list", namespace))
+	if !cluster.K8s.IsNamespaceAllowed(namespace) {
+		models.RespondNamespaceForbidden(c, namespace)
 		return
 	}
 
 	// Get the specific pod
-	pod, err := h.k8sService.GetPod(ctx, namespace, podName)
+	pod, err := cluster.K8s.GetPod(ctx, namespace, podName)
 	if err != nil {
 		logger.WithError(err).Error("Failed to fetch pod")
-
-		// Check if it's a pod not found error
-		if IsPodNotFoundError(err) {
-			models.RespondPodNotFound(c, namespace, podName)
-			return
-		}
-
 		models.RespondKubernetesError(c, "get pod", err)
 		return
 	}
 
 	podStatus := models.FromK8sPod(pod)
+	podStatus.Cluster = cluster.Name
+	cluster.Applications.PodOwners().Apply(ctx, *pod, &podStatus)
 
 	logger.Info("Successfully fetched pod")
 	models.RespondSuccess(c, podStatus)
 }
 
-// IsNamespaceNotFoundError checks if the error is due to namespace not being found
-func IsNamespaceNotFoundError(err error) bool {
-	// This is a simplified check - in a real implementation, you might want to
-	// check for specific Kubernetes error types
-	return err != nil &&
-		(fmt.Sprintf("%v", err) == "namespaces \"not-found\" not found" ||
-			fmt.Sprintf("%v", err) == "the server could not find the requested resource")
+// respondContinueExpired responds to a continue token the API server no longer accepts
+func respondContinueExpired(c *gin.Context) {
+	models.RespondError(c, http.StatusGone, models.ErrCodeValidation, "Continue token expired",
+		"The list changed too much since the token was issued, restart it without a continue token")
 }
 
-// IsPodNotFoundError checks if the error is due to pod not being found
-func IsPodNotFoundError(err error) bool {
-	// This is a simplified check - in a real implementation, you might want to
-	// check for specific Kubernetes error types
-	return err != nil &&
-		fmt.Sprintf("%v", err) == "pods \"not-found\" not found"
+// podListMetrics reads the usage of the pods listed from a namespace, or from all
+// namespaces when namespace is empty. Chunks are read without usage, as metrics-server
+// only serves the usage of whole namespaces.
+func podListMetrics(ctx context.Context, cluster *services.Cluster, namespace string, query podListQuery, logger *logrus.Entry) services.PodMetrics {
+	if query.continued() {
+		return services.PodMetrics{}
+	}
+	return services.GetPodMetrics(ctx, cluster.K8s, namespace, logger)
+}
+
+// podListOwners describes the pods listed from a namespace, or from all namespaces when
+// namespace is empty. The owners of a chunk are few, so they are looked up one by one
+// rather than listing every owner of the namespace.
+func podListOwners(ctx context.Context, cluster *services.Cluster, namespace string, query podListQuery) *services.PodOwners {
+	if query.continued() {
+		return cluster.Applications.PodOwners()
+	}
+	return cluster.Applications.PodListOwners(ctx, namespace)
 }
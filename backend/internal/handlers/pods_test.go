@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/middleware"
+	"k8s-monitor/internal/models"
+	"k8s-monitor/internal/services"
+)
+
+func TestPodHandlerListFilters(t *testing.T) {
+	pending := newRunningPod("shop", "worker-1", "worker")
+	pending.Status.Phase = corev1.PodPending
+
+	gin.SetMode(gin.TestMode)
+	clients := []services.KubernetesClient{newTestClient("prod", config.KubernetesConfig{},
+		newRunningPod("shop", "api-1", "api"),
+		newRunningPod("shop", "api-2", "api"),
+		newRunningPod("shop", "web-1", "web"),
+		pending,
+	)}
+	handler := NewPodHandler(services.NewClusterManagerFromClients(clients, newTestLogger()), newTestLogger())
+	router := gin.New()
+	router.GET("/api/v1/pods", handler.List)
+	router.GET("/api/v1/pods/:namespace", handler.ListByNamespace)
+
+	tests := []struct {
+		name    string
+		path    string
+		status  int
+		pods    []string
+		running int
+		pending int
+	}{
+		{name: "label selector", path: "/api/v1/pods?labelSelector=app%3Dapi", status: http.StatusOK, pods: []string{"api-1", "api-2"}, running: 2},
+		{name: "set-based selector", path: "/api/v1/pods/shop?labelSelector=app+notin+(api)", status: http.StatusOK, pods: []string{"web-1", "worker-1"}, running: 1, pending: 1},
+		{name: "status", path: "/api/v1/pods?status=pending", status: http.StatusOK, pods: []string{"worker-1"}, pending: 1},
+		{name: "search", path: "/api/v1/pods/shop?search=WEB", status: http.StatusOK, pods: []string{"web-1"}, running: 1},
+		{name: "invalid label selector", path: "/api/v1/pods?labelSelector=app%3D%3D%3D", status: http.StatusBadRequest},
+		{name: "unsupported field", path: "/api/v1/pods?fieldSelector=spec.priority%3D1", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response models.PodListResponse
+			status, _ := performRequest(t, router, tt.path, &response)
+
+			if status != tt.status {
+				t.Fatalf("status = %d, want %d", status, tt.status)
+			}
+			if status != http.StatusOK {
+				return
+			}
+
+			if response.Total != len(tt.pods) {
+				t.Fatalf("Total = %d, want %d", response.Total, len(tt.pods))
+			}
+			for i, name := range tt.pods {
+				if response.Pods[i].Name != name {
+					t.Errorf("pods[%d] = %q, want %q", i, response.Pods[i].Name, name)
+				}
+			}
+			if response.Summary.Running != tt.running || response.Summary.Pending != tt.pending {
+				t.Errorf("Summary = %+v, want %d running and %d pending", response.Summary, tt.running, tt.pending)
+			}
+		})
+	}
+}
+
+func TestPodHandlerListContinue(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	var listed metav1.ListOptions
+	clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		listed = action.(k8stesting.ListActionImpl).GetListOptions()
+		if listed.Continue == "expired" {
+			return true, nil, apierrors.NewResourceExpired("continue token expired")
+		}
+		list := &corev1.PodList{Items: []corev1.Pod{*newRunningPod("shop", "api-1", "api")}}
+		list.Continue = "next-chunk"
+		return true, list, nil
+	})
+
+	gin.SetMode(gin.TestMode)
+	clients := []services.KubernetesClient{
+		newTestClientForClientset("prod", config.KubernetesConfig{}, clientset),
+		newTestClient("dev", config.KubernetesConfig{}),
+	}
+	handler := NewPodHandler(services.NewClusterManagerFromClients(clients, newTestLogger()), newTestLogger())
+	router := gin.New()
+	router.GET("/api/v1/pods", handler.List)
+
+	var response models.PodListResponse
+	status, envelope := performRequest(t, router, "/api/v1/pods?cluster=prod&limit=1&continue=previous-chunk", &response)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", status, http.StatusOK)
+	}
+	if response.MetricsAvailable {
+		t.Errorf("a chunk was served with the usage of every pod")
+	}
+	if listed.Limit != 1 || listed.Continue != "previous-chunk" {
+		t.Errorf("listed with limit %d and continue %q, want 1 and previous-chunk", listed.Limit, listed.Continue)
+	}
+	if envelope.Meta == nil || envelope.Meta.Continue != "next-chunk" {
+		t.Errorf("meta = %+v, want continue token next-chunk", envelope.Meta)
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{name: "expired token", path: "/api/v1/pods?cluster=prod&continue=expired", status: http.StatusGone},
+		{name: "several clusters", path: "/api/v1/pods?cluster=all&limit=10", status: http.StatusBadRequest},
+		{name: "with sorting", path: "/api/v1/pods?cluster=prod&limit=10&sort=name", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status, _ := performRequest(t, router, tt.path, &models.PodListResponse{}); status != tt.status {
+				t.Errorf("status = %d, want %d", status, tt.status)
+			}
+		})
+	}
+}
+
+func TestPodHandlerListNamespaces(t *testing.T) {
+	cfg := config.KubernetesConfig{}
+	cfg.Namespaces.Exclude = []string{"kube-system"}
+
+	tests := []struct {
+		name          string
+		allPods       bool // whether pods can be listed across all namespaces
+		listNamespace int
+	}{
+		{name: "one list of all pods", allPods: true},
+		{name: "namespace scoped access", allPods: false, listNamespace: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "empty"}},
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+				newRunningPod("shop", "api-1", "api"),
+				newRunningPod("shop", "web-1", "web"),
+				newRunningPod("kube-system", "dns-1", "dns"),
+			)
+			var listAll, listNamespace int
+			clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if action.GetNamespace() != "" {
+					listNamespace++
+					return false, nil, nil
+				}
+				listAll++
+				if !tt.allPods {
+					return true, nil, apierrors.NewForbidden(corev1.Resource("pods"), "", nil)
+				}
+				return false, nil, nil
+			})
+
+			gin.SetMode(gin.TestMode)
+			clients := []services.KubernetesClient{newTestClientForClientset("prod", cfg, clientset)}
+			handler := NewPodHandler(services.NewClusterManagerFromClients(clients, newTestLogger()), newTestLogger())
+			router := gin.New()
+			router.GET("/api/v1/namespaces", handler.ListNamespaces)
+
+			var response models.NamespaceListResponse
+			if status, _ := performRequest(t, router, "/api/v1/namespaces", &response); status != http.StatusOK {
+				t.Fatalf("status = %d, want %d", status, http.StatusOK)
+			}
+
+			counts := make(map[string]int)
+			for _, ns := range response.Namespaces {
+				counts[ns.Name] = ns.PodCount
+			}
+			if len(counts) != 2 || counts["shop"] != 2 || counts["empty"] != 0 {
+				t.Errorf("pod counts = %v, want shop: 2 and empty: 0", counts)
+			}
+
+			// Namespaces are only listed one by one when all pods cannot be listed
+			if listAll != 1 || listNamespace != tt.listNamespace {
+				t.Errorf("listed all pods %d times and namespace pods %d times, want once and %d",
+					listAll, listNamespace, tt.listNamespace)
+			}
+		})
+	}
+}
+
+func TestPodHandlerListServesStaleData(t *testing.T) {
+	clientset := fake.NewSimpleClientset(newRunningPod("shop", "api-1", "api"))
+	gin.SetMode(gin.TestMode)
+	clients := []services.KubernetesClient{newTestClientForClientset("prod", config.KubernetesConfig{}, clientset)}
+	handler := NewPodHandler(services.NewClusterManagerFromClients(clients, newTestLogger()), newTestLogger())
+	router := gin.New()
+	router.Use(middleware.Staleness())
+	router.GET("/api/v1/pods", handler.List)
+
+	status, envelope := performRequest(t, router, "/api/v1/pods", &models.PodListResponse{})
+	if status != http.StatusOK || envelope.Stale {
+		t.Fatalf("live response %d with stale %v", status, envelope.Stale)
+	}
+
+	// The API server goes away
+	clientset.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewServiceUnavailable("connection refused")
+	})
+
+	var response models.PodListResponse
+	status, envelope = performRequest(t, router, "/api/v1/pods", &response)
+	if status != http.StatusOK || response.Total != 1 {
+		t.Fatalf("status = %d with %d pods, want the last known pod", status, response.Total)
+	}
+	if !envelope.Stale || envelope.DataAge == "" || envelope.DataTimestamp == nil {
+		t.Errorf("response not flagged stale: stale %v, age %q, data timestamp %v", envelope.Stale, envelope.DataAge, envelope.DataTimestamp)
+	}
+}
+
+func TestPodHandlerGetKubernetesErrors(t *testing.T) {
+	cfg := config.KubernetesConfig{}
+	cfg.Namespaces.Exclude = []string{"kube-system"}
+
+	tests := []struct {
+		name       string
+		path       string
+		err        error // returned by the API server, none to get the pod
+		status     int
+		errCode    string
+		retryAfter string
+	}{
+		{name: "found", path: "/api/v1/pods/shop/api-1", status: http.StatusOK},
+		{name: "missing pod", path: "/api/v1/pods/shop/web-1", status: http.StatusNotFound, errCode: models.ErrCodePodNotFound},
+		{name: "excluded namespace", path: "/api/v1/pods/kube-system/dns-1", status: http.StatusForbidden, errCode: models.ErrCodeForbidden},
+		{
+			name:    "forbidden by the cluster",
+			path:    "/api/v1/pods/shop/api-1",
+			err:     apierrors.NewForbidden(corev1.Resource("pods"), "api-1", nil),
+			status:  http.StatusForbidden,
+			errCode: models.ErrCodeForbidden,
+		},
+		{
+			name:    "conflict",
+			path:    "/api/v1/pods/shop/api-1",
+			err:     apierrors.NewConflict(corev1.Resource("pods"), "api-1", nil),
+			status:  http.StatusConflict,
+			errCode: models.ErrCodeConflict,
+		},
+		{
+			name:       "rate limited",
+			path:       "/api/v1/pods/shop/api-1",
+			err:        apierrors.NewTooManyRequests("slow down", 7),
+			status:     http.StatusTooManyRequests,
+			errCode:    models.ErrCodeRateLimit,
+			retryAfter: "7",
+		},
+		{
+			name:    "timeout",
+			path:    "/api/v1/pods/shop/api-1",
+			err:     apierrors.NewServerTimeout(corev1.Resource("pods"), "get", 1),
+			status:  http.StatusGatewayTimeout,
+			errCode: models.ErrCodeTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(newRunningPod("shop", "api-1", "api"))
+			if tt.err != nil {
+				clientset.PrependReactor("get", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tt.err
+				})
+			}
+
+			gin.SetMode(gin.TestMode)
+			clients := []services.KubernetesClient{newTestClientForClientset("prod", cfg, clientset)}
+			handler := NewPodHandler(services.NewClusterManagerFromClients(clients, newTestLogger()), newTestLogger())
+			router := gin.New()
+			router.GET("/api/v1/pods/:namespace/:name", handler.GetPod)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			var envelope models.APIResponse
+			if err := json.Unmarshal(recorder.Body.Bytes(), &envelope); err != nil {
+				t.Fatalf("failed to decode response %q: %v", recorder.Body.String(), err)
+			}
+			if recorder.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.status, recorder.Body.String())
+			}
+			if tt.errCode != "" && (envelope.Error == nil || envelope.Error.Code != tt.errCode) {
+				t.Errorf("error = %+v, want code %s", envelope.Error, tt.errCode)
+			}
+			if got := recorder.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.retryAfter)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/models"
+	"k8s-monitor/internal/storage"
+	"k8s-monitor/pkg/utils"
+)
+
+// maxSettingSize bounds the size of one setting value
+const maxSettingSize = 64 * 1024
+
+// settingKeyPattern restricts setting keys to simple identifiers
+var settingKeyPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// SettingsHandler handles persisted user settings
+type SettingsHandler struct {
+	store  storage.Store
+	logger *logrus.Logger
+}
+
+// NewSettingsHandler creates a new settings handler instance
+func NewSettingsHandler(store storage.Store, logger *logrus.Logger) *SettingsHandler {
+	return &SettingsHandler{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// List retrieves every stored setting
+// @Summary List settings
+// @Description Get every persisted user setting
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.SettingsResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/v1/settings [get]
+func (h *SettingsHandler) List(c *gin.Context) {
+	settings, err := h.store.Settings(c.Request.Context())
+	if err != nil {
+		utils.WithComponent(h.logger, "settings-handler").WithError(err).Error("Failed to list settings")
+		models.RespondInternalError(c, "Failed to list settings", err.Error())
+		return
+	}
+
+	response := models.SettingsResponse{Settings: make(map[string]json.RawMessage, len(settings))}
+	for key, value := range settings {
+		response.Settings[key] = value
+	}
+	models.RespondSuccess(c, response)
+}
+
+// Get retrieves one setting
+// @Summary Get setting
+// @Description Get one persisted user setting
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Param key path string true "Setting key"
+// @Success 200 {object} models.Setting
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/v1/settings/{key} [get]
+func (h *SettingsHandler) Get(c *gin.Context) {
+	key, ok := settingKey(c)
+	if !ok {
+		return
+	}
+
+	value, err := h.store.GetSetting(c.Request.Context(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		models.RespondNotFound(c, "Setting not found", "No setting with key '"+key+"'")
+		return
+	}
+	if err != nil {
+		utils.WithComponent(h.logger, "settings-handler").WithError(err).Error("Failed to get setting")
+		models.RespondInternalError(c, "Failed to get setting", err.Error())
+		return
+	}
+
+	models.RespondSuccess(c, models.Setting{Key: key, Value: value})
+}
+
+// Put stores one setting
+// @Summary Store setting
+// @Description Create or replace a persisted user setting. The request body is the setting's JSON value.
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Param key path string true "Setting key"
+// @Param value body object true "Setting value"
+// @Success 200 {object} models.Setting
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/v1/settings/{key} [put]
+func (h *SettingsHandler) Put(c *gin.Context) {
+	key, ok := settingKey(c)
+	if !ok {
+		return
+	}
+
+	value, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxSettingSize))
+	if err != nil {
+		models.RespondBadRequest(c, "Setting value too large", "Setting values are limited to 64 KiB")
+		return
+	}
+	if !json.Valid(value) {
+		models.RespondBadRequest(c, "Invalid setting value", "The request body must be a JSON value")
+		return
+	}
+
+	if err := h.store.PutSetting(c.Request.Context(), key, value); err != nil {
+		utils.WithComponent(h.logger, "settings-handler").WithError(err).Error("Failed to store setting")
+		models.RespondInternalError(c, "Failed to store setting", err.Error())
+		return
+	}
+
+	models.RespondSuccess(c, models.Setting{Key: key, Value: value})
+}
+
+// Delete removes one setting
+// @Summary Delete setting
+// @Description Remove a persisted user setting
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Param key path string true "Setting key"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/v1/settings/{key} [delete]
+func (h *SettingsHandler) Delete(c *gin.Context) {
+	key, ok := settingKey(c)
+	if !ok {
+		return
+	}
+
+	if err := h.store.DeleteSetting(c.Request.Context(), key); err != nil {
+		utils.WithComponent(h.logger, "settings-handler").WithError(err).Error("Failed to delete setting")
+		models.RespondInternalError(c, "Failed to delete setting", err.Error())
+		return
+	}
+
+	models.RespondSuccess(c, gin.H{"key": key})
+}
+
+// settingKey validates the key path parameter, responding with an error when it is invalid
+func settingKey(c *gin.Context) (string, bool) {
+	key := c.Param("key")
+	if !settingKeyPattern.MatchString(key) {
+		models.RespondBadRequest(c, "Invalid setting key",
+			"Keys are 1 to 128 letters, digits, dots, underscores or hyphens")
+		return "", false
+	}
+	return key, true
+}
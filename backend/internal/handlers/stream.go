@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/models"
+	"k8s-monitor/internal/services"
+	"k8s-monitor/pkg/utils"
+)
+
+// StreamHandler handles server-sent event streams
+type StreamHandler struct {
+	clusters  *services.ClusterManager
+	watcher   *services.ApplicationWatcher
+	heartbeat time.Duration
+	logger    *logrus.Logger
+}
+
+// NewStreamHandler creates a new stream handler instance
+func NewStreamHandler(clusters *services.ClusterManager, watcher *services.ApplicationWatcher, heartbeat time.Duration, logger *logrus.Logger) *StreamHandler {
+	if heartbeat <= 0 {
+		heartbeat = 15 * time.Second
+	}
+	return &StreamHandler{
+		clusters:  clusters,
+		watcher:   watcher,
+		heartbeat: heartbeat,
+		logger:    logger,
+	}
+}
+
+// Applications streams application status changes as server-sent events
+// @Summary Stream application changes
+// @Description Server-sent event stream emitting an event whenever an application is added, removed, or changes status, pod counts or version. Reconnecting clients can resume with the Last-Event-ID header or the lastEventId parameter; a "reset" event is sent when the requested events are no longer buffered. When authentication is enabled the bearer token must be sent in the Authorization header, which the browser EventSource API cannot set: read the stream with fetch instead.
+// @Tags applications
+// @Produce text/event-stream
+// @Param namespace query string false "Only stream events for this namespace"
+// @Param cluster query string false "Cluster name, or 'all' to stream every cluster"
+// @Param lastEventId query integer false "Resume after this event ID"
+// @Param Last-Event-ID header integer false "Resume after this event ID"
+// @Success 200 {object} models.ApplicationEvent
+// @Failure 400 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /api/v1/applications/stream [get]
+func (h *StreamHandler) Applications(c *gin.Context) {
+	clusters, ok := resolveClusters(c, h.clusters)
+	if !ok {
+		return
+	}
+	clusterNames := make(map[string]bool, len(clusters))
+	for _, cluster := range clusters {
+		clusterNames[cluster.Name] = true
+	}
+
+	namespace := c.Query("namespace")
+	if namespace != "" && !clusters[0].K8s.IsNamespaceAllowed(namespace) {
+		models.RespondNamespaceForbidden(c, namespace)
+		return
+	}
+
+	lastEventID, err := parseLastEventID(c)
+	if err != nil {
+		models.RespondValidationError(c, "Last event ID must be a positive integer")
+		return
+	}
+
+	logger := utils.WithComponent(h.logger, "stream-handler").WithField("namespace", namespace)
+
+	reset := false
+	sub, replay, err := h.watcher.Subscribe(lastEventID)
+	if errors.Is(err, services.ErrEventsExpired) {
+		reset = true
+		sub, replay, err = h.watcher.Subscribe(0)
+	}
+	if err != nil {
+		models.RespondInternalError(c, "Failed to subscribe to application events", err.Error())
+		return
+	}
+	defer sub.Close()
+
+	logger.WithField("lastEventId", lastEventID).Info("Application event stream opened")
+
+	startStream(c, "text/event-stream")
+
+	if reset {
+		// Tell the client to refetch the full application list before applying new events
+		c.Render(-1, sse.Event{
+			Id:    strconv.FormatUint(h.watcher.LastEventID(), 10),
+			Event: "reset",
+			Data:  gin.H{"reason": services.ErrEventsExpired.Error()},
+		})
+	}
+
+	matches := func(event models.ApplicationEvent) bool {
+		return clusterNames[event.Cluster] && (namespace == "" || event.Namespace == namespace)
+	}
+
+	for _, event := range replay {
+		if matches(event) {
+			renderApplicationEvent(c, event)
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-sub.Events:
+			if !ok {
+				// The subscriber fell behind or the server is stopping
+				return false
+			}
+			if matches(event) {
+				renderApplicationEvent(c, event)
+			}
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			return err == nil
+		}
+	})
+
+	logger.Info("Application event stream closed")
+}
+
+// startStream prepares a long-lived streaming response with the given content type
+func startStream(c *gin.Context, contentType string) {
+	// Streams outlive the server write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", contentType)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.WriteHeaderNow()
+}
+
+// wantsEventStream reports whether the client asked for server-sent events
+func wantsEventStream(c *gin.Context) bool {
+	return c.Query("format") == "sse" || strings.Contains(c.GetHeader("Accept"), "text/event-stream")
+}
+
+// renderApplicationEvent writes an application event in SSE format
+func renderApplicationEvent(c *gin.Context, event models.ApplicationEvent) {
+	c.Render(-1, sse.Event{
+		Id:    strconv.FormatUint(event.ID, 10),
+		Event: string(event.Type),
+		Data:  event,
+	})
+}
+
+// parseLastEventID reads the resume position from the Last-Event-ID header or lastEventId parameter
+func parseLastEventID(c *gin.Context) (uint64, error) {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = c.Query("lastEventId")
+	}
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
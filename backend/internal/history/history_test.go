@@ -0,0 +1,204 @@
+package history
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+	"k8s-monitor/internal/storage"
+)
+
+var start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func sample(offset time.Duration, status string, readyPods int) models.StatusSample {
+	return models.StatusSample{
+		Cluster:   "prod",
+		Namespace: "shop",
+		Name:      "checkout-api",
+		Timestamp: start.Add(offset),
+		Status:    status,
+		Summary:   models.ApplicationSummary{TotalPods: 2, ReadyPods: readyPods},
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "24h", want: 24 * time.Hour},
+		{value: "7d", want: 7 * 24 * time.Hour},
+		{value: "90m", want: 90 * time.Minute},
+		{value: "0d", wantErr: true},
+		{value: "week", wantErr: true},
+		{value: "-1h", wantErr: true},
+	}
+	for _, tt := range tests {
+		window, err := ParseWindow(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseWindow(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (window.Duration != tt.want || window.Name != tt.value) {
+			t.Errorf("ParseWindow(%q) = %+v, want %s", tt.value, window, tt.want)
+		}
+	}
+}
+
+func TestStep(t *testing.T) {
+	tests := []struct {
+		name      string
+		span      time.Duration
+		requested time.Duration
+		want      time.Duration
+	}{
+		{name: "short range uses the interval", span: time.Hour, want: time.Minute},
+		{name: "requested step is kept", span: time.Hour, requested: 10 * time.Minute, want: 10 * time.Minute},
+		{name: "week snaps to a round step", span: 7 * 24 * time.Hour, want: 30 * time.Minute},
+		{name: "fine requested step is raised", span: 7 * 24 * time.Hour, requested: 5 * time.Minute, want: 30 * time.Minute},
+		{name: "year uses whole days", span: 365 * 24 * time.Hour, want: 24 * time.Hour},
+		{name: "decade uses several days", span: 3650 * 24 * time.Hour, want: 8 * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Step(start, start.Add(tt.span), tt.requested, time.Minute, 500); got != tt.want {
+				t.Errorf("Step() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	samples := []models.StatusSample{
+		sample(0, "healthy", 2),
+		sample(time.Minute, "degraded", 1),
+		sample(2*time.Minute, "healthy", 2),
+		sample(5*time.Minute, "healthy", 2),
+		sample(20*time.Minute, "unhealthy", 0),
+	}
+
+	points := Downsample(samples, 5*time.Minute)
+	if len(points) != 3 {
+		t.Fatalf("got %d points, want 3", len(points))
+	}
+	first := points[0]
+	if first.Status != "degraded" || first.Samples != 3 || first.Statuses["healthy"] != 2 || first.ReadyPods != 5.0/3 {
+		t.Errorf("unexpected first point %+v", first)
+	}
+	if !points[1].Timestamp.Equal(start.Add(5*time.Minute)) || points[2].Status != "unhealthy" {
+		t.Errorf("unexpected points %+v", points)
+	}
+}
+
+func TestUptime(t *testing.T) {
+	samples := []models.StatusSample{
+		sample(-time.Minute, "healthy", 2), // covers the start of the window
+		sample(time.Minute, "degraded", 1),
+		sample(2*time.Minute, "unhealthy", 0),
+		sample(3*time.Minute, "healthy", 2),
+		// The recorder was down between 6m and 10m
+		sample(10*time.Minute, "healthy", 2),
+	}
+
+	window := Uptime(samples, start, start.Add(12*time.Minute), 3*time.Minute)
+
+	// Observed: 0-6m and 10-12m = 8m; healthy: 0-1m, 3-6m, 10-12m = 6m; ready pods: all but 2-3m = 7m
+	if window.CoveragePercent != 66.667 {
+		t.Errorf("CoveragePercent = %v, want 66.667", window.CoveragePercent)
+	}
+	if window.UptimePercent == nil || *window.UptimePercent != 75 {
+		t.Errorf("UptimePercent = %v, want 75", window.UptimePercent)
+	}
+	if window.AvailabilityPercent == nil || *window.AvailabilityPercent != 87.5 {
+		t.Errorf("AvailabilityPercent = %v, want 87.5", window.AvailabilityPercent)
+	}
+
+	empty := Uptime(nil, start, start.Add(time.Hour), time.Minute)
+	if empty.UptimePercent != nil || empty.CoveragePercent != 0 {
+		t.Errorf("unexpected uptime without samples %+v", empty)
+	}
+}
+
+func TestApplicationHistory(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+
+	var samples []models.StatusSample
+	for i := 0; i < 120; i++ {
+		status, ready := "healthy", 2
+		if i >= 90 {
+			status, ready = "unhealthy", 0
+		}
+		samples = append(samples, sample(time.Duration(i)*time.Minute, status, ready))
+	}
+	if err := store.AppendStatus(ctx, samples); err != nil {
+		t.Fatalf("AppendStatus() error = %v", err)
+	}
+
+	service, err := NewService(store, config.HistoryConfig{Interval: 60, UptimeWindows: []string{"1h", "2h"}, MaxPoints: 24})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	now := start.Add(2 * time.Hour)
+	key := storage.ApplicationKey{Cluster: "prod", Namespace: "shop", Name: "checkout-api"}
+	response, err := service.ApplicationHistory(ctx, key, Query{From: start, To: now}, now)
+	if err != nil {
+		t.Fatalf("ApplicationHistory() error = %v", err)
+	}
+
+	if response.Step != "5m0s" || len(response.Points) != 24 {
+		t.Errorf("got %d points with step %s, want 24 points with step 5m0s", len(response.Points), response.Step)
+	}
+	if len(response.Uptime) != 2 {
+		t.Fatalf("got %d uptime windows, want 2", len(response.Uptime))
+	}
+	if uptime := response.Uptime[0]; uptime.Window != "1h" || *uptime.UptimePercent != 50 {
+		t.Errorf("unexpected 1h uptime %+v", uptime)
+	}
+	if uptime := response.Uptime[1]; uptime.Window != "2h" || *uptime.UptimePercent != 75 || uptime.CoveragePercent != 100 {
+		t.Errorf("unexpected 2h uptime %+v", uptime)
+	}
+
+	if _, err := NewService(store, config.HistoryConfig{UptimeWindows: []string{"soon"}}); err == nil {
+		t.Errorf("NewService() accepted an invalid window")
+	}
+}
+
+// staticSource is a Source with fixed observations
+type staticSource struct {
+	applications map[string][]models.Application
+	observedAt   map[string]time.Time
+}
+
+func (s staticSource) Applications() map[string][]models.Application { return s.applications }
+func (s staticSource) ObservedAt() map[string]time.Time              { return s.observedAt }
+
+func TestRecorderSkipsStaleClusters(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+
+	app := models.Application{Namespace: "shop", Name: "checkout-api", Status: "healthy"}
+	now := start.Add(time.Hour)
+	source := staticSource{
+		applications: map[string][]models.Application{"prod": {app}, "staging": {app}},
+		observedAt:   map[string]time.Time{"prod": now.Add(-10 * time.Second), "staging": now.Add(-10 * time.Minute)},
+	}
+	NewRecorder(source, store, time.Minute, logrus.New()).Record(ctx, now)
+
+	for cluster, want := range map[string]int{"prod": 1, "staging": 0} {
+		key := storage.ApplicationKey{Cluster: cluster, Namespace: "shop", Name: "checkout-api"}
+		samples, err := store.StatusHistory(ctx, key, start, now.Add(time.Minute))
+		if err != nil {
+			t.Fatalf("StatusHistory() error = %v", err)
+		}
+		if len(samples) != want {
+			t.Errorf("cluster %s has %d samples, want %d", cluster, len(samples), want)
+		}
+	}
+}
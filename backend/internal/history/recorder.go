@@ -0,0 +1,112 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/models"
+	"k8s-monitor/internal/storage"
+	"k8s-monitor/pkg/utils"
+)
+
+// staleIntervals is the number of recording intervals after which the last observation of
+// a cluster is too old to be recorded
+const staleIntervals = 2
+
+// Source provides the last observed applications of every cluster, and when each cluster
+// was last observed successfully
+type Source interface {
+	Applications() map[string][]models.Application
+	ObservedAt() map[string]time.Time
+}
+
+// Recorder periodically stores a status sample of every observed application
+type Recorder struct {
+	source   Source
+	store    storage.Store
+	interval time.Duration
+	logger   *logrus.Entry
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewRecorder creates a recorder that snapshots the source every interval
+func NewRecorder(source Source, store storage.Store, interval time.Duration, logger *logrus.Logger) *Recorder {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &Recorder{
+		source:   source,
+		store:    store,
+		interval: interval,
+		logger:   utils.WithComponent(logger, "history-recorder"),
+	}
+}
+
+// Start begins recording in the background
+func (r *Recorder) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				r.Record(ctx, now)
+			}
+		}
+	}()
+}
+
+// Stop ends recording
+func (r *Recorder) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	<-r.done
+}
+
+// Record stores one sample per observed application. Clusters that were not observed
+// recently are skipped, so an outage shows as a gap rather than as the last known status.
+func (r *Recorder) Record(ctx context.Context, now time.Time) {
+	observedAt := r.source.ObservedAt()
+
+	var samples []models.StatusSample
+	for cluster, applications := range r.source.Applications() {
+		if now.Sub(observedAt[cluster]) > staleIntervals*r.interval {
+			r.logger.WithField("cluster", cluster).Debug("Skipped recording a cluster that was not observed recently")
+			continue
+		}
+		for _, app := range applications {
+			samples = append(samples, models.StatusSample{
+				Cluster:   cluster,
+				Namespace: app.Namespace,
+				Name:      app.Name,
+				Timestamp: now,
+				Status:    app.Status,
+				Summary:   app.Summary,
+			})
+		}
+	}
+	if len(samples) == 0 {
+		return
+	}
+
+	if err := r.store.AppendStatus(ctx, samples); err != nil {
+		r.logger.WithError(err).Error("Failed to record application history")
+		return
+	}
+	r.logger.WithField("applications", len(samples)).Debug("Recorded application history")
+}
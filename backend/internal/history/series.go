@@ -0,0 +1,193 @@
+package history
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s-monitor/internal/models"
+)
+
+// niceSteps are the steps a downsampled series snaps to
+var niceSteps = []time.Duration{
+	time.Minute, 2 * time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 2 * time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour, 24 * time.Hour,
+}
+
+// Window is a named duration ending now, such as 24h or 7d
+type Window struct {
+	Name     string
+	Duration time.Duration
+}
+
+// ParseWindow parses a Go duration, with an additional "d" unit for days
+func ParseWindow(value string) (Window, error) {
+	value = strings.TrimSpace(value)
+
+	var duration time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return Window{}, fmt.Errorf("invalid window %q", value)
+		}
+		duration = time.Duration(n) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return Window{}, fmt.Errorf("invalid window %q", value)
+		}
+		duration = parsed
+	}
+
+	if duration <= 0 {
+		return Window{}, fmt.Errorf("window %q must be positive", value)
+	}
+	return Window{Name: value, Duration: duration}, nil
+}
+
+// ParseWindows parses a list of windows
+func ParseWindows(values []string) ([]Window, error) {
+	windows := make([]Window, 0, len(values))
+	for _, value := range values {
+		window, err := ParseWindow(value)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// statusRank orders statuses from best to worst
+func statusRank(status string) int {
+	switch models.ApplicationStatus(status) {
+	case models.StatusHealthy:
+		return 0
+	case models.StatusUnknown:
+		return 1
+	case models.StatusDegraded:
+		return 2
+	case models.StatusUnhealthy:
+		return 3
+	}
+	return 1
+}
+
+// Step returns the step of a series over [from, to): the requested step, raised so that the
+// series has at most maxPoints points and never finer than the recording interval. Raised
+// steps snap to a round duration.
+func Step(from, to time.Time, requested, interval time.Duration, maxPoints int) time.Duration {
+	step := max(requested, interval)
+	if maxPoints <= 0 {
+		return step
+	}
+
+	minimum := (to.Sub(from) + time.Duration(maxPoints) - 1) / time.Duration(maxPoints)
+	if step >= minimum {
+		return step
+	}
+	for _, nice := range niceSteps {
+		if nice >= minimum {
+			return nice
+		}
+	}
+	// Whole days beyond the largest nice step
+	days := (minimum + 24*time.Hour - 1) / (24 * time.Hour)
+	return days * 24 * time.Hour
+}
+
+// Downsample aggregates samples ordered by time into one point per step. Steps without
+// samples are omitted.
+func Downsample(samples []models.StatusSample, step time.Duration) []models.HistoryPoint {
+	points := []models.HistoryPoint{}
+
+	var totalPods, readyPods int
+	for _, sample := range samples {
+		bucket := sample.Timestamp.Truncate(step)
+
+		if len(points) == 0 || !points[len(points)-1].Timestamp.Equal(bucket) {
+			finishPoint(points, totalPods, readyPods)
+			totalPods, readyPods = 0, 0
+			points = append(points, models.HistoryPoint{
+				Timestamp: bucket,
+				Status:    sample.Status,
+				Statuses:  make(map[string]int),
+			})
+		}
+
+		point := &points[len(points)-1]
+		point.Samples++
+		point.Statuses[sample.Status]++
+		if statusRank(sample.Status) > statusRank(point.Status) {
+			point.Status = sample.Status
+		}
+		point.RestartCount = max(point.RestartCount, sample.Summary.RestartCount)
+		totalPods += sample.Summary.TotalPods
+		readyPods += sample.Summary.ReadyPods
+	}
+	finishPoint(points, totalPods, readyPods)
+
+	return points
+}
+
+// finishPoint averages the pod counts of the last point
+func finishPoint(points []models.HistoryPoint, totalPods, readyPods int) {
+	if len(points) == 0 {
+		return
+	}
+	point := &points[len(points)-1]
+	point.TotalPods = float64(totalPods) / float64(point.Samples)
+	point.ReadyPods = float64(readyPods) / float64(point.Samples)
+}
+
+// Uptime computes how available an application was over [from, to). Each sample holds
+// until the next one, but at most maxGap; longer gaps count as unobserved.
+func Uptime(samples []models.StatusSample, from, to time.Time, maxGap time.Duration) models.UptimeWindow {
+	window := models.UptimeWindow{From: from, To: to}
+
+	var observed, healthy, available time.Duration
+	for i, sample := range samples {
+		end := sample.Timestamp.Add(maxGap)
+		if i+1 < len(samples) && samples[i+1].Timestamp.Before(end) {
+			end = samples[i+1].Timestamp
+		}
+
+		start := sample.Timestamp
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if !end.After(start) {
+			continue
+		}
+
+		span := end.Sub(start)
+		observed += span
+		if sample.Status == string(models.StatusHealthy) {
+			healthy += span
+		}
+		if sample.Summary.ReadyPods > 0 {
+			available += span
+		}
+	}
+
+	if total := to.Sub(from); total > 0 {
+		window.CoveragePercent = percent(observed, total)
+	}
+	if observed > 0 {
+		uptime := percent(healthy, observed)
+		availability := percent(available, observed)
+		window.UptimePercent = &uptime
+		window.AvailabilityPercent = &availability
+	}
+	return window
+}
+
+// percent returns part/total as a percentage rounded to three decimals
+func percent(part, total time.Duration) float64 {
+	value := float64(part) / float64(total) * 100
+	return float64(int64(value*1000+0.5)) / 1000
+}
@@ -0,0 +1,97 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+	"k8s-monitor/internal/storage"
+)
+
+// gapIntervals is how many recording intervals a sample holds before the application
+// counts as unobserved, tolerating late polls
+const gapIntervals = 3
+
+// Query selects the history of one application
+type Query struct {
+	From    time.Time
+	To      time.Time
+	Step    time.Duration // zero picks a step from the range
+	Windows []Window      // nil uses the configured windows
+}
+
+// Service reads recorded history and computes uptime
+type Service struct {
+	store     storage.Store
+	interval  time.Duration
+	maxPoints int
+	windows   []Window
+}
+
+// NewService creates a history reader. The configured uptime windows must be valid.
+func NewService(store storage.Store, cfg config.HistoryConfig) (*Service, error) {
+	windows, err := ParseWindows(cfg.UptimeWindows)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := time.Duration(cfg.Interval) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &Service{
+		store:     store,
+		interval:  interval,
+		maxPoints: cfg.MaxPoints,
+		windows:   windows,
+	}, nil
+}
+
+// ApplicationHistory returns the downsampled status series of an application over the
+// query range and its uptime over windows ending now
+func (s *Service) ApplicationHistory(ctx context.Context, key storage.ApplicationKey, query Query, now time.Time) (*models.ApplicationHistoryResponse, error) {
+	step := Step(query.From, query.To, query.Step, s.interval, s.maxPoints)
+
+	samples, err := s.store.StatusHistory(ctx, key, query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.ApplicationHistoryResponse{
+		Application: key.Name,
+		Namespace:   key.Namespace,
+		Cluster:     key.Cluster,
+		From:        query.From,
+		To:          query.To,
+		Step:        step.String(),
+		Points:      Downsample(samples, step),
+		Uptime:      []models.UptimeWindow{},
+	}
+
+	windows := query.Windows
+	if windows == nil {
+		windows = s.windows
+	}
+	if len(windows) == 0 {
+		return response, nil
+	}
+
+	// Read the longest window once, including the sample that covers its start
+	maxGap := gapIntervals * s.interval
+	longest := windows[0].Duration
+	for _, window := range windows[1:] {
+		longest = max(longest, window.Duration)
+	}
+	samples, err = s.store.StatusHistory(ctx, key, now.Add(-longest-maxGap), now)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, window := range windows {
+		uptime := Uptime(samples, now.Add(-window.Duration), now, maxGap)
+		uptime.Window = window.Name
+		response.Uptime = append(response.Uptime, uptime)
+	}
+	return response, nil
+}
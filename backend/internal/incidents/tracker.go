@@ -0,0 +1,449 @@
+package incidents
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/models"
+	"k8s-monitor/internal/services"
+	"k8s-monitor/internal/storage"
+	"k8s-monitor/pkg/utils"
+)
+
+const (
+	// maxIncidentEvents bounds the number of related events kept per incident
+	maxIncidentEvents = 50
+	// eventLookback includes events shortly before an incident started, they often explain it
+	eventLookback = 5 * time.Minute
+	// eventFetchTimeout bounds fetching the related events of one incident
+	eventFetchTimeout = 10 * time.Second
+)
+
+// Source provides the observed applications and their changes
+type Source interface {
+	Applications() map[string][]models.Application
+	Subscribe(afterID uint64) (*services.Subscription, []models.ApplicationEvent, error)
+}
+
+// EventFetcher returns the Kubernetes events of an application
+type EventFetcher func(ctx context.Context, cluster, namespace, name string) ([]models.EventInfo, error)
+
+// ClusterEvents fetches application events from the clusters of the registry
+func ClusterEvents(clusters *services.ClusterManager) EventFetcher {
+	return func(ctx context.Context, cluster, namespace, name string) ([]models.EventInfo, error) {
+		c, err := clusters.Get(cluster)
+		if err != nil {
+			return nil, err
+		}
+		response, err := c.Applications.GetApplicationEvents(ctx, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		return response.Events, nil
+	}
+}
+
+// Tracker opens an incident when an application leaves the healthy status and closes it
+// when the application recovers or is removed. Incidents are persisted in the store on
+// every change; open incidents are also kept in memory to apply transitions.
+type Tracker struct {
+	source      Source
+	store       storage.Store
+	fetchEvents EventFetcher
+	interval    time.Duration
+	logger      *logrus.Entry
+
+	mu   sync.Mutex
+	open map[string]*models.Incident // cluster/namespace/name -> open incident
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewTracker creates a tracker over the source. interval is how often the tracker reconciles
+// with the source's observations, which covers the baseline and missed changes.
+func NewTracker(source Source, store storage.Store, fetchEvents EventFetcher, interval time.Duration, logger *logrus.Logger) *Tracker {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &Tracker{
+		source:      source,
+		store:       store,
+		fetchEvents: fetchEvents,
+		interval:    interval,
+		logger:      utils.WithComponent(logger, "incident-tracker"),
+		open:        make(map[string]*models.Incident),
+	}
+}
+
+// Start resumes the open incidents of the previous run and begins tracking application
+// changes in the background
+func (t *Tracker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+	t.done = make(chan struct{})
+
+	if err := t.Restore(ctx); err != nil {
+		t.logger.WithError(err).Error("Failed to restore open incidents")
+	}
+
+	go func() {
+		defer close(t.done)
+		t.run(ctx)
+	}()
+}
+
+// Stop ends tracking
+func (t *Tracker) Stop() {
+	if t.cancel == nil {
+		return
+	}
+	t.cancel()
+	<-t.done
+}
+
+func (t *Tracker) run(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	sub, _, _ := t.source.Subscribe(0)
+	defer func() { sub.Close() }()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			t.Reconcile(ctx, now)
+		case event, ok := <-sub.Events:
+			if !ok {
+				// Dropped for lagging behind: resubscribe and catch up on missed changes
+				t.logger.Warn("Application event subscription closed, resubscribing")
+				sub, _, _ = t.source.Subscribe(0)
+				t.Reconcile(ctx, time.Now())
+				continue
+			}
+			t.persist(ctx, t.handle(event))
+		}
+	}
+}
+
+// Restore loads the incidents left open by the previous run
+func (t *Tracker) Restore(ctx context.Context) error {
+	incidents, err := t.store.ListIncidents(ctx, storage.IncidentFilter{Status: models.IncidentOpen})
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i := range incidents {
+		incident := incidents[i]
+		t.open[incidentKey(incident.Cluster, incident.Namespace, incident.Application)] = &incident
+	}
+	if len(incidents) > 0 {
+		t.logger.WithField("open", len(incidents)).Info("Restored open incidents")
+	}
+	return nil
+}
+
+// handle applies one application event and returns a copy of the incident it changed, if any
+func (t *Tracker) handle(event models.ApplicationEvent) []models.Incident {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var incident *models.Incident
+	switch event.Type {
+	case models.ApplicationRemoved:
+		incident = t.removeLocked(event.Cluster, event.Namespace, event.Name, event.Timestamp)
+	default:
+		if event.Application != nil {
+			incident = t.observeLocked(event.Cluster, *event.Application, event.Timestamp)
+		}
+	}
+	if incident == nil {
+		return nil
+	}
+	return []models.Incident{clone(incident)}
+}
+
+// Observe records the current state of one application and persists the resulting changes
+func (t *Tracker) Observe(ctx context.Context, cluster string, app models.Application, now time.Time) {
+	t.persist(ctx, t.handle(models.ApplicationEvent{
+		Type:        models.ApplicationUpdated,
+		Cluster:     cluster,
+		Namespace:   app.Namespace,
+		Name:        app.Name,
+		Application: &app,
+		Timestamp:   now,
+	}))
+}
+
+// Reconcile compares open incidents with the source's last observation and persists the
+// resulting changes. Clusters missing from the observation keep their incidents open,
+// their applications are unknown rather than removed.
+func (t *Tracker) Reconcile(ctx context.Context, now time.Time) {
+	t.persist(ctx, t.reconcile(now))
+}
+
+func (t *Tracker) reconcile(now time.Time) []models.Incident {
+	applications := t.source.Applications()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var changed []models.Incident
+	seen := make(map[string]bool)
+	for cluster, apps := range applications {
+		for _, app := range apps {
+			seen[incidentKey(cluster, app.Namespace, app.Name)] = true
+			if incident := t.observeLocked(cluster, app, now); incident != nil {
+				changed = append(changed, clone(incident))
+			}
+		}
+	}
+	for key, incident := range t.open {
+		if _, observed := applications[incident.Cluster]; !observed || seen[key] {
+			continue
+		}
+		if closed := t.removeLocked(incident.Cluster, incident.Namespace, incident.Application, now); closed != nil {
+			changed = append(changed, clone(closed))
+		}
+	}
+	return changed
+}
+
+// observeLocked opens, updates or closes the incident of an application. It returns the
+// incident when it changed. Only degraded and unhealthy applications open incidents: an
+// unknown status is also reported for jobs whose pods all completed.
+func (t *Tracker) observeLocked(cluster string, app models.Application, now time.Time) *models.Incident {
+	key := incidentKey(cluster, app.Namespace, app.Name)
+	incident := t.open[key]
+	healthy := app.Status == string(models.StatusHealthy)
+	failing := app.Status == string(models.StatusDegraded) || app.Status == string(models.StatusUnhealthy)
+
+	switch {
+	case incident == nil && !failing:
+		return nil
+
+	case incident == nil:
+		incident = &models.Incident{
+			ID:          incidentID(key, now),
+			Cluster:     cluster,
+			Namespace:   app.Namespace,
+			Application: app.Name,
+			Status:      models.IncidentOpen,
+			StartedAt:   now,
+			Events:      []models.EventInfo{},
+		}
+		t.open[key] = incident
+
+		mergePods(incident, app)
+		t.transitionLocked(incident, app, now, fmt.Sprintf("Application became %s (%d/%d pods ready)",
+			app.Status, app.Summary.ReadyPods, app.Summary.TotalPods))
+		t.logger.WithFields(logrus.Fields{
+			"cluster":     cluster,
+			"namespace":   app.Namespace,
+			"application": app.Name,
+			"status":      app.Status,
+		}).Info("Opened incident")
+		return incident
+
+	case healthy:
+		t.transitionLocked(incident, app, now, fmt.Sprintf("Application recovered (%d/%d pods ready)",
+			app.Summary.ReadyPods, app.Summary.TotalPods))
+		t.closeLocked(key, incident, now)
+		return incident
+	}
+
+	podsChanged := mergePods(incident, app)
+	if app.Status == incident.CurrentStatus {
+		if podsChanged {
+			return incident
+		}
+		return nil
+	}
+	t.transitionLocked(incident, app, now, fmt.Sprintf("Application became %s (%d/%d pods ready)",
+		app.Status, app.Summary.ReadyPods, app.Summary.TotalPods))
+	return incident
+}
+
+// removeLocked closes the open incident of a removed application
+func (t *Tracker) removeLocked(cluster, namespace, name string, now time.Time) *models.Incident {
+	key := incidentKey(cluster, namespace, name)
+	incident, ok := t.open[key]
+	if !ok {
+		return nil
+	}
+	incident.Updates = append(incident.Updates, models.IncidentUpdate{
+		Timestamp: now,
+		Status:    incident.CurrentStatus,
+		Message:   "Application was removed",
+	})
+	incident.Description = "Application was removed"
+	t.closeLocked(key, incident, now)
+	return incident
+}
+
+// transitionLocked records a status change and raises the peak severity
+func (t *Tracker) transitionLocked(incident *models.Incident, app models.Application, now time.Time, message string) {
+	incident.CurrentStatus = app.Status
+	incident.Description = message
+	incident.Updates = append(incident.Updates, models.IncidentUpdate{
+		Timestamp: now,
+		Status:    app.Status,
+		Message:   message,
+	})
+
+	if app.Status == string(models.StatusHealthy) {
+		return
+	}
+	if severity := models.SeverityForStatus(app.Status); severity.Rank() > incident.Severity.Rank() {
+		incident.Severity = severity
+		incident.Title = fmt.Sprintf("%s/%s is %s", app.Namespace, app.Name, app.Status)
+	}
+}
+
+func (t *Tracker) closeLocked(key string, incident *models.Incident, now time.Time) {
+	endedAt := now
+	incident.Status = models.IncidentClosed
+	incident.EndedAt = &endedAt
+	delete(t.open, key)
+
+	t.logger.WithFields(logrus.Fields{
+		"cluster":     incident.Cluster,
+		"namespace":   incident.Namespace,
+		"application": incident.Application,
+		"duration":    now.Sub(incident.StartedAt).Round(time.Second).String(),
+	}).Info("Closed incident")
+}
+
+// persist attaches the related Kubernetes events to changed incidents and saves them
+func (t *Tracker) persist(ctx context.Context, changed []models.Incident) {
+	for _, incident := range changed {
+		logger := t.logger.WithFields(logrus.Fields{
+			"cluster":     incident.Cluster,
+			"namespace":   incident.Namespace,
+			"application": incident.Application,
+		})
+
+		if t.fetchEvents != nil {
+			fetchCtx, cancel := context.WithTimeout(ctx, eventFetchTimeout)
+			events, err := t.fetchEvents(fetchCtx, incident.Cluster, incident.Namespace, incident.Application)
+			cancel()
+			if err != nil {
+				logger.WithError(err).Warn("Failed to fetch incident events")
+			} else {
+				mergeEvents(&incident, events)
+
+				// Keep the events of open incidents for their next change
+				t.mu.Lock()
+				if open, ok := t.open[incidentKey(incident.Cluster, incident.Namespace, incident.Application)]; ok && open.ID == incident.ID {
+					open.Events = append([]models.EventInfo{}, incident.Events...)
+				}
+				t.mu.Unlock()
+			}
+		}
+
+		if err := t.store.SaveIncident(ctx, incident); err != nil {
+			logger.WithError(err).Error("Failed to save incident")
+		}
+	}
+}
+
+// List returns the incidents matching the filter, most recent first
+func (t *Tracker) List(ctx context.Context, filter storage.IncidentFilter) ([]models.Incident, error) {
+	return t.store.ListIncidents(ctx, filter)
+}
+
+// Get returns one incident by ID, or storage.ErrNotFound
+func (t *Tracker) Get(ctx context.Context, id string) (models.Incident, error) {
+	return t.store.GetIncident(ctx, id)
+}
+
+// mergePods adds the application's pods that are not ready to the affected pods and reports
+// whether any was added
+func mergePods(incident *models.Incident, app models.Application) bool {
+	affected := make(map[string]bool, len(incident.AffectedPods))
+	for _, name := range incident.AffectedPods {
+		affected[name] = true
+	}
+	added := false
+	for _, pod := range app.Pods {
+		if pod.Ready || pod.Status == "Succeeded" || affected[pod.Name] {
+			continue
+		}
+		affected[pod.Name] = true
+		incident.AffectedPods = append(incident.AffectedPods, pod.Name)
+		added = true
+	}
+	if incident.AffectedPods == nil {
+		incident.AffectedPods = []string{}
+	}
+	sort.Strings(incident.AffectedPods)
+	return added
+}
+
+// mergeEvents keeps the events that overlap the incident, replacing older observations of
+// the same object and reason
+func mergeEvents(incident *models.Incident, events []models.EventInfo) {
+	from := incident.StartedAt.Add(-eventLookback)
+
+	type eventKey struct {
+		object models.ObjectReference
+		reason string
+	}
+	merged := make(map[eventKey]models.EventInfo, len(incident.Events)+len(events))
+	for _, event := range incident.Events {
+		merged[eventKey{event.Object, event.Reason}] = event
+	}
+	for _, event := range events {
+		if event.LastSeen.Before(from) || (incident.EndedAt != nil && event.FirstSeen.After(*incident.EndedAt)) {
+			continue
+		}
+		merged[eventKey{event.Object, event.Reason}] = event
+	}
+
+	incident.Events = make([]models.EventInfo, 0, len(merged))
+	for _, event := range merged {
+		incident.Events = append(incident.Events, event)
+	}
+	sort.Slice(incident.Events, func(i, j int) bool {
+		if !incident.Events[i].LastSeen.Equal(incident.Events[j].LastSeen) {
+			return incident.Events[i].LastSeen.Before(incident.Events[j].LastSeen)
+		}
+		return incident.Events[i].Reason < incident.Events[j].Reason
+	})
+	if len(incident.Events) > maxIncidentEvents {
+		incident.Events = incident.Events[len(incident.Events)-maxIncidentEvents:]
+	}
+}
+
+// clone copies an incident so it can be used without holding the lock
+func clone(incident *models.Incident) models.Incident {
+	c := *incident
+	c.AffectedPods = append([]string{}, incident.AffectedPods...)
+	c.Updates = append([]models.IncidentUpdate{}, incident.Updates...)
+	c.Events = append([]models.EventInfo{}, incident.Events...)
+	if incident.EndedAt != nil {
+		endedAt := *incident.EndedAt
+		c.EndedAt = &endedAt
+	}
+	return c
+}
+
+func incidentKey(cluster, namespace, name string) string {
+	return cluster + "/" + namespace + "/" + name
+}
+
+func incidentID(key string, startedAt time.Time) string {
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%s\x00%d", key, startedAt.UnixNano())
+	return fmt.Sprintf("%016x", hash.Sum64())
+}
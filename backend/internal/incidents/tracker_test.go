@@ -0,0 +1,187 @@
+package incidents
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/models"
+	"k8s-monitor/internal/services"
+	"k8s-monitor/internal/storage"
+)
+
+// staticSource serves a mutable observation
+type staticSource struct {
+	applications map[string][]models.Application
+}
+
+func (s *staticSource) Applications() map[string][]models.Application { return s.applications }
+
+func (s *staticSource) Subscribe(uint64) (*services.Subscription, []models.ApplicationEvent, error) {
+	return nil, nil, errors.New("not supported")
+}
+
+func newTestTracker(source Source, store storage.Store, fetch EventFetcher) *Tracker {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewTracker(source, store, fetch, time.Second, logger)
+}
+
+func list(t *testing.T, tracker *Tracker, filter storage.IncidentFilter) []models.Incident {
+	t.Helper()
+	incidents, err := tracker.List(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	return incidents
+}
+
+func application(name, status string, readyPods int, notReady ...string) models.Application {
+	app := models.Application{
+		Name:      name,
+		Namespace: "shop",
+		Status:    status,
+		Summary:   models.ApplicationSummary{TotalPods: 3, ReadyPods: readyPods},
+	}
+	for _, pod := range notReady {
+		app.Pods = append(app.Pods, models.PodStatus{Name: pod, Status: "Running"})
+	}
+	app.Pods = append(app.Pods, models.PodStatus{Name: name + "-ready", Status: "Running", Ready: true})
+	return app
+}
+
+func TestTrackerLifecycle(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	fetch := func(ctx context.Context, cluster, namespace, name string) ([]models.EventInfo, error) {
+		return []models.EventInfo{
+			{Type: models.EventTypeWarning, Reason: "BackOff", Object: models.ObjectReference{Kind: "Pod", Name: "api-1"},
+				FirstSeen: start, LastSeen: start.Add(time.Minute)},
+			{Type: models.EventTypeNormal, Reason: "Scheduled", Object: models.ObjectReference{Kind: "Pod", Name: "api-0"},
+				FirstSeen: start.Add(-time.Hour), LastSeen: start.Add(-time.Hour)},
+		}, nil
+	}
+	ctx := context.Background()
+	tracker := newTestTracker(&staticSource{}, storage.NewMemoryStore(), fetch)
+
+	tracker.Observe(ctx, "prod", application("api", "healthy", 3), start)
+	if incidents := list(t, tracker, storage.IncidentFilter{}); len(incidents) != 0 {
+		t.Fatalf("healthy application opened an incident")
+	}
+
+	tracker.Observe(ctx, "prod", application("api", "degraded", 2, "api-1"), start)
+	// Pods change without a status transition
+	tracker.Observe(ctx, "prod", application("api", "degraded", 2, "api-2"), start.Add(time.Minute))
+	tracker.Observe(ctx, "prod", application("api", "unhealthy", 0, "api-1", "api-2"), start.Add(2*time.Minute))
+	tracker.Observe(ctx, "prod", application("api", "degraded", 2, "api-1"), start.Add(3*time.Minute))
+
+	open := list(t, tracker, storage.IncidentFilter{Status: models.IncidentOpen})
+	if len(open) != 1 {
+		t.Fatalf("got %d open incidents, want 1", len(open))
+	}
+	incident := open[0]
+	if incident.Severity != models.SeverityCritical || incident.CurrentStatus != "degraded" || incident.Title != "shop/api is unhealthy" {
+		t.Errorf("unexpected incident %+v", incident)
+	}
+	if want := []string{"api-1", "api-2"}; !reflect.DeepEqual(incident.AffectedPods, want) {
+		t.Errorf("AffectedPods = %v, want %v", incident.AffectedPods, want)
+	}
+	if len(incident.Events) != 1 || incident.Events[0].Reason != "BackOff" {
+		t.Errorf("Events = %+v, want the BackOff event only", incident.Events)
+	}
+
+	tracker.Observe(ctx, "prod", application("api", "healthy", 3), start.Add(10*time.Minute))
+	incident, err := tracker.Get(ctx, incident.ID)
+	if err != nil {
+		t.Fatalf("Get(%s) error = %v", incident.ID, err)
+	}
+	if incident.Status != models.IncidentClosed || incident.EndedAt == nil || !incident.EndedAt.Equal(start.Add(10*time.Minute)) {
+		t.Errorf("incident not closed: %+v", incident)
+	}
+	var statuses []string
+	for _, update := range incident.Updates {
+		statuses = append(statuses, update.Status)
+	}
+	if want := []string{"degraded", "unhealthy", "degraded", "healthy"}; !reflect.DeepEqual(statuses, want) {
+		t.Errorf("update statuses = %v, want %v", statuses, want)
+	}
+
+	// An unknown status does not open an incident, a new failure does
+	tracker.Observe(ctx, "prod", application("api", "unknown", 0), start.Add(time.Hour))
+	if all := list(t, tracker, storage.IncidentFilter{Application: "api"}); len(all) != 1 {
+		t.Errorf("unknown status opened an incident: %+v", all)
+	}
+	tracker.Observe(ctx, "prod", application("api", "degraded", 2, "api-1"), start.Add(2*time.Hour))
+	all := list(t, tracker, storage.IncidentFilter{Application: "api"})
+	if len(all) != 2 || all[0].Status != models.IncidentOpen || all[0].Severity != models.SeverityMajor {
+		t.Errorf("unexpected incidents %+v", all)
+	}
+}
+
+func TestTrackerIgnoresCompletedJobs(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	pods := []models.PodStatus{{Name: "report-28901-x7k2p", Status: "Succeeded"}}
+	job := models.Application{
+		Name:      "report",
+		Namespace: "shop",
+		Type:      string(models.TypeCronJob),
+		Status:    string(models.DetermineApplicationStatus(pods)),
+		Summary:   models.ApplicationSummary{TotalPods: 1},
+		Pods:      pods,
+	}
+	source := &staticSource{applications: map[string][]models.Application{"prod": {job}}}
+	ctx := context.Background()
+	tracker := newTestTracker(source, storage.NewMemoryStore(), nil)
+
+	tracker.Reconcile(ctx, now)
+	// The job's pods are garbage-collected
+	source.applications = map[string][]models.Application{"prod": {}}
+	tracker.Reconcile(ctx, now.Add(time.Hour))
+
+	if incidents := list(t, tracker, storage.IncidentFilter{}); len(incidents) != 0 {
+		t.Errorf("completed job opened incidents %+v", incidents)
+	}
+}
+
+func TestTrackerReconcile(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	source := &staticSource{applications: map[string][]models.Application{
+		"prod":    {application("api", "unhealthy", 0), application("web", "healthy", 3)},
+		"staging": {application("api", "degraded", 1)},
+	}}
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+	tracker := newTestTracker(source, store, nil)
+
+	tracker.Reconcile(ctx, now)
+	if incidents := list(t, tracker, storage.IncidentFilter{}); len(incidents) != 2 {
+		t.Fatalf("baseline opened %d incidents, want 2", len(incidents))
+	}
+
+	// Staging is unreachable: its incident stays open. The prod application was removed.
+	source.applications = map[string][]models.Application{"prod": {application("web", "healthy", 3)}}
+	tracker.Reconcile(ctx, now.Add(time.Minute))
+
+	closed := list(t, tracker, storage.IncidentFilter{Status: models.IncidentClosed})
+	if len(closed) != 1 || closed[0].Cluster != "prod" || closed[0].Description != "Application was removed" {
+		t.Errorf("unexpected closed incidents %+v", closed)
+	}
+	open := list(t, tracker, storage.IncidentFilter{Cluster: "staging", Namespace: "shop"})
+	if len(open) != 1 || open[0].Status != models.IncidentOpen {
+		t.Errorf("unexpected staging incidents %+v", open)
+	}
+
+	// A restarted tracker resumes the open incident instead of opening another one
+	restarted := newTestTracker(source, store, nil)
+	if err := restarted.Restore(ctx); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	restarted.Observe(ctx, "staging", application("api", "healthy", 3), now.Add(2*time.Minute))
+	if incidents := list(t, restarted, storage.IncidentFilter{Cluster: "staging"}); len(incidents) != 1 || incidents[0].Status != models.IncidentClosed {
+		t.Errorf("unexpected staging incidents after restart %+v", incidents)
+	}
+}
@@ -0,0 +1,170 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"k8s-monitor/internal/models"
+)
+
+const namespace = "k8s_monitor"
+
+// Known values of the enumerated status gauges
+var (
+	applicationStatuses = []models.ApplicationStatus{
+		models.StatusHealthy,
+		models.StatusDegraded,
+		models.StatusUnhealthy,
+		models.StatusUnknown,
+	}
+	argoSyncStatuses   = []string{"Synced", "OutOfSync", "Unknown"}
+	argoHealthStatuses = []string{"Healthy", "Progressing", "Degraded", "Suspended", "Missing", "Unknown"}
+)
+
+// ApplicationSource provides the last observed applications of every cluster, and when
+// each cluster was last observed successfully
+type ApplicationSource interface {
+	Applications() map[string][]models.Application
+	ArgoCDApplications() map[string][]models.ArgoCDApplication
+	ObservedAt() map[string]time.Time
+}
+
+// ApplicationCollector exports application and ArgoCD health as Prometheus gauges. Values are
+// read from the source's last observation, so a scrape never queries the clusters. The
+// applications of clusters not observed within staleAfter are left out, and the
+// cluster gauges tell when each cluster was last observed.
+type ApplicationCollector struct {
+	source     ApplicationSource
+	staleAfter time.Duration
+	now        func() time.Time
+
+	clusterUp           *prometheus.Desc
+	clusterLastObserved *prometheus.Desc
+
+	status      *prometheus.Desc
+	pods        *prometheus.Desc
+	readyPods   *prometheus.Desc
+	runningPods *prometheus.Desc
+	pendingPods *prometheus.Desc
+	failedPods  *prometheus.Desc
+	restarts    *prometheus.Desc
+	argoSync    *prometheus.Desc
+	argoHealth  *prometheus.Desc
+}
+
+var _ prometheus.Collector = (*ApplicationCollector)(nil)
+
+// NewApplicationCollector creates a collector over the given source, exporting the
+// applications of clusters observed within staleAfter
+func NewApplicationCollector(source ApplicationSource, staleAfter time.Duration) *ApplicationCollector {
+	appLabels := []string{"cluster", "namespace", "application"}
+	desc := func(name, help string, extra ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help,
+			append(append([]string{}, appLabels...), extra...), nil)
+	}
+	clusterDesc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, []string{"cluster"}, nil)
+	}
+
+	return &ApplicationCollector{
+		source:     source,
+		staleAfter: staleAfter,
+		now:        time.Now,
+
+		clusterUp:           clusterDesc("cluster_up", "Whether the applications of the cluster were observed recently, 1 if so and 0 otherwise."),
+		clusterLastObserved: clusterDesc("cluster_last_observed_timestamp_seconds", "Unix time the applications of the cluster were last observed successfully."),
+
+		status:      desc("application_status", "Application health status, 1 for the current status and 0 otherwise.", "status"),
+		pods:        desc("application_pods", "Number of pods of the application."),
+		readyPods:   desc("application_pods_ready", "Number of ready pods of the application."),
+		runningPods: desc("application_pods_running", "Number of running pods of the application."),
+		pendingPods: desc("application_pods_pending", "Number of pending pods of the application."),
+		failedPods:  desc("application_pods_failed", "Number of failed pods of the application."),
+		restarts:    desc("application_container_restarts", "Sum of container restarts across the pods of the application."),
+		argoSync:    desc("argocd_application_sync_status", "ArgoCD application sync status, 1 for the current status and 0 otherwise.", "sync_status"),
+		argoHealth:  desc("argocd_application_health_status", "ArgoCD application health status, 1 for the current status and 0 otherwise.", "health_status"),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *ApplicationCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.clusterUp
+	ch <- c.clusterLastObserved
+	ch <- c.status
+	ch <- c.pods
+	ch <- c.readyPods
+	ch <- c.runningPods
+	ch <- c.pendingPods
+	ch <- c.failedPods
+	ch <- c.restarts
+	ch <- c.argoSync
+	ch <- c.argoHealth
+}
+
+// Collect implements prometheus.Collector
+func (c *ApplicationCollector) Collect(ch chan<- prometheus.Metric) {
+	now := c.now()
+	up := make(map[string]bool)
+	for cluster, observedAt := range c.source.ObservedAt() {
+		up[cluster] = now.Sub(observedAt) <= c.staleAfter
+		ch <- prometheus.MustNewConstMetric(c.clusterUp, prometheus.GaugeValue, boolValue(up[cluster]), cluster)
+		ch <- prometheus.MustNewConstMetric(c.clusterLastObserved, prometheus.GaugeValue,
+			float64(observedAt.UnixNano())/1e9, cluster)
+	}
+
+	for cluster, applications := range c.source.Applications() {
+		if !up[cluster] {
+			continue
+		}
+		for _, app := range applications {
+			labels := []string{cluster, app.Namespace, app.Name}
+
+			for _, status := range applicationStatuses {
+				ch <- prometheus.MustNewConstMetric(c.status, prometheus.GaugeValue,
+					boolValue(app.Status == string(status)), append(labels, string(status))...)
+			}
+
+			gauge := func(desc *prometheus.Desc, value int) {
+				ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(value), labels...)
+			}
+			gauge(c.pods, app.Summary.TotalPods)
+			gauge(c.readyPods, app.Summary.ReadyPods)
+			gauge(c.runningPods, app.Summary.RunningPods)
+			gauge(c.pendingPods, app.Summary.PendingPods)
+			gauge(c.failedPods, app.Summary.FailedPods)
+			gauge(c.restarts, app.Summary.RestartCount)
+		}
+	}
+
+	for cluster, applications := range c.source.ArgoCDApplications() {
+		if !up[cluster] {
+			continue
+		}
+		for _, app := range applications {
+			labels := []string{cluster, app.Namespace, app.Name}
+			collectEnum(ch, c.argoSync, labels, argoSyncStatuses, app.SyncStatus)
+			collectEnum(ch, c.argoHealth, labels, argoHealthStatuses, app.HealthStatus)
+		}
+	}
+}
+
+// collectEnum emits one series per known value, plus one for an unexpected current value
+func collectEnum(ch chan<- prometheus.Metric, desc *prometheus.Desc, labels, values []string, current string) {
+	known := false
+	for _, value := range values {
+		known = known || value == current
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue,
+			boolValue(value == current), append(labels, value)...)
+	}
+	if !known && current != "" {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, 1, append(labels, current)...)
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"k8s-monitor/internal/models"
+)
+
+// staticSource serves fixed observations
+type staticSource struct {
+	applications map[string][]models.Application
+	argo         map[string][]models.ArgoCDApplication
+	observedAt   map[string]time.Time
+}
+
+func (s staticSource) Applications() map[string][]models.Application { return s.applications }
+
+func (s staticSource) ArgoCDApplications() map[string][]models.ArgoCDApplication { return s.argo }
+
+func (s staticSource) ObservedAt() map[string]time.Time { return s.observedAt }
+
+func TestApplicationCollector(t *testing.T) {
+	now := time.Unix(1735732800, 0)
+	api := models.Application{
+		Name:      "api",
+		Namespace: "shop",
+		Status:    string(models.StatusDegraded),
+		Summary:   models.ApplicationSummary{TotalPods: 3, ReadyPods: 2, RunningPods: 3, RestartCount: 7},
+	}
+	collector := NewApplicationCollector(staticSource{
+		// Staging is unreachable, its last observation is out of date
+		applications: map[string][]models.Application{"prod": {api}, "staging": {api}},
+		argo: map[string][]models.ArgoCDApplication{
+			"prod":    {{Name: "shop", Namespace: "argocd", SyncStatus: "OutOfSync", HealthStatus: "Healthy"}},
+			"staging": {{Name: "shop", Namespace: "argocd", SyncStatus: "Synced", HealthStatus: "Healthy"}},
+		},
+		observedAt: map[string]time.Time{"prod": now.Add(-5 * time.Second), "staging": now.Add(-10 * time.Minute)},
+	}, time.Minute)
+	collector.now = func() time.Time { return now }
+
+	expected := `
+# HELP k8s_monitor_cluster_up Whether the applications of the cluster were observed recently, 1 if so and 0 otherwise.
+# TYPE k8s_monitor_cluster_up gauge
+k8s_monitor_cluster_up{cluster="prod"} 1
+k8s_monitor_cluster_up{cluster="staging"} 0
+# HELP k8s_monitor_cluster_last_observed_timestamp_seconds Unix time the applications of the cluster were last observed successfully.
+# TYPE k8s_monitor_cluster_last_observed_timestamp_seconds gauge
+k8s_monitor_cluster_last_observed_timestamp_seconds{cluster="prod"} 1.735732795e+09
+k8s_monitor_cluster_last_observed_timestamp_seconds{cluster="staging"} 1.7357322e+09
+# HELP k8s_monitor_application_status Application health status, 1 for the current status and 0 otherwise.
+# TYPE k8s_monitor_application_status gauge
+k8s_monitor_application_status{application="api",cluster="prod",namespace="shop",status="degraded"} 1
+k8s_monitor_application_status{application="api",cluster="prod",namespace="shop",status="healthy"} 0
+k8s_monitor_application_status{application="api",cluster="prod",namespace="shop",status="unhealthy"} 0
+k8s_monitor_application_status{application="api",cluster="prod",namespace="shop",status="unknown"} 0
+# HELP k8s_monitor_application_pods_ready Number of ready pods of the application.
+# TYPE k8s_monitor_application_pods_ready gauge
+k8s_monitor_application_pods_ready{application="api",cluster="prod",namespace="shop"} 2
+# HELP k8s_monitor_application_container_restarts Sum of container restarts across the pods of the application.
+# TYPE k8s_monitor_application_container_restarts gauge
+k8s_monitor_application_container_restarts{application="api",cluster="prod",namespace="shop"} 7
+# HELP k8s_monitor_argocd_application_sync_status ArgoCD application sync status, 1 for the current status and 0 otherwise.
+# TYPE k8s_monitor_argocd_application_sync_status gauge
+k8s_monitor_argocd_application_sync_status{application="shop",cluster="prod",namespace="argocd",sync_status="OutOfSync"} 1
+k8s_monitor_argocd_application_sync_status{application="shop",cluster="prod",namespace="argocd",sync_status="Synced"} 0
+k8s_monitor_argocd_application_sync_status{application="shop",cluster="prod",namespace="argocd",sync_status="Unknown"} 0
+`
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"k8s_monitor_cluster_up",
+		"k8s_monitor_cluster_last_observed_timestamp_seconds",
+		"k8s_monitor_application_status",
+		"k8s_monitor_application_pods_ready",
+		"k8s_monitor_application_container_restarts",
+		"k8s_monitor_argocd_application_sync_status",
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if count := testutil.CollectAndCount(collector, "k8s_monitor_argocd_application_health_status"); count != len(argoHealthStatuses) {
+		t.Errorf("got %d health status series, want %d", count, len(argoHealthStatuses))
+	}
+}
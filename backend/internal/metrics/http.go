@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unmatchedRoute labels requests that did not match any route, keeping label cardinality bounded
+const unmatchedRoute = "unmatched"
+
+// HTTPMetrics measures the requests served by the API server
+type HTTPMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+var _ prometheus.Collector = (*HTTPMetrics)(nil)
+
+// NewHTTPMetrics creates the HTTP server metrics
+func NewHTTPMetrics() *HTTPMetrics {
+	return &HTTPMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests served, by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of HTTP requests, by method, route and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+	}
+}
+
+// Observe records one served request. Route is the matched route pattern, not the raw path.
+func (m *HTTPMetrics) Observe(method, route string, status int, duration time.Duration) {
+	if route == "" {
+		route = unmatchedRoute
+	}
+	code := strconv.Itoa(status)
+	m.requests.WithLabelValues(method, route, code).Inc()
+	m.duration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+// Describe implements prometheus.Collector
+func (m *HTTPMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.duration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (m *HTTPMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.duration.Collect(ch)
+}
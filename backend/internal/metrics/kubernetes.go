@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// KubernetesClientMetrics measures the requests sent to the Kubernetes API servers
+type KubernetesClientMetrics struct {
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+var _ prometheus.Collector = (*KubernetesClientMetrics)(nil)
+
+// NewKubernetesClientMetrics creates the Kubernetes client metrics
+func NewKubernetesClientMetrics() *KubernetesClientMetrics {
+	labels := []string{"cluster", "verb", "resource"}
+	return &KubernetesClientMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "kubernetes_client",
+			Name:      "requests_total",
+			Help:      "Number of Kubernetes API requests, by cluster, verb, resource and status code.",
+		}, append(labels, "code")),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "kubernetes_client",
+			Name:      "request_errors_total",
+			Help:      "Number of Kubernetes API requests that failed or returned an error status, by cluster, verb and resource.",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "kubernetes_client",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of Kubernetes API requests until response headers, by cluster, verb and resource.",
+			Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}, labels),
+	}
+}
+
+// WrapTransport instruments the HTTP transport of a cluster's clients. It matches
+// services.TransportWrapper.
+func (m *KubernetesClientMetrics) WrapTransport(cluster string, rt http.RoundTripper) http.RoundTripper {
+	return &instrumentedTransport{cluster: cluster, next: rt, metrics: m}
+}
+
+// Describe implements prometheus.Collector
+func (m *KubernetesClientMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.errors.Describe(ch)
+	m.duration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (m *KubernetesClientMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.errors.Collect(ch)
+	m.duration.Collect(ch)
+}
+
+// instrumentedTransport records latency and outcome of every round trip
+type instrumentedTransport struct {
+	cluster string
+	next    http.RoundTripper
+	metrics *KubernetesClientMetrics
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	duration := time.Since(start)
+
+	verb, resource := requestVerbResource(req)
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+
+	t.metrics.requests.WithLabelValues(t.cluster, verb, resource, code).Inc()
+	t.metrics.duration.WithLabelValues(t.cluster, verb, resource).Observe(duration.Seconds())
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		t.metrics.errors.WithLabelValues(t.cluster, verb, resource).Inc()
+	}
+
+	return resp, err
+}
+
+// requestVerbResource derives the Kubernetes verb and resource of an API request from its
+// method and path, e.g. GET /api/v1/namespaces/shop/pods is (list, pods) and
+// GET /apis/apps/v1/namespaces/shop/replicasets/api-1 is (get, replicasets.apps)
+func requestVerbResource(req *http.Request) (string, string) {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+
+	// Strip the API prefix: /api/v1 for the core group, /apis/<group>/<version> otherwise
+	group := ""
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		segments = segments[2:]
+	case len(segments) >= 3 && segments[0] == "apis":
+		group = segments[1]
+		segments = segments[3:]
+	default:
+		// Discovery and version endpoints
+		return strings.ToLower(req.Method), "discovery"
+	}
+	if len(segments) == 0 {
+		return strings.ToLower(req.Method), "discovery"
+	}
+
+	// Namespaced requests: namespaces/<namespace>/<resource>/...
+	if segments[0] == "namespaces" && len(segments) >= 3 {
+		segments = segments[2:]
+	}
+
+	resource := segments[0]
+	if group != "" {
+		resource += "." + group
+	}
+	hasName := len(segments) >= 2
+	if len(segments) >= 3 {
+		resource += "/" + segments[2]
+	}
+
+	var verb string
+	switch req.Method {
+	case http.MethodGet:
+		switch {
+		case req.URL.Query().Get("watch") == "true" || req.URL.Query().Get("watch") == "1":
+			verb = "watch"
+		case hasName:
+			verb = "get"
+		default:
+			verb = "list"
+		}
+	case http.MethodPost:
+		verb = "create"
+	case http.MethodPut:
+		verb = "update"
+	case http.MethodPatch:
+		verb = "patch"
+	case http.MethodDelete:
+		if hasName {
+			verb = "delete"
+		} else {
+			verb = "deletecollection"
+		}
+	default:
+		verb = strings.ToLower(req.Method)
+	}
+
+	return verb, resource
+}
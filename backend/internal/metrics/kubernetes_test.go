@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRequestVerbResource(t *testing.T) {
+	tests := []struct {
+		method   string
+		path     string
+		verb     string
+		resource string
+	}{
+		{method: http.MethodGet, path: "/api/v1/pods", verb: "list", resource: "pods"},
+		{method: http.MethodGet, path: "/api/v1/namespaces/shop/pods", verb: "list", resource: "pods"},
+		{method: http.MethodGet, path: "/api/v1/namespaces/shop/pods?watch=true&resourceVersion=1", verb: "watch", resource: "pods"},
+		{method: http.MethodGet, path: "/api/v1/namespaces/shop/pods/api-1", verb: "get", resource: "pods"},
+		{method: http.MethodGet, path: "/api/v1/namespaces/shop/pods/api-1/log?follow=true", verb: "get", resource: "pods/log"},
+		{method: http.MethodGet, path: "/api/v1/namespaces/shop", verb: "get", resource: "namespaces"},
+		{method: http.MethodGet, path: "/api/v1/namespaces", verb: "list", resource: "namespaces"},
+		{method: http.MethodGet, path: "/apis/apps/v1/namespaces/shop/replicasets/api-1", verb: "get", resource: "replicasets.apps"},
+		{method: http.MethodGet, path: "/apis/metrics.k8s.io/v1beta1/pods", verb: "list", resource: "pods.metrics.k8s.io"},
+		{method: http.MethodDelete, path: "/api/v1/namespaces/shop/pods", verb: "deletecollection", resource: "pods"},
+		{method: http.MethodGet, path: "/version", verb: "get", resource: "discovery"},
+		{method: http.MethodGet, path: "/apis/argoproj.io/v1alpha1", verb: "get", resource: "discovery"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			verb, resource := requestVerbResource(httptest.NewRequest(tt.method, tt.path, nil))
+			if verb != tt.verb || resource != tt.resource {
+				t.Errorf("got (%s, %s), want (%s, %s)", verb, resource, tt.verb, tt.resource)
+			}
+		})
+	}
+}
+
+func TestInstrumentedTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/namespaces/shop/pods/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	m := NewKubernetesClientMetrics()
+	client := &http.Client{Transport: m.WrapTransport("prod", http.DefaultTransport)}
+
+	for _, path := range []string{"/api/v1/namespaces/shop/pods", "/api/v1/namespaces/shop/pods/missing"} {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		resp.Body.Close()
+	}
+
+	if got := testutil.ToFloat64(m.requests.WithLabelValues("prod", "list", "pods", "200")); got != 1 {
+		t.Errorf("list requests = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.errors.WithLabelValues("prod", "get", "pods")); got != 1 {
+		t.Errorf("get errors = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.errors.WithLabelValues("prod", "list", "pods")); got != 0 {
+		t.Errorf("list errors = %v, want 0", got)
+	}
+}
@@ -0,0 +1,208 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+	"k8s-monitor/pkg/utils"
+)
+
+// identityKey is the gin context key of the authenticated Identity
+const identityKey = "identity"
+
+// signatureAlgorithms are the token signature algorithms accepted. Symmetric algorithms
+// are left out, as a JWKS only publishes public keys.
+var signatureAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+// Identity is the user a request was authenticated as
+type Identity struct {
+	Subject string                 `json:"subject"`
+	User    string                 `json:"user"`
+	Groups  []string               `json:"groups,omitempty"`
+	Claims  map[string]interface{} `json:"-"` // all claims of the token
+}
+
+// IdentityFrom returns the identity of an authenticated request
+func IdentityFrom(c *gin.Context) (*Identity, bool) {
+	value, ok := c.Get(identityKey)
+	if !ok {
+		return nil, false
+	}
+	identity, ok := value.(*Identity)
+	return identity, ok
+}
+
+// authenticator verifies bearer tokens against the keys of an OIDC issuer
+type authenticator struct {
+	config config.AuthConfig
+	keys   *keySet
+	now    func() time.Time
+	logger *logrus.Entry
+}
+
+// newAuthenticator validates the authentication configuration and loads its keys. An
+// audience is required, as an issuer's keys also sign tokens meant for its other clients.
+func newAuthenticator(cfg config.AuthConfig, logger *logrus.Logger) (*authenticator, error) {
+	if cfg.Audience == "" {
+		return nil, errors.New("authentication requires an audience")
+	}
+
+	var keys *keySet
+	switch {
+	case cfg.JWKSFile != "":
+		var err error
+		if keys, err = newFileKeySet(cfg.JWKSFile); err != nil {
+			return nil, err
+		}
+	case cfg.JWKSURL != "" || cfg.Issuer != "":
+		refresh := time.Duration(cfg.RefreshInterval) * time.Second
+		if refresh < minKeyRefresh {
+			refresh = minKeyRefresh
+		}
+		keys = newRemoteKeySet(cfg.JWKSURL, cfg.Issuer, refresh)
+	default:
+		return nil, errors.New("authentication requires an issuer, a JWKS URL or a JWKS file")
+	}
+
+	return &authenticator{
+		config: cfg,
+		keys:   keys,
+		now:    time.Now,
+		logger: utils.WithComponent(logger, "auth"),
+	}, nil
+}
+
+// Auth returns a gin.HandlerFunc that rejects requests without a valid bearer token. The
+// token must be a JWT signed with a key of the configured JWKS, unexpired, issued for the
+// configured audience, and by the configured issuer when set. The identity of the
+// token's user is attached to the context, see IdentityFrom.
+//
+// Tokens are only read from the Authorization header, never from the URL, where they
+// would end up in access logs and browser history. Browsers' EventSource cannot set
+// headers, so clients of the event stream must read it with fetch instead.
+func Auth(cfg config.AuthConfig, logger *logrus.Logger) (gin.HandlerFunc, error) {
+	a, err := newAuthenticator(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	return a.handle, nil
+}
+
+// handle authenticates a request
+func (a *authenticator) handle(c *gin.Context) {
+	token, ok := bearerToken(c.GetHeader("Authorization"))
+	if !ok {
+		c.Header("WWW-Authenticate", `Bearer realm="k8s-monitor"`)
+		models.RespondError(c, http.StatusUnauthorized, models.ErrCodeUnauthorized,
+			"Authentication required", "Send a bearer token in the Authorization header")
+		c.Abort()
+		return
+	}
+
+	identity, err := a.verify(token)
+	if err != nil {
+		a.logger.WithError(err).WithField("client_ip", c.ClientIP()).Warn("Rejected bearer token")
+		c.Header("WWW-Authenticate", `Bearer realm="k8s-monitor", error="invalid_token"`)
+		models.RespondError(c, http.StatusUnauthorized, models.ErrCodeUnauthorized, "Invalid token", "")
+		c.Abort()
+		return
+	}
+
+	c.Set(identityKey, identity)
+	c.Next()
+}
+
+// verify checks a token's signature and claims and returns the identity it asserts
+func (a *authenticator) verify(raw string) (*Identity, error) {
+	token, err := jwt.ParseSigned(raw, signatureAlgorithms)
+	if err != nil {
+		return nil, fmt.Errorf("malformed token: %w", err)
+	}
+
+	keys, err := a.keys.lookup(token.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims jwt.Claims
+	var all map[string]interface{}
+	verified := false
+	for _, key := range keys {
+		if key.Algorithm != "" && key.Algorithm != token.Headers[0].Algorithm {
+			err = fmt.Errorf("key %s is for %s", key.KeyID, key.Algorithm)
+			continue
+		}
+		if err = token.Claims(key.Public().Key, &claims, &all); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+
+	if claims.Expiry == nil {
+		return nil, errors.New("token has no expiry")
+	}
+	expected := jwt.Expected{
+		Issuer:      a.config.Issuer,
+		AnyAudience: jwt.Audience{a.config.Audience},
+		Time:        a.now(),
+	}
+	if err := claims.ValidateWithLeeway(expected, time.Duration(a.config.Leeway)*time.Second); err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{
+		Subject: claims.Subject,
+		User:    claims.Subject,
+		Groups:  stringsClaim(all[a.config.GroupsClaim]),
+		Claims:  all,
+	}
+	if user, ok := all[a.config.UserClaim].(string); ok && user != "" {
+		identity.User = user
+	}
+	return identity, nil
+}
+
+// bearerToken returns the token of a bearer Authorization header
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// stringsClaim reads a claim holding a list of strings, or a single string
+func stringsClaim(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+)
+
+// testKey is a signing key and its public JWK
+type testKey struct {
+	private *rsa.PrivateKey
+	public  jose.JSONWebKey
+}
+
+func newTestKey(t *testing.T, kid string) testKey {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	return testKey{
+		private: private,
+		public:  jose.JSONWebKey{Key: &private.PublicKey, KeyID: kid, Algorithm: string(jose.RS256), Use: "sig"},
+	}
+}
+
+// sign issues a token with the given claims
+func (k testKey) sign(t *testing.T, claims map[string]interface{}) string {
+	t.Helper()
+	options := (&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", k.public.KeyID)
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: k.private}, options)
+	if err != nil {
+		t.Fatalf("NewSigner() error = %v", err)
+	}
+	token, err := jwt.Signed(signer).Claims(claims).Serialize()
+	if err != nil {
+		t.Fatalf("Serialize() error = %v", err)
+	}
+	return token
+}
+
+func newTestAuthRouter(t *testing.T, a *authenticator) http.Handler {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/pods", a.handle, func(c *gin.Context) {
+		identity, _ := IdentityFrom(c)
+		models.RespondSuccess(c, identity)
+	})
+	return router
+}
+
+func newTestAuthenticator(t *testing.T, cfg config.AuthConfig) *authenticator {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	cfg.UserClaim = "email"
+	cfg.GroupsClaim = "groups"
+	a, err := newAuthenticator(cfg, logger)
+	if err != nil {
+		t.Fatalf("newAuthenticator() error = %v", err)
+	}
+	return a
+}
+
+func TestAuth(t *testing.T) {
+	key := newTestKey(t, "key-1")
+	other := newTestKey(t, "key-2")
+
+	jwks, _ := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{key.public}})
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	a := newTestAuthenticator(t, config.AuthConfig{
+		Issuer:   "https://issuer.example.com",
+		Audience: "k8s-monitor",
+		JWKSFile: path,
+		Leeway:   60,
+	})
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return now }
+	router := newTestAuthRouter(t, a)
+
+	claims := func(overrides map[string]interface{}) map[string]interface{} {
+		claims := map[string]interface{}{
+			"iss":    "https://issuer.example.com",
+			"aud":    "k8s-monitor",
+			"sub":    "user-1",
+			"email":  "jane@example.com",
+			"groups": []string{"dev", "ops"},
+			"exp":    now.Add(time.Hour).Unix(),
+		}
+		for name, value := range overrides {
+			if value == nil {
+				delete(claims, name)
+			} else {
+				claims[name] = value
+			}
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{name: "valid", header: "Bearer " + key.sign(t, claims(nil)), status: http.StatusOK},
+		{name: "missing token", status: http.StatusUnauthorized},
+		{name: "basic auth", header: "Basic dXNlcjpwYXNz", status: http.StatusUnauthorized},
+		{name: "malformed", header: "Bearer not-a-jwt", status: http.StatusUnauthorized},
+		{name: "unknown key", header: "Bearer " + other.sign(t, claims(nil)), status: http.StatusUnauthorized},
+		{name: "expired", header: "Bearer " + key.sign(t, claims(map[string]interface{}{"exp": now.Add(-2 * time.Minute).Unix()})), status: http.StatusUnauthorized},
+		{name: "within leeway", header: "Bearer " + key.sign(t, claims(map[string]interface{}{"exp": now.Add(-30 * time.Second).Unix()})), status: http.StatusOK},
+		{name: "no expiry", header: "Bearer " + key.sign(t, claims(map[string]interface{}{"exp": nil})), status: http.StatusUnauthorized},
+		{name: "other issuer", header: "Bearer " + key.sign(t, claims(map[string]interface{}{"iss": "https://evil.example.com"})), status: http.StatusUnauthorized},
+		{name: "other audience", header: "Bearer " + key.sign(t, claims(map[string]interface{}{"aud": "grafana"})), status: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/api/v1/pods", nil)
+			if tt.header != "" {
+				request.Header.Set("Authorization", tt.header)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.status, recorder.Body.String())
+			}
+			if tt.status == http.StatusUnauthorized && recorder.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 without WWW-Authenticate")
+			}
+		})
+	}
+
+	// The identity of the token is available to handlers
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/pods", nil)
+	request.Header.Set("Authorization", "Bearer "+key.sign(t, claims(nil)))
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	var identity Identity
+	if err := json.Unmarshal(recorder.Body.Bytes(), &models.APIResponse{Data: &identity}); err != nil {
+		t.Fatal(err)
+	}
+	if identity.Subject != "user-1" || identity.User != "jane@example.com" || len(identity.Groups) != 2 || identity.Groups[1] != "ops" {
+		t.Errorf("identity = %+v", identity)
+	}
+}
+
+func TestAuthRemoteKeys(t *testing.T) {
+	key := newTestKey(t, "key-1")
+	rotated := newTestKey(t, "key-2")
+
+	var published atomic.Value
+	published.Store([]jose.JSONWebKey{key.public})
+	var fetches atomic.Int32
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"issuer": server.URL, "jwks_uri": server.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: published.Load().([]jose.JSONWebKey)})
+	})
+
+	// The JWKS URL is discovered from the issuer
+	a := newTestAuthenticator(t, config.AuthConfig{Issuer: server.URL, Audience: "k8s-monitor", RefreshInterval: 3600})
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return now }
+	a.keys.now = a.now
+	router := newTestAuthRouter(t, a)
+
+	status := func(k testKey) int {
+		token := k.sign(t, map[string]interface{}{"iss": server.URL, "aud": "k8s-monitor", "sub": "user-1", "exp": now.Add(time.Hour).Unix()})
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/pods", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	if got := status(key); got != http.StatusOK || fetches.Load() != 1 {
+		t.Fatalf("status = %d after %d fetches, want 200 after one", got, fetches.Load())
+	}
+	if status(key); fetches.Load() != 1 {
+		t.Errorf("known key fetched the keys again, %d fetches", fetches.Load())
+	}
+
+	// The issuer rolls its key; unknown keys fetch the set at most once a minute
+	published.Store([]jose.JSONWebKey{rotated.public})
+	now = now.Add(2 * time.Minute)
+	if got := status(rotated); got != http.StatusOK || fetches.Load() != 2 {
+		t.Errorf("status = %d after %d fetches, want 200 after two", got, fetches.Load())
+	}
+	if got := status(newTestKey(t, "key-3")); got != http.StatusUnauthorized || fetches.Load() != 2 {
+		t.Errorf("status = %d after %d fetches, want 401 without fetching", got, fetches.Load())
+	}
+	if got := status(key); got != http.StatusUnauthorized {
+		t.Errorf("status = %d with the retired key, want 401", got)
+	}
+}
+
+func TestNewAuthenticatorValidatesConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.AuthConfig
+	}{
+		{name: "no keys", cfg: config.AuthConfig{Enabled: true, Audience: "k8s-monitor"}},
+		{name: "missing JWKS file", cfg: config.AuthConfig{Audience: "k8s-monitor", JWKSFile: filepath.Join(t.TempDir(), "missing.json")}},
+		{name: "no audience", cfg: config.AuthConfig{Enabled: true, Issuer: "https://issuer.example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newAuthenticator(tt.cfg, logrus.New()); err == nil {
+				t.Error("newAuthenticator() accepted the configuration")
+			}
+		})
+	}
+}
+
+func TestAuthKeyRefreshDoesNotBlock(t *testing.T) {
+	key := newTestKey(t, "key-1")
+
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every fetch after the first hangs until released
+		if fetches.Add(1) > 1 {
+			<-release
+		}
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{key.public}})
+	}))
+	defer server.Close()
+	defer close(release)
+
+	a := newTestAuthenticator(t, config.AuthConfig{JWKSURL: server.URL, Audience: "k8s-monitor", RefreshInterval: 60})
+	var now atomic.Int64
+	now.Store(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC).Unix())
+	a.now = func() time.Time { return time.Unix(now.Load(), 0) }
+	a.keys.now = a.now
+	router := newTestAuthRouter(t, a)
+
+	token := key.sign(t, map[string]interface{}{"aud": "k8s-monitor", "sub": "user-1", "exp": a.now().Add(24 * time.Hour).Unix()})
+	status := func() int {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/pods", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+	if got := status(); got != http.StatusOK {
+		t.Fatalf("status = %d, want 200", got)
+	}
+
+	// Once the keys expire, requests keep being served while the issuer is slow to answer
+	now.Add(int64((2 * time.Minute).Seconds()))
+	done := make(chan int, 10)
+	for i := 0; i < cap(done); i++ {
+		go func() { done <- status() }()
+	}
+	for i := 0; i < cap(done); i++ {
+		select {
+		case got := <-done:
+			if got != http.StatusOK {
+				t.Errorf("status = %d during a refresh, want 200", got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("requests waited for the key refresh")
+		}
+	}
+	// The refresh runs in the background
+	deadline := time.Now().Add(5 * time.Second)
+	for fetches.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := fetches.Load(); got != 2 {
+		t.Errorf("keys fetched %d times, want a single refresh", got)
+	}
+}
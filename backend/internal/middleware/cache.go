@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+
+	"k8s-monitor/pkg/utils"
+)
+
+// representationHeaders are the response headers describing the response itself, kept with
+// it. Other headers, such as those set by CORS, depend on the request and are never replayed.
+var representationHeaders = []string{"Content-Type", "Retry-After"}
+
+// cachedResponse is a response captured from a handler
+type cachedResponse struct {
+	status  int
+	header  http.Header
+	body    []byte
+	etag    string
+	expires time.Time
+}
+
+// responseCache caches successful responses for a short time, keyed by path and query
+type responseCache struct {
+	ttl     time.Duration
+	now     func() time.Time
+	group   singleflight.Group
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+	logger  *logrus.Entry
+}
+
+// newResponseCache creates a response cache keeping responses for ttl
+func newResponseCache(ttl time.Duration, logger *logrus.Logger) *responseCache {
+	return &responseCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*cachedResponse),
+		logger:  utils.WithComponent(logger, "response-cache"),
+	}
+}
+
+// ResponseCache returns a gin.HandlerFunc caching successful GET responses for ttl, keyed
+// by path and query. Identical requests arriving while a response is being built wait for
+// it instead of building their own. Responses carry Cache-Control and ETag headers, and
+// requests whose If-None-Match matches get a 304. Routes sharing the handler share the cache.
+func ResponseCache(ttl time.Duration, logger *logrus.Logger) gin.HandlerFunc {
+	return newResponseCache(ttl, logger).handle
+}
+
+// handle serves a request from the cache, or builds and caches its response
+func (rc *responseCache) handle(c *gin.Context) {
+	if c.Request.Method != http.MethodGet {
+		c.Next()
+		return
+	}
+
+	key := cacheKey(c)
+	response, hit := rc.get(key)
+	if !hit {
+		// Only the first of identical concurrent requests runs the handlers
+		value, _, _ := rc.group.Do(key, func() (interface{}, error) {
+			return rc.capture(c, key), nil
+		})
+		response = value.(*cachedResponse)
+	}
+
+	c.Abort()
+	rc.write(c, response)
+}
+
+// get returns the unexpired cached response of a key
+func (rc *responseCache) get(key string) (*cachedResponse, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	response, ok := rc.entries[key]
+	if !ok || !rc.now().Before(response.expires) {
+		return nil, false
+	}
+	return response, true
+}
+
+// capture runs the remaining handlers with a buffered writer and caches successful responses
+func (rc *responseCache) capture(c *gin.Context, key string) *cachedResponse {
+	// Waiting requests depend on the response, so it is built even if this client leaves
+	c.Request = c.Request.WithContext(context.WithoutCancel(c.Request.Context()))
+
+	writer := &bufferedWriter{ResponseWriter: c.Writer}
+	c.Writer = writer
+	defer func() { c.Writer = writer.ResponseWriter }()
+	c.Next()
+
+	response := &cachedResponse{
+		status: writer.Status(),
+		header: make(http.Header),
+		body:   writer.body.Bytes(),
+	}
+	for _, name := range representationHeaders {
+		if values := writer.Header().Values(name); len(values) > 0 {
+			response.header[name] = values
+		}
+	}
+	if response.status != http.StatusOK {
+		return response
+	}
+
+	response.etag = contentTag(response.body)
+	response.expires = rc.now().Add(rc.ttl)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	// Expired entries are dropped as new ones come in
+	now := rc.now()
+	for k, entry := range rc.entries {
+		if !now.Before(entry.expires) {
+			delete(rc.entries, k)
+		}
+	}
+	rc.entries[key] = response
+
+	rc.logger.WithField("key", key).Debug("Cached response")
+	return response
+}
+
+// write sends a captured response, or 304 when the client already has it
+func (rc *responseCache) write(c *gin.Context, response *cachedResponse) {
+	header := c.Writer.Header()
+	for name, values := range response.header {
+		header[name] = values
+	}
+
+	if response.etag != "" {
+		header.Set("ETag", response.etag)
+		header.Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(rc.ttl.Seconds())))
+		if etagMatches(c.GetHeader("If-None-Match"), response.etag) {
+			c.Status(http.StatusNotModified)
+			c.Writer.WriteHeaderNow()
+			return
+		}
+	}
+
+	c.Status(response.status)
+	c.Writer.Write(response.body)
+}
+
+// bufferedWriter holds back a response until it can be tagged
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write buffers the response body
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+// WriteString buffers the response body
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// WriteHeaderNow defers writing the header along with the body
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+// cacheKey identifies a request by its path and its query parameters in a canonical order
+func cacheKey(c *gin.Context) string {
+	return c.Request.URL.Path + "?" + c.Request.URL.Query().Encode()
+}
+
+// contentTag returns a strong ETag of a JSON response. The response timestamp is left
+// out, so a response rebuilt with the same content keeps its tag.
+func contentTag(body []byte) string {
+	hash := sha256.New()
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		hash.Write(body)
+	} else {
+		delete(fields, "timestamp")
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			hash.Write([]byte(name))
+			hash.Write(fields[name])
+		}
+	}
+
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header lists the tag
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
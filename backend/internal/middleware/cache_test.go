@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/models"
+)
+
+func newTestCache(ttl time.Duration) *responseCache {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return newResponseCache(ttl, logger)
+}
+
+func get(router http.Handler, path string, header http.Header) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, path, nil)
+	for name, values := range header {
+		request.Header[name] = values
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestResponseCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cache := newTestCache(5 * time.Second)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	var calls atomic.Int32
+	router := gin.New()
+	router.GET("/items", cache.handle, func(c *gin.Context) {
+		calls.Add(1)
+		models.RespondSuccess(c, []string{"a", c.Query("sort")})
+	})
+	router.GET("/broken", cache.handle, func(c *gin.Context) {
+		calls.Add(1)
+		models.RespondInternalError(c, "Failed", "")
+	})
+
+	first := get(router, "/items?sort=name&page=1", nil)
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" || first.Header().Get("Cache-Control") != "private, max-age=5" {
+		t.Fatalf("first response %d with headers %v", first.Code, first.Header())
+	}
+
+	// The same query in another order is served from the cache
+	second := get(router, "/items?page=1&sort=name", nil)
+	if second.Body.String() != first.Body.String() || calls.Load() != 1 {
+		t.Errorf("second request ran the handler, %d calls", calls.Load())
+	}
+	if get(router, "/items?sort=age", nil); calls.Load() != 2 {
+		t.Errorf("another query was served from the cache")
+	}
+
+	notModified := get(router, "/items?sort=name&page=1", http.Header{"If-None-Match": {`"other", ` + etag}})
+	if notModified.Code != http.StatusNotModified || notModified.Body.Len() != 0 || notModified.Header().Get("ETag") != etag {
+		t.Errorf("If-None-Match got %d with body %q", notModified.Code, notModified.Body.String())
+	}
+
+	// Rebuilt responses keep their tag when only the timestamp changed
+	now = now.Add(6 * time.Second)
+	time.Sleep(time.Millisecond)
+	rebuilt := get(router, "/items?sort=name&page=1", nil)
+	if calls.Load() != 3 {
+		t.Errorf("expired response was served, %d calls", calls.Load())
+	}
+	if rebuilt.Header().Get("ETag") != etag {
+		t.Errorf("ETag = %s after rebuilding, want %s", rebuilt.Header().Get("ETag"), etag)
+	}
+
+	// Errors are not cached
+	get(router, "/broken", nil)
+	if broken := get(router, "/broken", nil); broken.Code != http.StatusInternalServerError || broken.Header().Get("ETag") != "" || calls.Load() != 5 {
+		t.Errorf("error response %d was cached, %d calls", broken.Code, calls.Load())
+	}
+}
+
+func TestResponseCacheCoalescesRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cache := newTestCache(time.Minute)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls atomic.Int32
+	router := gin.New()
+	router.GET("/applications", cache.handle, func(c *gin.Context) {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		models.RespondSuccess(c, "applications")
+	})
+
+	var wg sync.WaitGroup
+	responses := make([]*httptest.ResponseRecorder, 10)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		responses[0] = get(router, "/applications", nil)
+	}()
+	<-started
+
+	for i := 1; i < len(responses); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i] = get(router, "/applications", nil)
+		}(i)
+	}
+	// Let the waiting requests join the one in flight
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("handler ran %d times, want once", calls.Load())
+	}
+	for i, response := range responses {
+		if response.Code != http.StatusOK || response.Body.String() != responses[0].Body.String() {
+			t.Errorf("responses[%d] = %d %q", i, response.Code, response.Body.String())
+		}
+	}
+}
+
+func TestResponseCacheKeepsRequestHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cache := newTestCache(time.Minute)
+
+	router := gin.New()
+	router.GET("/nodes", func(c *gin.Context) {
+		// Stands in for CORS, which answers each request for its own origin
+		if origin := c.GetHeader("Origin"); origin != "" {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		c.Next()
+	}, cache.handle, func(c *gin.Context) {
+		models.RespondSuccess(c, "nodes")
+	})
+
+	first := get(router, "/nodes", http.Header{"Origin": {"https://a.example.com"}})
+	if got := first.Header().Get("Access-Control-Allow-Origin"); got != "https://a.example.com" {
+		t.Fatalf("first Access-Control-Allow-Origin = %q", got)
+	}
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   string
+	}{
+		{name: "other origin", header: http.Header{"Origin": {"https://b.example.com"}}, want: "https://b.example.com"},
+		{name: "no origin", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := get(router, "/nodes", tt.header)
+			if got := response.Header().Get("Access-Control-Allow-Origin"); got != tt.want {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.want)
+			}
+			if got := response.Header().Get("Content-Type"); got != first.Header().Get("Content-Type") {
+				t.Errorf("Content-Type = %q, want %q", got, first.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"golang.org/x/sync/singleflight"
+)
+
+// minKeyRefresh bounds how often tokens signed with unknown keys make the key set be fetched
+const minKeyRefresh = time.Minute
+
+// errUnknownKey is returned when no key of the set matches the key ID of a token
+var errUnknownKey = errors.New("no key matches the token")
+
+// keySet holds the keys tokens are verified with. Keys read from a URL are fetched on first
+// use, again once refresh has passed, and early when a token names a key the set lacks,
+// which is how issuers roll their keys.
+type keySet struct {
+	url     string // JWKS URL, empty for keys read from a file
+	issuer  string // discovers the JWKS URL when no URL or file is set
+	refresh time.Duration
+	client  *http.Client
+	now     func() time.Time
+	group   singleflight.Group
+
+	mu        sync.Mutex
+	keys      jose.JSONWebKeySet
+	fetched   time.Time // last successful fetch
+	attempted time.Time // last fetch
+	fetchErr  error     // error of the last fetch
+}
+
+// newFileKeySet reads a key set from a JWKS file
+func newFileKeySet(path string) (*keySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+	var keys jose.JSONWebKeySet
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS file %s: %w", path, err)
+	}
+	if len(keys.Keys) == 0 {
+		return nil, fmt.Errorf("JWKS file %s has no keys", path)
+	}
+	return &keySet{keys: keys, now: time.Now}, nil
+}
+
+// newRemoteKeySet creates a key set fetched from a JWKS URL, or from the JWKS URL of the
+// issuer's discovery document when url is empty
+func newRemoteKeySet(url, issuer string, refresh time.Duration) *keySet {
+	return &keySet{
+		url:     url,
+		issuer:  issuer,
+		refresh: refresh,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		now:     time.Now,
+	}
+}
+
+// lookup returns the keys a token with the given key ID may be signed with. Tokens without
+// a key ID may be signed with any key of the set.
+func (s *keySet) lookup(kid string) ([]jose.JSONWebKey, error) {
+	s.mu.Lock()
+	keys, err := s.match(kid)
+	if s.client == nil || !s.due(err) {
+		fetchErr := s.fetchErr
+		s.mu.Unlock()
+		if err != nil && fetchErr != nil {
+			return nil, fetchErr
+		}
+		return keys, err
+	}
+	s.mu.Unlock()
+
+	refresh := func() (interface{}, error) { return nil, s.refreshKeys() }
+	if err == nil {
+		// Known keys are served while the set is refreshed, and while the issuer is unreachable
+		go s.group.Do("keys", refresh)
+		return keys, nil
+	}
+
+	// Requests for unknown keys wait for a fetch in flight rather than starting their own
+	if _, fetchErr, _ := s.group.Do("keys", refresh); fetchErr != nil {
+		return nil, fetchErr
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.match(kid)
+}
+
+// due reports whether the keys should be fetched: once they expired, or early for unknown
+// keys, at most once a minute. The caller must hold s.mu.
+func (s *keySet) due(matchErr error) bool {
+	now := s.now()
+	if now.Sub(s.attempted) < minKeyRefresh {
+		return false
+	}
+	return matchErr != nil || s.fetched.IsZero() || now.Sub(s.fetched) >= s.refresh
+}
+
+// refreshKeys fetches the keys and swaps them in. The fetch is not bound to the request
+// that started it, as every request waiting for the keys depends on it.
+func (s *keySet) refreshKeys() error {
+	s.mu.Lock()
+	if now := s.now(); now.Sub(s.attempted) < minKeyRefresh {
+		// Another fetch just finished
+		defer s.mu.Unlock()
+		return s.fetchErr
+	}
+	s.attempted = s.now()
+	url := s.url
+	s.mu.Unlock()
+
+	url, keys, err := s.fetch(context.Background(), url)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetchErr = err
+	if err != nil {
+		return err
+	}
+	s.url = url
+	s.keys = keys
+	s.fetched = s.now()
+	return nil
+}
+
+// match returns the signing keys of the set with the given key ID. The caller must hold s.mu.
+func (s *keySet) match(kid string) ([]jose.JSONWebKey, error) {
+	candidates := s.keys.Keys
+	if kid != "" {
+		candidates = s.keys.Key(kid)
+	}
+	var keys []jose.JSONWebKey
+	for _, key := range candidates {
+		if key.Use == "" || key.Use == "sig" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errUnknownKey
+	}
+	return keys, nil
+}
+
+// fetch reads the keys served at the JWKS URL, discovering the URL first when unknown
+func (s *keySet) fetch(ctx context.Context, url string) (string, jose.JSONWebKeySet, error) {
+	var keys jose.JSONWebKeySet
+	if url == "" {
+		discovered, err := s.discover(ctx)
+		if err != nil {
+			return "", keys, err
+		}
+		url = discovered
+	}
+
+	if err := s.getJSON(ctx, url, &keys); err != nil {
+		return "", keys, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	return url, keys, nil
+}
+
+// discover reads the JWKS URL from the issuer's OIDC discovery document
+func (s *keySet) discover(ctx context.Context) (string, error) {
+	var document struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	url := strings.TrimSuffix(s.issuer, "/") + "/.well-known/openid-configuration"
+	if err := s.getJSON(ctx, url, &document); err != nil {
+		return "", fmt.Errorf("failed to discover JWKS URL: %w", err)
+	}
+	if document.JWKSURI == "" {
+		return "", fmt.Errorf("discovery document %s has no jwks_uri", url)
+	}
+	return document.JWKSURI, nil
+}
+
+// getJSON decodes the JSON document served at a URL
+func (s *keySet) getJSON(ctx context.Context, url string, out interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", url, response.Status)
+	}
+	return json.NewDecoder(response.Body).Decode(out)
+}
@@ -42,6 +42,11 @@ func Logger(logger *logrus.Logger) gin.HandlerFunc {
 			"component":  "http-server",
 		})
 
+		// Add the authenticated user if any
+		if identity, ok := IdentityFrom(c); ok {
+			entry = entry.WithField("user", identity.User)
+		}
+
 		// Add error information if present
 		if len(c.Errors) > 0 {
 			entry = entry.WithField("errors", c.Errors.String())
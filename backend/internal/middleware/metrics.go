@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"k8s-monitor/internal/metrics"
+)
+
+// Metrics returns a gin.HandlerFunc that records request counts and durations per route
+func Metrics(httpMetrics *metrics.HTTPMetrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		// Process request
+		c.Next()
+
+		httpMetrics.Observe(c.Request.Method, c.FullPath(), c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"k8s-monitor/internal/models"
+)
+
+// Staleness returns a gin.HandlerFunc letting handlers answer from the last known data of
+// unreachable clusters. Responses built from it are flagged stale with the age of the data.
+func Staleness() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, _ := models.WithStaleness(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
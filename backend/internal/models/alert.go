@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// AlertStatus describes whether an alert is active
+type AlertStatus string
+
+const (
+	AlertFiring   AlertStatus = "firing"
+	AlertResolved AlertStatus = "resolved"
+)
+
+// Alert kinds
+const (
+	AlertKindApplication = "application"
+	AlertKindArgoCD      = "argocd"
+	AlertKindCluster     = "cluster"
+)
+
+// Alert represents a rule that fired for one application, or for one cluster
+type Alert struct {
+	Fingerprint string      `json:"fingerprint"`
+	Rule        string      `json:"rule"`
+	Severity    string      `json:"severity"`
+	Status      AlertStatus `json:"status"`
+	Kind        string      `json:"kind"` // application, argocd or cluster
+	Cluster     string      `json:"cluster,omitempty"`
+	Namespace   string      `json:"namespace"`
+	Name        string      `json:"name"`
+	Message     string      `json:"message"`
+	StartsAt    time.Time   `json:"startsAt"`
+	EndsAt      *time.Time  `json:"endsAt,omitempty"`
+}
+
+// AlertListResponse represents the response for the alerts endpoint
+type AlertListResponse struct {
+	Enabled bool    `json:"enabled"`
+	Alerts  []Alert `json:"alerts"`
+	Total   int     `json:"total"`
+}
@@ -6,18 +6,25 @@ import (
 
 // Application represents an application composed of multiple Kubernetes resources
 type Application struct {
-	Name        string             `json:"name"`
-	Namespace   string             `json:"namespace"`
-	Status      string             `json:"status"` // healthy, degraded, unhealthy, unknown
-	Type        string             `json:"type"`   // deployment, statefulset, daemonset, standalone
-	Version     string             `json:"version,omitempty"`
-	Labels      map[string]string  `json:"labels,omitempty"`
-	Annotations map[string]string  `json:"annotations,omitempty"`
-	Pods        []PodStatus        `json:"pods"`
-	Services    []ServiceInfo      `json:"services,omitempty"`
-	Summary     ApplicationSummary `json:"summary"`
-	CreatedAt   time.Time          `json:"createdAt"`
-	UpdatedAt   time.Time          `json:"updatedAt"`
+	Name          string             `json:"name"`
+	Namespace     string             `json:"namespace"`
+	Cluster       string             `json:"cluster,omitempty"`
+	Status        string             `json:"status"` // healthy, degraded, unhealthy, unknown
+	Type          string             `json:"type"`   // deployment, statefulset, daemonset, standalone, system
+	Version       string             `json:"version,omitempty"`
+	System        string             `json:"system,omitempty"`            // the part-of label
+	ArgoCD        string             `json:"argocdApplication,omitempty"` // the managing ArgoCD application
+	Labels        map[string]string  `json:"labels,omitempty"`
+	Annotations   map[string]string  `json:"annotations,omitempty"`
+	Workloads     []WorkloadInfo     `json:"workloads,omitempty"`
+	RootOwnerKind string             `json:"rootOwnerKind,omitempty"`
+	RootOwnerName string             `json:"rootOwnerName,omitempty"`
+	Pods          []PodStatus        `json:"pods"`
+	Services      []ServiceInfo      `json:"services,omitempty"`
+	Summary       ApplicationSummary `json:"summary"`
+	Resources     ResourceUsage      `json:"resources"`
+	CreatedAt     time.Time          `json:"createdAt"`
+	UpdatedAt     time.Time          `json:"updatedAt"`
 }
 
 // ApplicationSummary provides aggregated statistics for an application
@@ -32,13 +39,26 @@ type ApplicationSummary struct {
 
 // ServiceInfo represents basic information about a Kubernetes service
 type ServiceInfo struct {
-	Name        string            `json:"name"`
-	Type        string            `json:"type"`
-	ClusterIP   string            `json:"clusterIP,omitempty"`
-	ExternalIP  []string          `json:"externalIP,omitempty"`
-	Ports       []ServicePort     `json:"ports,omitempty"`
-	Labels      map[string]string `json:"labels,omitempty"`
-	Annotations map[string]string `json:"annotations,omitempty"`
+	Name              string            `json:"name"`
+	Type              string            `json:"type"`
+	ClusterIP         string            `json:"clusterIP,omitempty"`
+	ExternalIP        []string          `json:"externalIP,omitempty"`
+	Ports             []ServicePort     `json:"ports,omitempty"`
+	Selector          map[string]string `json:"selector,omitempty"`
+	ReadyEndpoints    int               `json:"readyEndpoints"`
+	NotReadyEndpoints int               `json:"notReadyEndpoints"`
+	Endpoints         []ServiceEndpoint `json:"endpoints,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+}
+
+// ServiceEndpoint represents an endpoint backing a service, taken from its EndpointSlices
+type ServiceEndpoint struct {
+	Addresses   []string `json:"addresses"`
+	Ready       bool     `json:"ready"`
+	Terminating bool     `json:"terminating,omitempty"`
+	PodName     string   `json:"podName,omitempty"`
+	NodeName    string   `json:"nodeName,omitempty"`
 }
 
 // ServicePort represents a port exposed by a service
@@ -52,10 +72,13 @@ type ServicePort struct {
 
 // ApplicationsResponse represents the response for application list endpoints
 type ApplicationsResponse struct {
-	Applications []Application       `json:"applications"`
-	Total        int                 `json:"total"`
-	Namespace    string              `json:"namespace,omitempty"`
-	Summary      ApplicationsSummary `json:"summary"`
+	Applications     []Application       `json:"applications"`
+	Total            int                 `json:"total"`
+	Namespace        string              `json:"namespace,omitempty"`
+	Cluster          string              `json:"cluster,omitempty"`
+	Summary          ApplicationsSummary `json:"summary"`
+	MetricsAvailable bool                `json:"metricsAvailable"`
+	ClusterErrors    map[string]string   `json:"clusterErrors,omitempty"`
 }
 
 // ApplicationsSummary provides aggregated statistics across all applications
@@ -69,6 +92,24 @@ type ApplicationsSummary struct {
 	RunningPods int `json:"runningPods"`
 }
 
+// Count adds an application to the summary
+func (s *ApplicationsSummary) Count(app Application) {
+	switch app.Status {
+	case string(StatusHealthy):
+		s.Healthy++
+	case string(StatusDegraded):
+		s.Degraded++
+	case string(StatusUnhealthy):
+		s.Unhealthy++
+	default:
+		s.Unknown++
+	}
+
+	s.TotalPods += app.Summary.TotalPods
+	s.ReadyPods += app.Summary.ReadyPods
+	s.RunningPods += app.Summary.RunningPods
+}
+
 // ApplicationStatus represents possible application health states
 type ApplicationStatus string
 
@@ -79,6 +120,20 @@ const (
 	StatusUnknown   ApplicationStatus = "unknown"
 )
 
+// Rank orders statuses from best to worst
+func (s ApplicationStatus) Rank() int {
+	switch s {
+	case StatusHealthy:
+		return 0
+	case StatusDegraded:
+		return 2
+	case StatusUnhealthy:
+		return 3
+	default:
+		return 1
+	}
+}
+
 // ApplicationType represents the type of Kubernetes workload
 type ApplicationType string
 
@@ -89,6 +144,9 @@ const (
 	TypeStandalone  ApplicationType = "standalone"
 	TypeJob         ApplicationType = "job"
 	TypeCronJob     ApplicationType = "cronjob"
+	TypeRollout     ApplicationType = "rollout" // Argo Rollouts
+	TypeCustom      ApplicationType = "custom"  // owned by another custom resource
+	TypeSystem      ApplicationType = "system"  // workloads of different kinds grouped by the part-of label
 )
 
 // DetermineApplicationStatus calculates the overall health status of an application
@@ -133,7 +191,8 @@ func DetermineApplicationStatus(pods []PodStatus) ApplicationStatus {
 	return StatusUnknown
 }
 
-// DetermineApplicationType determines the application type based on owner references
+// DetermineApplicationType determines the application type from the root owner of its
+// pods, or their direct owner when the owner chain was not resolved
 func DetermineApplicationType(pods []PodStatus) ApplicationType {
 	if len(pods) == 0 {
 		return TypeStandalone
@@ -141,9 +200,16 @@ func DetermineApplicationType(pods []PodStatus) ApplicationType {
 
 	// Check the first pod's owner kind (assuming all pods have the same owner type)
 	firstPod := pods[0]
-	switch firstPod.OwnerKind {
-	case "ReplicaSet":
-		// ReplicaSet is typically owned by a Deployment
+	kind := firstPod.RootOwnerKind
+	if kind == "" {
+		kind = firstPod.OwnerKind
+	}
+
+	switch kind {
+	case "":
+		return TypeStandalone
+	case "Deployment", "ReplicaSet":
+		// An unresolved ReplicaSet is typically owned by a Deployment
 		return TypeDeployment
 	case "StatefulSet":
 		return TypeStatefulSet
@@ -153,8 +219,13 @@ func DetermineApplicationType(pods []PodStatus) ApplicationType {
 		return TypeJob
 	case "CronJob":
 		return TypeCronJob
-	default:
+	case "Rollout":
+		return TypeRollout
+	case "Node":
+		// Static pods are mirrored by the kubelet of their node
 		return TypeStandalone
+	default:
+		return TypeCustom
 	}
 }
 
@@ -0,0 +1,155 @@
+package models
+
+import "testing"
+
+func TestDetermineApplicationStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		pods []PodStatus
+		want ApplicationStatus
+	}{
+		{
+			name: "no pods",
+			pods: nil,
+			want: StatusUnknown,
+		},
+		{
+			name: "all running and ready",
+			pods: []PodStatus{
+				{Status: "Running", Ready: true},
+				{Status: "Running", Ready: true},
+			},
+			want: StatusHealthy,
+		},
+		{
+			name: "one failed pod",
+			pods: []PodStatus{
+				{Status: "Running", Ready: true},
+				{Status: "Failed"},
+			},
+			want: StatusUnhealthy,
+		},
+		{
+			name: "pending pod",
+			pods: []PodStatus{
+				{Status: "Running", Ready: true},
+				{Status: "Pending"},
+			},
+			want: StatusDegraded,
+		},
+		{
+			name: "running but not ready",
+			pods: []PodStatus{
+				{Status: "Running", Ready: true},
+				{Status: "Running", Ready: false},
+			},
+			want: StatusDegraded,
+		},
+		{
+			name: "only succeeded pods",
+			pods: []PodStatus{
+				{Status: "Succeeded"},
+			},
+			want: StatusUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetermineApplicationStatus(tt.pods); got != tt.want {
+				t.Errorf("DetermineApplicationStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetermineApplicationType(t *testing.T) {
+	tests := []struct {
+		ownerKind string
+		rootKind  string
+		want      ApplicationType
+	}{
+		{ownerKind: "ReplicaSet", want: TypeDeployment},
+		{ownerKind: "StatefulSet", want: TypeStatefulSet},
+		{ownerKind: "DaemonSet", want: TypeDaemonSet},
+		{ownerKind: "Job", want: TypeJob},
+		{ownerKind: "CronJob", want: TypeCronJob},
+		{ownerKind: "", want: TypeStandalone},
+		{ownerKind: "Job", rootKind: "CronJob", want: TypeCronJob},
+		{ownerKind: "ReplicaSet", rootKind: "Rollout", want: TypeRollout},
+		{ownerKind: "StatefulSet", rootKind: "RabbitmqCluster", want: TypeCustom},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.want), func(t *testing.T) {
+			pods := []PodStatus{{OwnerKind: tt.ownerKind, RootOwnerKind: tt.rootKind}}
+			if got := DetermineApplicationType(pods); got != tt.want {
+				t.Errorf("DetermineApplicationType(%q) = %q, want %q", tt.ownerKind, got, tt.want)
+			}
+		})
+	}
+
+	if got := DetermineApplicationType(nil); got != TypeStandalone {
+		t.Errorf("DetermineApplicationType(nil) = %q, want %q", got, TypeStandalone)
+	}
+}
+
+func TestCalculateApplicationSummary(t *testing.T) {
+	pods := []PodStatus{
+		{Status: "Running", Ready: true, Restarts: 1},
+		{Status: "Running", Ready: false, Restarts: 3},
+		{Status: "Pending"},
+		{Status: "Failed", Restarts: 2},
+	}
+
+	want := ApplicationSummary{
+		TotalPods:    4,
+		ReadyPods:    1,
+		RunningPods:  2,
+		PendingPods:  1,
+		FailedPods:   1,
+		RestartCount: 6,
+	}
+
+	if got := CalculateApplicationSummary(pods); got != want {
+		t.Errorf("CalculateApplicationSummary() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetApplicationVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		annotations map[string]string
+		want        string
+	}{
+		{
+			name:   "recommended label",
+			labels: map[string]string{"app.kubernetes.io/version": "1.2.3", "version": "old"},
+			want:   "1.2.3",
+		},
+		{
+			name:        "labels take precedence over annotations",
+			labels:      map[string]string{"version": "v2"},
+			annotations: map[string]string{"app.kubernetes.io/version": "v1"},
+			want:        "v2",
+		},
+		{
+			name:        "annotation fallback",
+			annotations: map[string]string{"helm.sh/chart": "api-0.1.0"},
+			want:        "api-0.1.0",
+		},
+		{
+			name: "no version",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetApplicationVersion(tt.labels, tt.annotations); got != tt.want {
+				t.Errorf("GetApplicationVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
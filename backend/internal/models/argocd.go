@@ -9,6 +9,7 @@ import (
 type ArgoCDApplication struct {
 	Name           string            `json:"name"`
 	Namespace      string            `json:"namespace"`
+	Cluster        string            `json:"cluster,omitempty"`
 	Status         string            `json:"status"` // Computed overall status
 	SyncStatus     string            `json:"syncStatus"`
 	HealthStatus   string            `json:"healthStatus"`
@@ -38,10 +39,12 @@ type ArgoCDResource struct {
 
 // ArgoCDApplicationsResponse represents the response for ArgoCD application endpoints
 type ArgoCDApplicationsResponse struct {
-	Applications []ArgoCDApplication `json:"applications"`
-	Total        int                 `json:"total"`
-	Namespace    string              `json:"namespace,omitempty"`
-	Summary      ArgoCDSummary       `json:"summary"`
+	Applications  []ArgoCDApplication `json:"applications"`
+	Total         int                 `json:"total"`
+	Namespace     string              `json:"namespace,omitempty"`
+	Cluster       string              `json:"cluster,omitempty"`
+	Summary       ArgoCDSummary       `json:"summary"`
+	ClusterErrors map[string]string   `json:"clusterErrors,omitempty"`
 }
 
 // ArgoCDSummary provides aggregated statistics about ArgoCD applications
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newArgoApplication(syncStatus, healthStatus string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata": map[string]interface{}{
+			"name":              "checkout",
+			"namespace":         "argocd",
+			"creationTimestamp": "2025-01-02T03:04:05Z",
+			"labels":            map[string]interface{}{"team": "payments"},
+		},
+		"spec": map[string]interface{}{
+			"source": map[string]interface{}{
+				"repoURL":        "https://github.com/example/deploy.git",
+				"path":           "apps/checkout",
+				"targetRevision": "main",
+			},
+			"destination": map[string]interface{}{
+				"server":    "https://kubernetes.default.svc",
+				"namespace": "shop",
+			},
+		},
+		"status": map[string]interface{}{
+			"sync":           map[string]interface{}{"status": syncStatus},
+			"health":         map[string]interface{}{"status": healthStatus},
+			"operationState": map[string]interface{}{"phase": "Succeeded"},
+			"resources": []interface{}{
+				map[string]interface{}{
+					"group":     "apps",
+					"version":   "v1",
+					"kind":      "Deployment",
+					"namespace": "shop",
+					"name":      "checkout-api",
+					"status":    "Synced",
+					"health":    map[string]interface{}{"status": "Healthy"},
+				},
+				map[string]interface{}{
+					"version":   "v1",
+					"kind":      "Service",
+					"namespace": "shop",
+					"name":      "checkout-api",
+					"status":    "Synced",
+				},
+			},
+		},
+	}}
+	return obj
+}
+
+func TestFromArgoApplication(t *testing.T) {
+	app := FromArgoApplication(newArgoApplication("Synced", "Healthy"))
+
+	if app.Name != "checkout" || app.Namespace != "argocd" {
+		t.Fatalf("unexpected identity %s/%s", app.Namespace, app.Name)
+	}
+	if want := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC); !app.CreatedAt.Equal(want) {
+		t.Errorf("CreatedAt = %v, want %v", app.CreatedAt, want)
+	}
+	if app.Labels["team"] != "payments" {
+		t.Errorf("Labels = %v, want team=payments", app.Labels)
+	}
+
+	checks := map[string][2]string{
+		"RepoURL":        {app.RepoURL, "https://github.com/example/deploy.git"},
+		"Path":           {app.Path, "apps/checkout"},
+		"TargetRevision": {app.TargetRevision, "main"},
+		"Server":         {app.Server, "https://kubernetes.default.svc"},
+		"DestNamespace":  {app.DestNamespace, "shop"},
+		"SyncStatus":     {app.SyncStatus, "Synced"},
+		"HealthStatus":   {app.HealthStatus, "Healthy"},
+		"OperationState": {app.OperationState, "Succeeded"},
+		"Status":         {app.Status, "healthy"},
+	}
+	for field, values := range checks {
+		if values[0] != values[1] {
+			t.Errorf("%s = %q, want %q", field, values[0], values[1])
+		}
+	}
+
+	if len(app.Resources) != 2 {
+		t.Fatalf("len(Resources) = %d, want 2", len(app.Resources))
+	}
+	deployment := app.Resources[0]
+	if deployment.Kind != "Deployment" || deployment.Group != "apps" || deployment.Name != "checkout-api" {
+		t.Errorf("unexpected first resource %+v", deployment)
+	}
+	// Resource health is an object in ArgoCD, so it is not read as a string
+	if deployment.Health != "" {
+		t.Errorf("Health = %q, want empty", deployment.Health)
+	}
+	if app.Resources[1].Group != "" {
+		t.Errorf("core resource Group = %q, want empty", app.Resources[1].Group)
+	}
+}
+
+func TestFromArgoApplicationWithoutStatus(t *testing.T) {
+	obj := newArgoApplication("", "")
+	delete(obj.Object, "status")
+
+	app := FromArgoApplication(obj)
+	if app.SyncStatus != "" || app.HealthStatus != "" || len(app.Resources) != 0 {
+		t.Errorf("expected empty status fields, got %+v", app)
+	}
+	if app.Status != "unknown" {
+		t.Errorf("Status = %q, want unknown", app.Status)
+	}
+}
+
+func TestGetArgoCDStatus(t *testing.T) {
+	tests := []struct {
+		sync   string
+		health string
+		want   string
+	}{
+		{sync: "Synced", health: "Healthy", want: "healthy"},
+		{sync: "OutOfSync", health: "Healthy", want: "out-of-sync"},
+		{sync: "OutOfSync", health: "Degraded", want: "out-of-sync"},
+		{sync: "Synced", health: "Degraded", want: "degraded"},
+		{sync: "Synced", health: "Progressing", want: "progressing"},
+		{sync: "Synced", health: "Suspended", want: "suspended"},
+		{sync: "Synced", health: "Missing", want: "missing"},
+		{sync: "Unknown", health: "Unknown", want: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sync+"/"+tt.health, func(t *testing.T) {
+			app := ArgoCDApplication{SyncStatus: tt.sync, HealthStatus: tt.health}
+			if got := app.GetArgoCDStatus(); got != tt.want {
+				t.Errorf("GetArgoCDStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package models
+
+// ClusterInfo represents a cluster of the registry and its connectivity
+type ClusterInfo struct {
+	Name    string          `json:"name"`
+	Context string          `json:"context,omitempty"`
+	Server  string          `json:"server,omitempty"`
+	Default bool            `json:"default"`
+	Status  string          `json:"status"` // connected, disconnected
+	Version string          `json:"version,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Cache   map[string]bool `json:"cache,omitempty"`
+}
+
+// ClusterListResponse represents the response for the cluster list endpoint
+type ClusterListResponse struct {
+	Clusters       []ClusterInfo `json:"clusters"`
+	Total          int           `json:"total"`
+	DefaultCluster string        `json:"defaultCluster"`
+}
+
+// Cluster connectivity states
+const (
+	ClusterStatusConnected    = "connected"
+	ClusterStatusDisconnected = "disconnected"
+)
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Event types reported by Kubernetes
+const (
+	EventTypeNormal  = "Normal"
+	EventTypeWarning = "Warning"
+)
+
+// ObjectReference identifies the object an event is about
+type ObjectReference struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// EventInfo represents the events of one reason for one object, deduplicated
+type EventInfo struct {
+	Type      string          `json:"type"`
+	Reason    string          `json:"reason"`
+	Message   string          `json:"message"` // most recent message
+	Object    ObjectReference `json:"object"`
+	Source    string          `json:"source,omitempty"`
+	Count     int32           `json:"count"`
+	FirstSeen time.Time       `json:"firstSeen"`
+	LastSeen  time.Time       `json:"lastSeen"`
+}
+
+// EventsResponse represents the event timeline of a pod or an application
+type EventsResponse struct {
+	Kind      string            `json:"kind"` // Pod or Application
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Cluster   string            `json:"cluster,omitempty"`
+	Objects   []ObjectReference `json:"objects"`
+	Events    []EventInfo       `json:"events"`
+	Total     int               `json:"total"`
+	Warnings  int               `json:"warnings"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ApplicationFilter narrows application lists. Unset fields match every application.
+type ApplicationFilter struct {
+	Selector labels.Selector // matched against the application labels
+	Status   string
+	Type     string
+	Node     string // an application matches when any of its pods runs on the node
+	Search   string // case-insensitive substring of the application name
+}
+
+// Matches reports whether an application passes the filter
+func (f ApplicationFilter) Matches(app Application) bool {
+	if f.Selector != nil && !f.Selector.Matches(labels.Set(app.Labels)) {
+		return false
+	}
+	if f.Status != "" && app.Status != f.Status {
+		return false
+	}
+	if f.Type != "" && app.Type != f.Type {
+		return false
+	}
+	if f.Search != "" && !strings.Contains(strings.ToLower(app.Name), strings.ToLower(f.Search)) {
+		return false
+	}
+	if f.Node != "" {
+		for _, pod := range app.Pods {
+			if pod.Node == f.Node {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// PodFilter narrows pod lists after conversion. Label and field selectors, including the
+// node, are evaluated when listing pods.
+type PodFilter struct {
+	Status string // case-insensitive, e.g. running or CrashLoopBackOff
+	Search string // case-insensitive substring of the pod name
+}
+
+// Matches reports whether a pod passes the filter
+func (f PodFilter) Matches(pod PodStatus) bool {
+	if f.Status != "" && !strings.EqualFold(pod.Status, f.Status) {
+		return false
+	}
+	if f.Search != "" && !strings.Contains(strings.ToLower(pod.Name), strings.ToLower(f.Search)) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestApplicationFilterMatches(t *testing.T) {
+	app := Application{
+		Name:   "checkout-api",
+		Status: string(StatusDegraded),
+		Type:   string(TypeDeployment),
+		Labels: map[string]string{"team": "payments", "tier": "backend"},
+		Pods:   []PodStatus{{Name: "checkout-api-1", Node: "node-a"}, {Name: "checkout-api-2", Node: "node-b"}},
+	}
+
+	tests := []struct {
+		name   string
+		filter ApplicationFilter
+		want   bool
+	}{
+		{name: "empty filter", want: true},
+		{name: "selector", filter: ApplicationFilter{Selector: labels.SelectorFromSet(labels.Set{"team": "payments"})}, want: true},
+		{name: "selector mismatch", filter: ApplicationFilter{Selector: labels.SelectorFromSet(labels.Set{"tier": "frontend"})}},
+		{name: "status", filter: ApplicationFilter{Status: "degraded"}, want: true},
+		{name: "status mismatch", filter: ApplicationFilter{Status: "healthy"}},
+		{name: "type mismatch", filter: ApplicationFilter{Type: "statefulset"}},
+		{name: "node of a pod", filter: ApplicationFilter{Node: "node-b"}, want: true},
+		{name: "node without pods", filter: ApplicationFilter{Node: "node-c"}},
+		{name: "search", filter: ApplicationFilter{Search: "Checkout"}, want: true},
+		{name: "search mismatch", filter: ApplicationFilter{Search: "cart"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(app); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// StatusSample is one recorded observation of an application's health
+type StatusSample struct {
+	Cluster   string             `json:"cluster,omitempty"`
+	Namespace string             `json:"namespace"`
+	Name      string             `json:"name"`
+	Timestamp time.Time          `json:"timestamp"`
+	Status    string             `json:"status"`
+	Summary   ApplicationSummary `json:"summary"`
+}
+
+// HistoryPoint aggregates the samples of one step of a history series
+type HistoryPoint struct {
+	Timestamp    time.Time      `json:"timestamp"` // start of the step
+	Status       string         `json:"status"`    // worst status observed during the step
+	Samples      int            `json:"samples"`
+	Statuses     map[string]int `json:"statuses"` // samples per status
+	TotalPods    float64        `json:"totalPods"`
+	ReadyPods    float64        `json:"readyPods"`
+	RestartCount int            `json:"restartCount"` // highest restart count observed
+}
+
+// UptimeWindow reports how available an application was over a window ending now
+type UptimeWindow struct {
+	Window string    `json:"window"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	// Percentages of the observed time; nil when nothing was observed
+	UptimePercent       *float64 `json:"uptimePercent"`       // healthy
+	AvailabilityPercent *float64 `json:"availabilityPercent"` // at least one ready pod
+	// CoveragePercent is the share of the window with observations
+	CoveragePercent float64 `json:"coveragePercent"`
+}
+
+// ApplicationHistoryResponse represents the health history of an application
+type ApplicationHistoryResponse struct {
+	Application string         `json:"application"`
+	Namespace   string         `json:"namespace"`
+	Cluster     string         `json:"cluster,omitempty"`
+	From        time.Time      `json:"from"`
+	To          time.Time      `json:"to"`
+	Step        string         `json:"step"`
+	Points      []HistoryPoint `json:"points"`
+	Uptime      []UptimeWindow `json:"uptime"`
+}
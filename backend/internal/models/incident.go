@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// IncidentStatus describes whether an incident is still ongoing
+type IncidentStatus string
+
+const (
+	IncidentOpen   IncidentStatus = "open"
+	IncidentClosed IncidentStatus = "closed"
+)
+
+// IncidentSeverity ranks how badly an application was affected
+type IncidentSeverity string
+
+const (
+	SeverityMinor    IncidentSeverity = "minor"    // status unknown
+	SeverityMajor    IncidentSeverity = "major"    // degraded
+	SeverityCritical IncidentSeverity = "critical" // unhealthy
+)
+
+// SeverityForStatus maps an application status to an incident severity
+func SeverityForStatus(status string) IncidentSeverity {
+	switch ApplicationStatus(status) {
+	case StatusUnhealthy:
+		return SeverityCritical
+	case StatusDegraded:
+		return SeverityMajor
+	default:
+		return SeverityMinor
+	}
+}
+
+// Rank orders severities from minor to critical
+func (s IncidentSeverity) Rank() int {
+	switch s {
+	case SeverityCritical:
+		return 3
+	case SeverityMajor:
+		return 2
+	case SeverityMinor:
+		return 1
+	}
+	return 0
+}
+
+// IncidentUpdate records one status transition during an incident
+type IncidentUpdate struct {
+	Timestamp time.Time `json:"timestamp"`
+	Status    string    `json:"status"` // application status after the transition
+	Message   string    `json:"message"`
+}
+
+// Incident represents a period during which an application was not healthy
+type Incident struct {
+	ID            string           `json:"id"`
+	Cluster       string           `json:"cluster,omitempty"`
+	Namespace     string           `json:"namespace"`
+	Application   string           `json:"application"`
+	Status        IncidentStatus   `json:"status"`
+	Severity      IncidentSeverity `json:"severity"` // peak severity
+	CurrentStatus string           `json:"currentStatus"`
+	Title         string           `json:"title"`
+	Description   string           `json:"description"`
+	StartedAt     time.Time        `json:"startedAt"`
+	EndedAt       *time.Time       `json:"endedAt,omitempty"`
+	AffectedPods  []string         `json:"affectedPods"`
+	Updates       []IncidentUpdate `json:"updates"`
+	Events        []EventInfo      `json:"events"`
+}
+
+// IncidentListResponse represents the response for the incidents endpoint
+type IncidentListResponse struct {
+	Incidents []Incident `json:"incidents"`
+	Total     int        `json:"total"`
+	Open      int        `json:"open"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// LogLine represents a single container log line
+type LogLine struct {
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	Pod       string     `json:"pod,omitempty"`
+	Container string     `json:"container,omitempty"`
+	Message   string     `json:"message"`
+}
+
+// PodLogsResponse represents the response for the pod logs endpoint
+type PodLogsResponse struct {
+	Pod       string    `json:"pod"`
+	Namespace string    `json:"namespace"`
+	Cluster   string    `json:"cluster,omitempty"`
+	Container string    `json:"container"`
+	Previous  bool      `json:"previous"`
+	Lines     []LogLine `json:"lines"`
+	Total     int       `json:"total"`
+}
+
+// LogSource identifies one container log stream
+type LogSource struct {
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+}
+
+// ApplicationLogsResponse represents the response for the application logs endpoint
+type ApplicationLogsResponse struct {
+	Application string            `json:"application"`
+	Namespace   string            `json:"namespace"`
+	Cluster     string            `json:"cluster,omitempty"`
+	Sources     []LogSource       `json:"sources"`
+	Lines       []LogLine         `json:"lines"`
+	Total       int               `json:"total"`
+	Errors      map[string]string `json:"errors,omitempty"`
+}
+
+// ParseLogLine converts a raw log line into a LogLine, splitting off the
+// RFC3339 timestamp prefix that the API server adds when timestamps are requested
+func ParseLogLine(raw string, timestamps bool) LogLine {
+	if !timestamps {
+		return LogLine{Message: raw}
+	}
+
+	prefix, message, found := strings.Cut(raw, " ")
+	if !found {
+		prefix, message = raw, ""
+	}
+
+	ts, err := time.Parse(time.RFC3339Nano, prefix)
+	if err != nil {
+		return LogLine{Message: raw}
+	}
+	return LogLine{Timestamp: &ts, Message: message}
+}
+
+// String renders the line the way kubectl prints it
+func (l LogLine) String() string {
+	var b strings.Builder
+	if l.Pod != "" || l.Container != "" {
+		b.WriteString("[" + l.Pod + "/" + l.Container + "] ")
+	}
+	if l.Timestamp != nil {
+		b.WriteString(l.Timestamp.Format(time.RFC3339Nano) + " ")
+	}
+	b.WriteString(l.Message)
+	return b.String()
+}
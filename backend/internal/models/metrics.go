@@ -0,0 +1,72 @@
+package models
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ResourceQuantities holds CPU in millicores and memory in bytes
+type ResourceQuantities struct {
+	CPU    int64 `json:"cpu"`    // millicores
+	Memory int64 `json:"memory"` // bytes
+}
+
+// Add accumulates other quantities
+func (q *ResourceQuantities) Add(other ResourceQuantities) {
+	q.CPU += other.CPU
+	q.Memory += other.Memory
+}
+
+// ResourceUsage reports CPU and memory usage alongside requests and limits. Usage is only set
+// when metrics-server reported a sample; MetricsAvailable tells whether the metrics.k8s.io API
+// could be queried at all.
+type ResourceUsage struct {
+	MetricsAvailable bool                `json:"metricsAvailable"`
+	Usage            *ResourceQuantities `json:"usage,omitempty"`
+	Requests         ResourceQuantities  `json:"requests"`
+	Limits           ResourceQuantities  `json:"limits"`
+}
+
+// Add accumulates the usage, requests and limits of another resource usage
+func (u *ResourceUsage) Add(other ResourceUsage) {
+	u.Requests.Add(other.Requests)
+	u.Limits.Add(other.Limits)
+	if other.Usage != nil {
+		if u.Usage == nil {
+			u.Usage = &ResourceQuantities{}
+		}
+		u.Usage.Add(*other.Usage)
+	}
+}
+
+// NodeInfo represents a cluster node with its capacity and the resources of its pods
+type NodeInfo struct {
+	Name           string             `json:"name"`
+	Cluster        string             `json:"cluster,omitempty"`
+	Ready          bool               `json:"ready"`
+	KubeletVersion string             `json:"kubeletVersion,omitempty"`
+	Capacity       ResourceQuantities `json:"capacity"`
+	Allocatable    ResourceQuantities `json:"allocatable"`
+	Resources      ResourceUsage      `json:"resources"` // node usage, and requests and limits of its pods
+	PodCount       int                `json:"podCount"`
+}
+
+// NodeListResponse represents the response for node list endpoints
+type NodeListResponse struct {
+	Nodes            []NodeInfo        `json:"nodes"`
+	Total            int               `json:"total"`
+	Cluster          string            `json:"cluster,omitempty"`
+	MetricsAvailable bool              `json:"metricsAvailable"`
+	ClusterErrors    map[string]string `json:"clusterErrors,omitempty"`
+}
+
+// QuantitiesFromList extracts CPU and memory from a Kubernetes resource list
+func QuantitiesFromList(list corev1.ResourceList) ResourceQuantities {
+	var q ResourceQuantities
+	if cpu, ok := list[corev1.ResourceCPU]; ok {
+		q.CPU = cpu.MilliValue()
+	}
+	if memory, ok := list[corev1.ResourceMemory]; ok {
+		q.Memory = memory.Value()
+	}
+	return q
+}
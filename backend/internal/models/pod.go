@@ -9,32 +9,37 @@ import (
 
 // PodStatus represents the status information of a Kubernetes pod
 type PodStatus struct {
-	Name        string            `json:"name"`
-	Namespace   string            `json:"namespace"`
-	Status      string            `json:"status"`
-	Ready       bool              `json:"ready"`
-	Restarts    int32             `json:"restarts"`
-	Age         string            `json:"age"`
-	CreatedAt   time.Time         `json:"createdAt"`
-	Node        string            `json:"node,omitempty"`
-	IP          string            `json:"ip,omitempty"`
-	Labels      map[string]string `json:"labels,omitempty"`
-	Annotations map[string]string `json:"annotations,omitempty"`
-	Containers  []ContainerStatus `json:"containers"`
-	Conditions  []PodCondition    `json:"conditions,omitempty"`
-	OwnerKind   string            `json:"ownerKind,omitempty"`
-	OwnerName   string            `json:"ownerName,omitempty"`
-	Application string            `json:"application,omitempty"`
+	Name          string            `json:"name"`
+	Namespace     string            `json:"namespace"`
+	Cluster       string            `json:"cluster,omitempty"`
+	Status        string            `json:"status"`
+	Ready         bool              `json:"ready"`
+	Restarts      int32             `json:"restarts"`
+	Age           string            `json:"age"`
+	CreatedAt     time.Time         `json:"createdAt"`
+	Node          string            `json:"node,omitempty"`
+	IP            string            `json:"ip,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+	Containers    []ContainerStatus `json:"containers"`
+	Conditions    []PodCondition    `json:"conditions,omitempty"`
+	OwnerKind     string            `json:"ownerKind,omitempty"`
+	OwnerName     string            `json:"ownerName,omitempty"`
+	RootOwnerKind string            `json:"rootOwnerKind,omitempty"` // end of the owner chain, e.g. Deployment or CronJob
+	RootOwnerName string            `json:"rootOwnerName,omitempty"`
+	Application   string            `json:"application,omitempty"` // set by the application service
+	Resources     ResourceUsage     `json:"resources"`
 }
 
 // ContainerStatus represents the status of a container within a pod
 type ContainerStatus struct {
-	Name         string `json:"name"`
-	Ready        bool   `json:"ready"`
-	RestartCount int32  `json:"restartCount"`
-	Image        string `json:"image"`
-	State        string `json:"state"`
-	LastRestart  string `json:"lastRestart,omitempty"`
+	Name         string        `json:"name"`
+	Ready        bool          `json:"ready"`
+	RestartCount int32         `json:"restartCount"`
+	Image        string        `json:"image"`
+	State        string        `json:"state"`
+	LastRestart  string        `json:"lastRestart,omitempty"`
+	Resources    ResourceUsage `json:"resources"`
 }
 
 // PodCondition represents a pod condition
@@ -48,10 +53,13 @@ type PodCondition struct {
 
 // PodListResponse represents the response for pod list endpoints
 type PodListResponse struct {
-	Pods      []PodStatus `json:"pods"`
-	Total     int         `json:"total"`
-	Namespace string      `json:"namespace,omitempty"`
-	Summary   PodSummary  `json:"summary"`
+	Pods             []PodStatus       `json:"pods"`
+	Total            int               `json:"total"`
+	Namespace        string            `json:"namespace,omitempty"`
+	Cluster          string            `json:"cluster,omitempty"`
+	Summary          PodSummary        `json:"summary"`
+	MetricsAvailable bool              `json:"metricsAvailable"`
+	ClusterErrors    map[string]string `json:"clusterErrors,omitempty"`
 }
 
 // PodSummary provides aggregated statistics about pods
@@ -63,9 +71,26 @@ type PodSummary struct {
 	Unknown   int `json:"unknown"`
 }
 
+// Add counts a pod with the given phase
+func (s *PodSummary) Add(phase string) {
+	switch phase {
+	case "Running":
+		s.Running++
+	case "Pending":
+		s.Pending++
+	case "Succeeded":
+		s.Succeeded++
+	case "Failed":
+		s.Failed++
+	default:
+		s.Unknown++
+	}
+}
+
 // NamespaceInfo represents information about a Kubernetes namespace
 type NamespaceInfo struct {
 	Name        string            `json:"name"`
+	Cluster     string            `json:"cluster,omitempty"`
 	Status      string            `json:"status"`
 	Age         string            `json:"age"`
 	CreatedAt   time.Time         `json:"createdAt"`
@@ -76,8 +101,10 @@ type NamespaceInfo struct {
 
 // NamespaceListResponse represents the response for namespace list endpoints
 type NamespaceListResponse struct {
-	Namespaces []NamespaceInfo `json:"namespaces"`
-	Total      int             `json:"total"`
+	Namespaces    []NamespaceInfo   `json:"namespaces"`
+	Total         int               `json:"total"`
+	Cluster       string            `json:"cluster,omitempty"`
+	ClusterErrors map[string]string `json:"clusterErrors,omitempty"`
 }
 
 // FromK8sPod converts a Kubernetes Pod object to our PodStatus model
@@ -95,6 +122,18 @@ func FromK8sPod(pod *corev1.Pod) PodStatus {
 		}
 	}
 
+	// Requests and limits come from the pod spec
+	containerResources := make(map[string]ResourceUsage, len(pod.Spec.Containers))
+	var podResources ResourceUsage
+	for _, container := range pod.Spec.Containers {
+		resources := ResourceUsage{
+			Requests: QuantitiesFromList(container.Resources.Requests),
+			Limits:   QuantitiesFromList(container.Resources.Limits),
+		}
+		containerResources[container.Name] = resources
+		podResources.Add(resources)
+	}
+
 	// Calculate total restarts
 	var totalRestarts int32
 	var containers []ContainerStatus
@@ -123,6 +162,7 @@ func FromK8sPod(pod *corev1.Pod) PodStatus {
 			Image:        containerStatus.Image,
 			State:        containerState,
 			LastRestart:  lastRestart,
+			Resources:    containerResources[containerStatus.Name],
 		})
 	}
 
@@ -146,9 +186,6 @@ func FromK8sPod(pod *corev1.Pod) PodStatus {
 		ownerName = pod.OwnerReferences[0].Name
 	}
 
-	// Try to determine application name from labels
-	application := getApplicationName(pod.Labels)
-
 	return PodStatus{
 		Name:        pod.Name,
 		Namespace:   pod.Namespace,
@@ -165,7 +202,7 @@ func FromK8sPod(pod *corev1.Pod) PodStatus {
 		Conditions:  conditions,
 		OwnerKind:   ownerKind,
 		OwnerName:   ownerName,
-		Application: application,
+		Resources:   podResources,
 	}
 }
 
@@ -184,26 +221,6 @@ func FromK8sNamespace(ns *corev1.Namespace) NamespaceInfo {
 	}
 }
 
-// getApplicationName extracts application name from pod labels using common conventions
-func getApplicationName(labels map[string]string) string {
-	// Try different common label keys for application name
-	applicationKeys := []string{
-		"app.kubernetes.io/name",
-		"app.kubernetes.io/instance",
-		"app",
-		"application",
-		"k8s-app",
-	}
-
-	for _, key := range applicationKeys {
-		if value, exists := labels[key]; exists && value != "" {
-			return value
-		}
-	}
-
-	return ""
-}
-
 // formatDuration formats a duration into a human-readable string
 func formatDuration(d time.Duration) string {
 	if d < time.Minute {
@@ -1,19 +1,27 @@
 package models
 
 import (
+	"context"
+	"errors"
+	"net"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 // APIResponse represents a standard API response structure
 type APIResponse struct {
-	Success   bool        `json:"success"`
-	Data      interface{} `json:"data,omitempty"`
-	Error     *APIError   `json:"error,omitempty"`
-	Meta      *Meta       `json:"meta,omitempty"`
-	Timestamp time.Time   `json:"timestamp"`
+	Success       bool        `json:"success"`
+	Data          interface{} `json:"data,omitempty"`
+	Error         *APIError   `json:"error,omitempty"`
+	Meta          *Meta       `json:"meta,omitempty"`
+	Stale         bool        `json:"stale,omitempty"`         // data was served from a snapshot of an unreachable cluster
+	DataAge       string      `json:"dataAge,omitempty"`       // age of the oldest snapshot served
+	DataTimestamp *time.Time  `json:"dataTimestamp,omitempty"` // when the oldest snapshot served was fetched
+	Timestamp     time.Time   `json:"timestamp"`
 }
 
 // APIError represents error information in API responses
@@ -29,6 +37,9 @@ type Meta struct {
 	Page       int    `json:"page,omitempty"`
 	PageSize   int    `json:"pageSize,omitempty"`
 	TotalPages int    `json:"totalPages,omitempty"`
+	Sort       string `json:"sort,omitempty"`
+	Order      string `json:"order,omitempty"`
+	Continue   string `json:"continue,omitempty"` // token of the next chunk of a continued list
 	Namespace  string `json:"namespace,omitempty"`
 }
 
@@ -39,23 +50,27 @@ type HealthResponse struct {
 	Uptime           string            `json:"uptime"`
 	KubernetesStatus string            `json:"kubernetesStatus"`
 	Checks           map[string]string `json:"checks"`
+	Cache            map[string]bool   `json:"cache,omitempty"`
 	Timestamp        time.Time         `json:"timestamp"`
 }
 
 // ErrorCode constants for different types of errors
 const (
-	ErrCodeInternal          = "INTERNAL_ERROR"
-	ErrCodeBadRequest        = "BAD_REQUEST"
-	ErrCodeNotFound          = "NOT_FOUND"
-	ErrCodeUnauthorized      = "UNAUTHORIZED"
-	ErrCodeForbidden         = "FORBIDDEN"
-	ErrCodeValidation        = "VALIDATION_ERROR"
-	ErrCodeKubernetesAPI     = "KUBERNETES_API_ERROR"
-	ErrCodeNamespaceNotFound = "NAMESPACE_NOT_FOUND"
-	ErrCodePodNotFound       = "POD_NOT_FOUND"
-	ErrCodeResourceNotFound  = "RESOURCE_NOT_FOUND"
-	ErrCodeTimeout           = "TIMEOUT_ERROR"
-	ErrCodeRateLimit         = "RATE_LIMIT_EXCEEDED"
+	ErrCodeInternal           = "INTERNAL_ERROR"
+	ErrCodeBadRequest         = "BAD_REQUEST"
+	ErrCodeNotFound           = "NOT_FOUND"
+	ErrCodeUnauthorized       = "UNAUTHORIZED"
+	ErrCodeForbidden          = "FORBIDDEN"
+	ErrCodeValidation         = "VALIDATION_ERROR"
+	ErrCodeKubernetesAPI      = "KUBERNETES_API_ERROR"
+	ErrCodeNamespaceNotFound  = "NAMESPACE_NOT_FOUND"
+	ErrCodePodNotFound        = "POD_NOT_FOUND"
+	ErrCodeResourceNotFound   = "RESOURCE_NOT_FOUND"
+	ErrCodeTimeout            = "TIMEOUT_ERROR"
+	ErrCodeRateLimit          = "RATE_LIMIT_EXCEEDED"
+	ErrCodeClusterNotFound    = "CLUSTER_NOT_FOUND"
+	ErrCodeClusterUnavailable = "CLUSTER_UNAVAILABLE"
+	ErrCodeConflict           = "CONFLICT"
 )
 
 // NewSuccessResponse creates a successful API response
@@ -90,14 +105,29 @@ func NewErrorResponse(code, message, details string) *APIResponse {
 	}
 }
 
+// markStale flags a response built from snapshots served during the request
+func (r *APIResponse) markStale(c *gin.Context) {
+	fetchedAt, stale := StalenessFrom(c.Request.Context()).Oldest()
+	if !stale {
+		return
+	}
+	r.Stale = true
+	r.DataAge = r.Timestamp.Sub(fetchedAt).Round(time.Second).String()
+	r.DataTimestamp = &fetchedAt
+}
+
 // RespondSuccess sends a successful response
 func RespondSuccess(c *gin.Context, data interface{}) {
-	c.JSON(http.StatusOK, NewSuccessResponse(data))
+	response := NewSuccessResponse(data)
+	response.markStale(c)
+	c.JSON(http.StatusOK, response)
 }
 
 // RespondSuccessWithMeta sends a successful response with metadata
 func RespondSuccessWithMeta(c *gin.Context, data interface{}, meta *Meta) {
-	c.JSON(http.StatusOK, NewSuccessResponseWithMeta(data, meta))
+	response := NewSuccessResponseWithMeta(data, meta)
+	response.markStale(c)
+	c.JSON(http.StatusOK, response)
 }
 
 // RespondError sends an error response with appropriate HTTP status
@@ -120,11 +150,69 @@ func RespondInternalError(c *gin.Context, message, details string) {
 	RespondError(c, http.StatusInternalServerError, ErrCodeInternal, message, details)
 }
 
-// RespondKubernetesError sends an error response for Kubernetes API errors
+// kubernetesError is how an error from the Kubernetes API is reported
+type kubernetesError struct {
+	status  int
+	code    string
+	message string
+}
+
+// classifyKubernetesError maps an error from the Kubernetes API to its HTTP status and
+// error code. Errors the cluster did not classify are reported as bad gateway errors.
+func classifyKubernetesError(err error) kubernetesError {
+	var netErr net.Error
+	switch {
+	case apierrors.IsNotFound(err):
+		switch notFoundKind(err) {
+		case "namespaces":
+			return kubernetesError{http.StatusNotFound, ErrCodeNamespaceNotFound, "Namespace not found"}
+		case "pods":
+			return kubernetesError{http.StatusNotFound, ErrCodePodNotFound, "Pod not found"}
+		}
+		return kubernetesError{http.StatusNotFound, ErrCodeResourceNotFound, "Resource not found"}
+	case apierrors.IsForbidden(err):
+		return kubernetesError{http.StatusForbidden, ErrCodeForbidden, "Access denied by the cluster"}
+	case apierrors.IsConflict(err), apierrors.IsAlreadyExists(err):
+		return kubernetesError{http.StatusConflict, ErrCodeConflict, "Resource conflict"}
+	case apierrors.IsTooManyRequests(err):
+		return kubernetesError{http.StatusTooManyRequests, ErrCodeRateLimit, "Kubernetes API rate limit exceeded"}
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return kubernetesError{http.StatusGatewayTimeout, ErrCodeTimeout, "Kubernetes API request timed out"}
+	case apierrors.IsServiceUnavailable(err):
+		return kubernetesError{http.StatusServiceUnavailable, ErrCodeClusterUnavailable, "Kubernetes API unavailable"}
+	case apierrors.IsBadRequest(err), apierrors.IsInvalid(err):
+		return kubernetesError{http.StatusBadRequest, ErrCodeBadRequest, "Request rejected by the cluster"}
+	}
+	return kubernetesError{http.StatusBadGateway, ErrCodeKubernetesAPI, "Kubernetes API operation failed"}
+}
+
+// notFoundKind returns the resource of a not found error, e.g. pods
+func notFoundKind(err error) string {
+	var status apierrors.APIStatus
+	if errors.As(err, &status) && status.Status().Details != nil {
+		return status.Status().Details.Kind
+	}
+	return ""
+}
+
+// RespondKubernetesError sends an error response for Kubernetes API errors, with the
+// status and error code of the cluster's answer. Rate limited requests carry the delay
+// suggested by the cluster in Retry-After.
 func RespondKubernetesError(c *gin.Context, operation string, err error) {
-	message := "Kubernetes API operation failed"
+	kerr := classifyKubernetesError(err)
+	if seconds, ok := apierrors.SuggestsClientDelay(err); ok && kerr.status == http.StatusTooManyRequests {
+		c.Header("Retry-After", strconv.Itoa(seconds))
+	}
 	details := "Operation: " + operation + ", Error: " + err.Error()
-	RespondError(c, http.StatusBadGateway, ErrCodeKubernetesAPI, message, details)
+	RespondError(c, kerr.status, kerr.code, kerr.message, details)
+}
+
+// RespondNamespaceForbidden sends an error for a namespace the configuration does not allow
+func RespondNamespaceForbidden(c *gin.Context, namespace string) {
+	message := "Access to namespace not allowed"
+	details := "Namespace '" + namespace + "' is not in the allowed list"
+	RespondError(c, http.StatusForbidden, ErrCodeForbidden, message, details)
 }
 
 // RespondNamespaceNotFound sends a namespace not found error
@@ -141,6 +229,20 @@ func RespondPodNotFound(c *gin.Context, namespace, podName string) {
 	RespondError(c, http.StatusNotFound, ErrCodePodNotFound, message, details)
 }
 
+// RespondClusterNotFound sends a cluster not found error
+func RespondClusterNotFound(c *gin.Context, cluster string) {
+	message := "Cluster not found"
+	details := "The cluster '" + cluster + "' is not configured"
+	RespondError(c, http.StatusNotFound, ErrCodeClusterNotFound, message, details)
+}
+
+// RespondClusterUnavailable sends an error for a configured cluster that cannot be reached
+func RespondClusterUnavailable(c *gin.Context, cluster string, err error) {
+	message := "Cluster unavailable"
+	details := "Cluster: " + cluster + ", Error: " + err.Error()
+	RespondError(c, http.StatusServiceUnavailable, ErrCodeClusterUnavailable, message, details)
+}
+
 // RespondValidationError sends a validation error response
 func RespondValidationError(c *gin.Context, details string) {
 	RespondError(c, http.StatusBadRequest, ErrCodeValidation, "Validation failed", details)
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestClassifyKubernetesError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{name: "namespace not found", err: apierrors.NewNotFound(corev1.Resource("namespaces"), "shop"), status: http.StatusNotFound, code: ErrCodeNamespaceNotFound},
+		{name: "wrapped pod not found", err: fmt.Errorf("failed to get pod: %w", apierrors.NewNotFound(corev1.Resource("pods"), "api-1")), status: http.StatusNotFound, code: ErrCodePodNotFound},
+		{name: "other not found", err: apierrors.NewNotFound(schema.GroupResource{Group: "argoproj.io", Resource: "applications"}, "shop"), status: http.StatusNotFound, code: ErrCodeResourceNotFound},
+		{name: "forbidden", err: apierrors.NewForbidden(corev1.Resource("pods"), "", nil), status: http.StatusForbidden, code: ErrCodeForbidden},
+		{name: "conflict", err: apierrors.NewConflict(corev1.Resource("pods"), "api-1", nil), status: http.StatusConflict, code: ErrCodeConflict},
+		{name: "already exists", err: apierrors.NewAlreadyExists(corev1.Resource("pods"), "api-1"), status: http.StatusConflict, code: ErrCodeConflict},
+		{name: "rate limited", err: apierrors.NewTooManyRequests("slow down", 1), status: http.StatusTooManyRequests, code: ErrCodeRateLimit},
+		{name: "server timeout", err: apierrors.NewServerTimeout(corev1.Resource("pods"), "list", 1), status: http.StatusGatewayTimeout, code: ErrCodeTimeout},
+		{name: "deadline", err: fmt.Errorf("failed to list pods: %w", context.DeadlineExceeded), status: http.StatusGatewayTimeout, code: ErrCodeTimeout},
+		{name: "unavailable", err: apierrors.NewServiceUnavailable("etcd down"), status: http.StatusServiceUnavailable, code: ErrCodeClusterUnavailable},
+		{name: "invalid", err: apierrors.NewBadRequest("bad selector"), status: http.StatusBadRequest, code: ErrCodeBadRequest},
+		{name: "other", err: errors.New("boom"), status: http.StatusBadGateway, code: ErrCodeKubernetesAPI},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyKubernetesError(tt.err)
+			if got.status != tt.status || got.code != tt.code {
+				t.Errorf("classifyKubernetesError() = %d %s, want %d %s", got.status, got.code, tt.status, tt.code)
+			}
+		})
+	}
+}
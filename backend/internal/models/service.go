@@ -0,0 +1,68 @@
+package models
+
+import (
+	"sort"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+)
+
+// FromK8sEndpointSlices converts the EndpointSlices of a service to its endpoints. An
+// endpoint listed in several slices, as dual-stack services do, is reported once with
+// all of its addresses.
+func FromK8sEndpointSlices(slices []discoveryv1.EndpointSlice) []ServiceEndpoint {
+	var endpoints []ServiceEndpoint
+	index := make(map[string]int)
+
+	for _, slice := range slices {
+		for _, e := range slice.Endpoints {
+			if len(e.Addresses) == 0 {
+				continue
+			}
+
+			// Endpoints of a pod share its name across slices, others their address
+			key := e.Addresses[0]
+			podName := ""
+			if e.TargetRef != nil && e.TargetRef.Kind == "Pod" {
+				podName = e.TargetRef.Name
+				key = "pod/" + podName
+			}
+			if i, exists := index[key]; exists {
+				endpoints[i].Addresses = append(endpoints[i].Addresses, e.Addresses...)
+				continue
+			}
+
+			endpoint := ServiceEndpoint{
+				Addresses: append([]string(nil), e.Addresses...),
+				// A nil condition means the endpoint is ready
+				Ready:       e.Conditions.Ready == nil || *e.Conditions.Ready,
+				Terminating: e.Conditions.Terminating != nil && *e.Conditions.Terminating,
+				PodName:     podName,
+			}
+			if e.NodeName != nil {
+				endpoint.NodeName = *e.NodeName
+			}
+			index[key] = len(endpoints)
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].PodName != endpoints[j].PodName {
+			return endpoints[i].PodName < endpoints[j].PodName
+		}
+		return endpoints[i].Addresses[0] < endpoints[j].Addresses[0]
+	})
+	return endpoints
+}
+
+// CountEndpoints returns the number of ready and not ready endpoints
+func CountEndpoints(endpoints []ServiceEndpoint) (ready, notReady int) {
+	for _, e := range endpoints {
+		if e.Ready {
+			ready++
+		} else {
+			notReady++
+		}
+	}
+	return ready, notReady
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+)
+
+func TestFromK8sEndpointSlices(t *testing.T) {
+	notReady := false
+	terminating := true
+	node := "node-a"
+
+	slices := []discoveryv1.EndpointSlice{
+		{
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.1.0.5"}, NodeName: &node, TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "web-b"}},
+				{
+					Addresses:  []string{"10.1.0.4"},
+					Conditions: discoveryv1.EndpointConditions{Ready: &notReady, Terminating: &terminating},
+					TargetRef:  &corev1.ObjectReference{Kind: "Pod", Name: "web-a"},
+				},
+				{Addresses: []string{"192.168.1.20"}},
+			},
+		},
+		{
+			// The same pod in the IPv6 slice of a dual-stack service
+			AddressType: discoveryv1.AddressTypeIPv6,
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"fd00::5"}, TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "web-b"}},
+			},
+		},
+	}
+
+	endpoints := FromK8sEndpointSlices(slices)
+	if len(endpoints) != 3 {
+		t.Fatalf("got %d endpoints, want 3: %+v", len(endpoints), endpoints)
+	}
+
+	external, webA, webB := endpoints[0], endpoints[1], endpoints[2]
+	if external.PodName != "" || !external.Ready {
+		t.Errorf("unexpected endpoint without pod %+v", external)
+	}
+	if webA.PodName != "web-a" || webA.Ready || !webA.Terminating {
+		t.Errorf("unexpected web-a endpoint %+v", webA)
+	}
+	if webB.PodName != "web-b" || !webB.Ready || webB.NodeName != "node-a" || len(webB.Addresses) != 2 {
+		t.Errorf("unexpected web-b endpoint %+v", webB)
+	}
+
+	if ready, notReady := CountEndpoints(endpoints); ready != 2 || notReady != 1 {
+		t.Errorf("CountEndpoints() = %d, %d, want 2, 1", ready, notReady)
+	}
+}
@@ -0,0 +1,14 @@
+package models
+
+import "encoding/json"
+
+// Setting is one persisted user setting with an arbitrary JSON value
+type Setting struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value" swaggertype:"object"`
+}
+
+// SettingsResponse represents the response for the settings endpoint
+type SettingsResponse struct {
+	Settings map[string]json.RawMessage `json:"settings" swaggertype:"object"`
+}
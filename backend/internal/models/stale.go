@@ -0,0 +1,53 @@
+package models
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// staleKey is the context key of a request's Staleness
+type staleKey struct{}
+
+// Staleness records the snapshots served during a request in place of live data, when a
+// cluster could not be reached or its cached resources stopped being watched
+type Staleness struct {
+	mu     sync.Mutex
+	oldest time.Time
+}
+
+// WithStaleness returns a context recording the staleness of the data read with it.
+// Snapshots are only served to reads whose context records staleness.
+func WithStaleness(ctx context.Context) (context.Context, *Staleness) {
+	staleness := &Staleness{}
+	return context.WithValue(ctx, staleKey{}, staleness), staleness
+}
+
+// StalenessFrom returns the staleness recorded by a context, or nil
+func StalenessFrom(ctx context.Context) *Staleness {
+	staleness, _ := ctx.Value(staleKey{}).(*Staleness)
+	return staleness
+}
+
+// Record notes that data fetched at the given time was served
+func (s *Staleness) Record(fetchedAt time.Time) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.oldest.IsZero() || fetchedAt.Before(s.oldest) {
+		s.oldest = fetchedAt
+	}
+}
+
+// Oldest returns when the oldest served snapshot was fetched, or false when only live
+// data was served
+func (s *Staleness) Oldest() (time.Time, bool) {
+	if s == nil {
+		return time.Time{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.oldest, !s.oldest.IsZero()
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// ApplicationEventType describes how an application changed
+type ApplicationEventType string
+
+const (
+	ApplicationAdded   ApplicationEventType = "added"
+	ApplicationUpdated ApplicationEventType = "updated"
+	ApplicationRemoved ApplicationEventType = "removed"
+)
+
+// ApplicationState is the subset of an application that is tracked for change detection
+type ApplicationState struct {
+	Status  string             `json:"status"`
+	Version string             `json:"version,omitempty"`
+	Summary ApplicationSummary `json:"summary"`
+}
+
+// ApplicationEvent represents a change of an application between two observations
+type ApplicationEvent struct {
+	ID          uint64               `json:"id"`
+	Type        ApplicationEventType `json:"type"`
+	Cluster     string               `json:"cluster,omitempty"`
+	Namespace   string               `json:"namespace"`
+	Name        string               `json:"name"`
+	Changes     []string             `json:"changes,omitempty"` // status, pods, version
+	Previous    *ApplicationState    `json:"previous,omitempty"`
+	Current     *ApplicationState    `json:"current,omitempty"`
+	Application *Application         `json:"application,omitempty"`
+	Timestamp   time.Time            `json:"timestamp"`
+}
+
+// StateOf extracts the tracked state of an application
+func StateOf(app *Application) ApplicationState {
+	return ApplicationState{
+		Status:  app.Status,
+		Version: app.Version,
+		Summary: app.Summary,
+	}
+}
+
+// Diff lists which tracked aspects differ between two states
+func (s ApplicationState) Diff(other ApplicationState) []string {
+	var changes []string
+	if s.Status != other.Status {
+		changes = append(changes, "status")
+	}
+	if s.Summary.TotalPods != other.Summary.TotalPods ||
+		s.Summary.ReadyPods != other.Summary.ReadyPods ||
+		s.Summary.RunningPods != other.Summary.RunningPods ||
+		s.Summary.PendingPods != other.Summary.PendingPods ||
+		s.Summary.FailedPods != other.Summary.FailedPods {
+		changes = append(changes, "pods")
+	}
+	if s.Version != other.Version {
+		changes = append(changes, "version")
+	}
+	return changes
+}
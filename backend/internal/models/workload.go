@@ -0,0 +1,211 @@
+package models
+
+import (
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Workload kinds an application can be discovered from
+const (
+	WorkloadKindDeployment  = "Deployment"
+	WorkloadKindStatefulSet = "StatefulSet"
+	WorkloadKindDaemonSet   = "DaemonSet"
+)
+
+// WorkloadInfo describes the workload object an application was discovered from
+type WorkloadInfo struct {
+	Kind              string              `json:"kind"`
+	Name              string              `json:"name"`
+	Selector          string              `json:"selector,omitempty"`
+	DesiredReplicas   int32               `json:"desiredReplicas"`
+	ReadyReplicas     int32               `json:"readyReplicas"`
+	AvailableReplicas int32               `json:"availableReplicas"`
+	UpdatedReplicas   int32               `json:"updatedReplicas"`
+	Paused            bool                `json:"paused,omitempty"`
+	RolloutComplete   bool                `json:"rolloutComplete"`
+	Conditions        []WorkloadCondition `json:"conditions,omitempty"`
+}
+
+// WorkloadCondition represents a workload status condition, such as a Deployment's Progressing
+type WorkloadCondition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+}
+
+// FromK8sDeployment converts a Deployment to our WorkloadInfo model
+func FromK8sDeployment(deployment *appsv1.Deployment) WorkloadInfo {
+	desired := replicasOrDefault(deployment.Spec.Replicas)
+	status := deployment.Status
+
+	var conditions []WorkloadCondition
+	for _, c := range status.Conditions {
+		conditions = append(conditions, WorkloadCondition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: c.LastTransitionTime.Time,
+		})
+	}
+
+	return WorkloadInfo{
+		Kind:              WorkloadKindDeployment,
+		Name:              deployment.Name,
+		Selector:          selectorString(deployment.Spec.Selector),
+		DesiredReplicas:   desired,
+		ReadyReplicas:     status.ReadyReplicas,
+		AvailableReplicas: status.AvailableReplicas,
+		UpdatedReplicas:   status.UpdatedReplicas,
+		Paused:            deployment.Spec.Paused,
+		RolloutComplete: status.ObservedGeneration >= deployment.Generation &&
+			status.UpdatedReplicas == desired && status.Replicas == desired,
+		Conditions: conditions,
+	}
+}
+
+// FromK8sStatefulSet converts a StatefulSet to our WorkloadInfo model
+func FromK8sStatefulSet(statefulSet *appsv1.StatefulSet) WorkloadInfo {
+	desired := replicasOrDefault(statefulSet.Spec.Replicas)
+	status := statefulSet.Status
+
+	var conditions []WorkloadCondition
+	for _, c := range status.Conditions {
+		conditions = append(conditions, WorkloadCondition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: c.LastTransitionTime.Time,
+		})
+	}
+
+	// Pods of an OnDelete stateful set are only updated when deleted by hand
+	rolloutComplete := status.ObservedGeneration >= statefulSet.Generation
+	if statefulSet.Spec.UpdateStrategy.Type != appsv1.OnDeleteStatefulSetStrategyType {
+		rolloutComplete = rolloutComplete && status.UpdatedReplicas == desired &&
+			(status.UpdateRevision == "" || status.CurrentRevision == status.UpdateRevision)
+	}
+
+	return WorkloadInfo{
+		Kind:              WorkloadKindStatefulSet,
+		Name:              statefulSet.Name,
+		Selector:          selectorString(statefulSet.Spec.Selector),
+		DesiredReplicas:   desired,
+		ReadyReplicas:     status.ReadyReplicas,
+		AvailableReplicas: status.AvailableReplicas,
+		UpdatedReplicas:   status.UpdatedReplicas,
+		RolloutComplete:   rolloutComplete,
+		Conditions:        conditions,
+	}
+}
+
+// FromK8sDaemonSet converts a DaemonSet to our WorkloadInfo model. Its desired replicas
+// are the nodes it should run on.
+func FromK8sDaemonSet(daemonSet *appsv1.DaemonSet) WorkloadInfo {
+	status := daemonSet.Status
+
+	var conditions []WorkloadCondition
+	for _, c := range status.Conditions {
+		conditions = append(conditions, WorkloadCondition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: c.LastTransitionTime.Time,
+		})
+	}
+
+	rolloutComplete := status.ObservedGeneration >= daemonSet.Generation
+	if daemonSet.Spec.UpdateStrategy.Type != appsv1.OnDeleteDaemonSetStrategyType {
+		rolloutComplete = rolloutComplete && status.UpdatedNumberScheduled == status.DesiredNumberScheduled
+	}
+
+	return WorkloadInfo{
+		Kind:              WorkloadKindDaemonSet,
+		Name:              daemonSet.Name,
+		Selector:          selectorString(daemonSet.Spec.Selector),
+		DesiredReplicas:   status.DesiredNumberScheduled,
+		ReadyReplicas:     status.NumberReady,
+		AvailableReplicas: status.NumberAvailable,
+		UpdatedReplicas:   status.UpdatedNumberScheduled,
+		RolloutComplete:   rolloutComplete,
+		Conditions:        conditions,
+	}
+}
+
+// ApplicationType returns the application type of a workload kind
+func (w WorkloadInfo) ApplicationType() ApplicationType {
+	switch w.Kind {
+	case WorkloadKindDeployment:
+		return TypeDeployment
+	case WorkloadKindStatefulSet:
+		return TypeStatefulSet
+	case WorkloadKindDaemonSet:
+		return TypeDaemonSet
+	default:
+		return TypeStandalone
+	}
+}
+
+// Condition returns the condition of the given type, if the workload reports it
+func (w WorkloadInfo) Condition(conditionType string) (WorkloadCondition, bool) {
+	for _, c := range w.Conditions {
+		if c.Type == conditionType {
+			return c, true
+		}
+	}
+	return WorkloadCondition{}, false
+}
+
+// DetermineWorkloadStatus calculates the health of an application from its workload
+// replica counts and rollout conditions, so that scaled-to-zero workloads and workloads
+// whose pods are all gone are still reported
+func DetermineWorkloadStatus(workload WorkloadInfo) ApplicationStatus {
+	// Scaled to zero on purpose
+	if workload.DesiredReplicas == 0 {
+		return StatusHealthy
+	}
+
+	// The rollout is stuck or pods cannot be created
+	if c, ok := workload.Condition(string(appsv1.DeploymentProgressing)); ok &&
+		c.Status == string(corev1.ConditionFalse) && c.Reason == "ProgressDeadlineExceeded" {
+		return StatusUnhealthy
+	}
+	if c, ok := workload.Condition(string(appsv1.DeploymentReplicaFailure)); ok && c.Status == string(corev1.ConditionTrue) {
+		return StatusUnhealthy
+	}
+
+	// Nothing is serving
+	if workload.AvailableReplicas == 0 {
+		return StatusUnhealthy
+	}
+
+	// Missing replicas or a rollout in progress
+	if workload.AvailableReplicas < workload.DesiredReplicas || !workload.RolloutComplete {
+		return StatusDegraded
+	}
+
+	return StatusHealthy
+}
+
+// replicasOrDefault returns the replica count of a spec, which defaults to one
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// selectorString formats a label selector, or returns an empty string when it is unset
+func selectorString(selector *metav1.LabelSelector) string {
+	if selector == nil {
+		return ""
+	}
+	return metav1.FormatLabelSelector(selector)
+}
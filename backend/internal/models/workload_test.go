@@ -0,0 +1,87 @@
+package models
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDetermineWorkloadStatus(t *testing.T) {
+	progressDeadline := WorkloadCondition{Type: "Progressing", Status: "False", Reason: "ProgressDeadlineExceeded"}
+	replicaFailure := WorkloadCondition{Type: "ReplicaFailure", Status: "True", Reason: "FailedCreate"}
+
+	tests := []struct {
+		name     string
+		workload WorkloadInfo
+		want     ApplicationStatus
+	}{
+		{
+			name:     "scaled to zero",
+			workload: WorkloadInfo{DesiredReplicas: 0, RolloutComplete: true},
+			want:     StatusHealthy,
+		},
+		{
+			name:     "all replicas available",
+			workload: WorkloadInfo{DesiredReplicas: 3, AvailableReplicas: 3, RolloutComplete: true},
+			want:     StatusHealthy,
+		},
+		{
+			name:     "some replicas unavailable",
+			workload: WorkloadInfo{DesiredReplicas: 3, AvailableReplicas: 2, RolloutComplete: true},
+			want:     StatusDegraded,
+		},
+		{
+			name:     "rollout in progress",
+			workload: WorkloadInfo{DesiredReplicas: 3, AvailableReplicas: 3},
+			want:     StatusDegraded,
+		},
+		{
+			name:     "no replica available",
+			workload: WorkloadInfo{DesiredReplicas: 2, RolloutComplete: true},
+			want:     StatusUnhealthy,
+		},
+		{
+			name:     "progress deadline exceeded",
+			workload: WorkloadInfo{DesiredReplicas: 2, AvailableReplicas: 2, Conditions: []WorkloadCondition{progressDeadline}},
+			want:     StatusUnhealthy,
+		},
+		{
+			name:     "replica failure",
+			workload: WorkloadInfo{DesiredReplicas: 2, AvailableReplicas: 1, Conditions: []WorkloadCondition{replicaFailure}},
+			want:     StatusUnhealthy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetermineWorkloadStatus(tt.workload); got != tt.want {
+				t.Errorf("DetermineWorkloadStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromK8sStatefulSet(t *testing.T) {
+	replicas := int32(3)
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Generation: 2},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:       &replicas,
+			Selector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType},
+		},
+		Status: appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, AvailableReplicas: 3, UpdatedReplicas: 1},
+	}
+
+	// OnDelete stateful sets never finish rolling out on their own
+	workload := FromK8sStatefulSet(statefulSet)
+	if !workload.RolloutComplete || workload.DesiredReplicas != 3 || workload.Selector != "app=db" {
+		t.Errorf("unexpected workload %+v", workload)
+	}
+
+	statefulSet.Spec.UpdateStrategy.Type = appsv1.RollingUpdateStatefulSetStrategyType
+	if FromK8sStatefulSet(statefulSet).RolloutComplete {
+		t.Error("rolling update with outdated replicas reported as complete")
+	}
+}
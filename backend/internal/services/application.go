@@ -2,27 +2,47 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"k8s-monitor/internal/models"
 	"k8s-monitor/pkg/utils"
 )
 
+// maxConcurrentBuilds bounds the applications of a listing built at once
+const maxConcurrentBuilds = 8
+
+var (
+	// ErrApplicationNotFound is returned when no workload or pods make up the requested application
+	ErrApplicationNotFound = errors.New("application not found")
+	// ErrNamespaceNotAllowed is returned for namespaces excluded by the configuration
+	ErrNamespaceNotAllowed = errors.New("access to namespace not allowed")
+)
+
 // ApplicationService provides application-centric operations
 type ApplicationService struct {
-	k8sService *KubernetesService
+	k8sService KubernetesClient
+	grouper    *Grouper
 	logger     *logrus.Logger
 }
 
-// NewApplicationService creates a new application service instance
-func NewApplicationService(k8sService *KubernetesService, logger *logrus.Logger) *ApplicationService {
+// NewApplicationService creates a new application service instance. A nil grouper
+// applies the default grouping.
+func NewApplicationService(k8sService KubernetesClient, grouper *Grouper, logger *logrus.Logger) *ApplicationService {
+	if grouper == nil {
+		grouper = DefaultGrouper()
+	}
 	return &ApplicationService{
 		k8sService: k8sService,
+		grouper:    grouper,
 		logger:     logger,
 	}
 }
@@ -32,67 +52,45 @@ func (a *ApplicationService) GetApplications(ctx context.Context) (*models.Appli
 	logger := utils.WithComponent(a.logger, "application-service")
 	logger.Info("Fetching all applications")
 
-	// Get all pods
-	podList, err := a.k8sService.GetAllPods(ctx)
+	// Discover applications from workloads and pods
+	groups, err := a.discoverApplications(ctx, "")
 	if err != nil {
-		return nil, fmt.Errorf("failed to get pods: %w", err)
+		return nil, err
 	}
 
-	// Group pods by application
-	applicationMap := a.groupPodsByApplication(podList.Items)
-
-	// Convert to application models
-	var applications []models.Application
-	summary := models.ApplicationsSummary{}
-
-	for appKey, pods := range applicationMap {
-		if len(pods) == 0 {
-			continue
-		}
-
-		// Check if namespace is allowed
-		if !a.k8sService.IsNamespaceAllowed(pods[0].Namespace) {
-			continue
-		}
+	// Get resource usage, if metrics-server is installed
+	metrics := GetPodMetrics(ctx, a.k8sService, "", logger)
 
-		app := a.buildApplicationFromPods(ctx, appKey, pods)
-		applications = append(applications, app)
-
-		// Update summary
-		switch app.Status {
-		case string(models.StatusHealthy):
-			summary.Healthy++
-		case string(models.StatusDegraded):
-			summary.Degraded++
-		case string(models.StatusUnhealthy):
-			summary.Unhealthy++
-		default:
-			summary.Unknown++
-		}
+	// Services are listed once for all applications
+	services := a.loadServices(ctx, "")
 
-		summary.TotalPods += app.Summary.TotalPods
-		summary.ReadyPods += app.Summary.ReadyPods
-		summary.RunningPods += app.Summary.RunningPods
-	}
-
-	// Sort applications by name
-	sort.Slice(applications, func(i, j int) bool {
-		if applications[i].Namespace != applications[j].Namespace {
-			return applications[i].Namespace < applications[j].Namespace
-		}
-		return applications[i].Name < applications[j].Name
-	})
+	applications, summary := a.buildApplications(groups, metrics, services)
 
 	response := &models.ApplicationsResponse{
-		Applications: applications,
-		Total:        len(applications),
-		Summary:      summary,
+		Applications:     applications,
+		Total:            len(applications),
+		Summary:          summary,
+		MetricsAvailable: metrics.Available,
 	}
 
 	logger.WithField("total", len(applications)).Info("Successfully fetched applications")
 	return response, nil
 }
 
+// ObserveApplications builds all applications like GetApplications, without resource
+// usage, which status changes do not depend on. It serves the application watcher's
+// frequent polls, so it only logs at debug level.
+func (a *ApplicationService) ObserveApplications(ctx context.Context) ([]models.Application, error) {
+	groups, err := a.discoverApplications(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	applications, _ := a.buildApplications(groups, PodMetrics{}, a.loadServices(ctx, ""))
+
+	utils.WithComponent(a.logger, "application-service").WithField("total", len(applications)).Debug("Observed applications")
+	return applications, nil
+}
+
 // GetApplicationsByNamespace retrieves applications from a specific namespace
 func (a *ApplicationService) GetApplicationsByNamespace(ctx context.Context, namespace string) (*models.ApplicationsResponse, error) {
 	logger := utils.WithNamespace(a.logger, namespace)
@@ -100,61 +98,99 @@ func (a *ApplicationService) GetApplicationsByNamespace(ctx context.Context, nam
 
 	// Check if namespace is allowed
 	if !a.k8sService.IsNamespaceAllowed(namespace) {
-		return nil, fmt.Errorf("access to namespace '%s' not allowed", namespace)
+		return nil, fmt.Errorf("%w: %s", ErrNamespaceNotAllowed, namespace)
 	}
 
-	// Get pods from the specified namespace
-	podList, err := a.k8sService.GetPods(ctx, namespace)
+	// Discover applications from the workloads and pods of the namespace
+	groups, err := a.discoverApplications(ctx, namespace)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get pods from namespace %s: %w", namespace, err)
+		return nil, err
 	}
 
-	// Group pods by application
-	applicationMap := a.groupPodsByApplication(podList.Items)
+	// Get resource usage, if metrics-server is installed
+	metrics := GetPodMetrics(ctx, a.k8sService, namespace, logger)
 
-	// Convert to application models
-	var applications []models.Application
-	summary := models.ApplicationsSummary{}
+	// Services are listed once for all applications
+	services := a.loadServices(ctx, namespace)
 
-	for appKey, pods := range applicationMap {
-		if len(pods) == 0 {
-			continue
-		}
+	applications, summary := a.buildApplications(groups, metrics, services)
 
-		app := a.buildApplicationFromPods(ctx, appKey, pods)
-		applications = append(applications, app)
-
-		// Update summary
-		switch app.Status {
-		case string(models.StatusHealthy):
-			summary.Healthy++
-		case string(models.StatusDegraded):
-			summary.Degraded++
-		case string(models.StatusUnhealthy):
-			summary.Unhealthy++
-		default:
-			summary.Unknown++
-		}
+	response := &models.ApplicationsResponse{
+		Applications:     applications,
+		Total:            len(applications),
+		Namespace:        namespace,
+		Summary:          summary,
+		MetricsAvailable: metrics.Available,
+	}
 
-		summary.TotalPods += app.Summary.TotalPods
-		summary.ReadyPods += app.Summary.ReadyPods
-		summary.RunningPods += app.Summary.RunningPods
+	logger.WithField("total", len(applications)).Info("Successfully fetched applications by namespace")
+	return response, nil
+}
+
+// GetApplication builds a single application of a namespace
+func (a *ApplicationService) GetApplication(ctx context.Context, namespace, name string) (*models.Application, error) {
+	group, err := a.findApplication(ctx, namespace, name)
+	if err != nil {
+		return nil, err
 	}
 
-	// Sort applications by name
-	sort.Slice(applications, func(i, j int) bool {
-		return applications[i].Name < applications[j].Name
-	})
+	logger := utils.WithApplication(a.logger, namespace, name)
+	metrics := GetPodMetrics(ctx, a.k8sService, namespace, logger)
+	app := a.buildApplication(applicationKey{namespace: namespace, name: name}, group, metrics, a.loadServices(ctx, namespace))
+	return &app, nil
+}
 
-	response := &models.ApplicationsResponse{
-		Applications: applications,
-		Total:        len(applications),
-		Namespace:    namespace,
-		Summary:      summary,
+// GetApplicationPods returns the pods grouped under an application, using the same
+// grouping as the application listings. Workloads scaled to zero have no pods.
+func (a *ApplicationService) GetApplicationPods(ctx context.Context, namespace, name string) ([]corev1.Pod, error) {
+	group, err := a.findApplication(ctx, namespace, name)
+	if err != nil {
+		return nil, err
 	}
+	return group.pods, nil
+}
 
-	logger.WithField("total", len(applications)).Info("Successfully fetched applications by namespace")
-	return response, nil
+// findApplication discovers the applications of a namespace and returns the named one
+func (a *ApplicationService) findApplication(ctx context.Context, namespace, name string) (*applicationGroup, error) {
+	// Check if namespace is allowed
+	if !a.k8sService.IsNamespaceAllowed(namespace) {
+		return nil, fmt.Errorf("%w: %s", ErrNamespaceNotAllowed, namespace)
+	}
+
+	groups, err := a.discoverApplications(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	group, exists := groups[applicationKey{namespace: namespace, name: name}]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s/%s", ErrApplicationNotFound, namespace, name)
+	}
+	return group, nil
+}
+
+// GetApplicationLogSources lists the pod containers of an application, optionally
+// restricted to containers with the given name. An application without pods, such as
+// one scaled to zero, has no sources.
+func (a *ApplicationService) GetApplicationLogSources(ctx context.Context, namespace, name, container string) ([]models.LogSource, error) {
+	pods, err := a.GetApplicationPods(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []models.LogSource
+	for _, pod := range pods {
+		for _, c := range pod.Spec.Containers {
+			if container == "" || c.Name == container {
+				sources = append(sources, models.LogSource{Pod: pod.Name, Container: c.Name})
+			}
+		}
+	}
+
+	if len(pods) > 0 && len(sources) == 0 {
+		return nil, fmt.Errorf("%w: %q in application %s", ErrContainerNotFound, container, name)
+	}
+	return sources, nil
 }
 
 // applicationKey represents a unique identifier for an application
@@ -163,12 +199,16 @@ type applicationKey struct {
 	name      string
 }
 
-// groupPodsByApplication groups pods by their application identity
-func (a *ApplicationService) groupPodsByApplication(pods []corev1.Pod) map[applicationKey][]corev1.Pod {
+// groupPodsByApplication groups pods by their application identity. roots holds the
+// resolved root owners of the pods and may be nil.
+func (a *ApplicationService) groupPodsByApplication(pods []corev1.Pod, roots podRoots) map[applicationKey][]corev1.Pod {
 	applicationMap := make(map[applicationKey][]corev1.Pod)
 
 	for _, pod := range pods {
-		appName := a.extractApplicationName(pod)
+		appName := a.grouper.System(pod.Namespace, pod.Labels)
+		if appName == "" {
+			appName = a.extractApplicationName(pod, roots.get(pod))
+		}
 		key := applicationKey{
 			namespace: pod.Namespace,
 			name:      appName,
@@ -180,36 +220,100 @@ func (a *ApplicationService) groupPodsByApplication(pods []corev1.Pod) map[appli
 	return applicationMap
 }
 
-// extractApplicationName extracts the application name from a pod using various strategies
-func (a *ApplicationService) extractApplicationName(pod corev1.Pod) string {
-	// Strategy 1: Check standard application labels
-	applicationKeys := []string{
-		"app.kubernetes.io/name",
-		"app.kubernetes.io/instance",
-		"app",
-		"application",
-		"k8s-app",
+// PodOwners describes pods listed outside of application discovery
+type PodOwners struct {
+	service  *ApplicationService
+	resolver *ownerResolver
+}
+
+// PodOwners creates a describer that looks each owner up once, for a single request.
+// Lists of pods are described with PodListOwners.
+func (a *ApplicationService) PodOwners() *PodOwners {
+	return &PodOwners{
+		service:  a,
+		resolver: newOwnerResolver(a.k8sService, utils.WithComponent(a.logger, "application-service")),
 	}
+}
+
+// PodListOwners creates a describer for a list of the pods of a namespace, or of all
+// namespaces when namespace is empty. The ReplicaSets, Jobs and workloads of the namespace
+// are listed up front, so that only owners of other kinds are looked up one by one.
+func (a *ApplicationService) PodListOwners(ctx context.Context, namespace string) *PodOwners {
+	owners := a.PodOwners()
+	logger := utils.WithComponent(a.logger, "application-service").WithField("namespace", namespace)
 
-	for _, key := range applicationKeys {
-		if value, exists := pod.Labels[key]; exists && value != "" {
-			return value
+	if replicaSets, err := a.k8sService.GetReplicaSets(ctx, namespace); err == nil {
+		for _, rs := range replicaSets.Items {
+			owners.resolver.seed(rs.Namespace, "ReplicaSet", rs.Name, rs.OwnerReferences)
+		}
+	} else {
+		logger.WithError(err).Debug("Failed to list replica sets, resolving their owners one by one")
+	}
+	if jobs, err := a.k8sService.GetJobs(ctx, namespace); err == nil {
+		for _, job := range jobs.Items {
+			owners.resolver.seed(job.Namespace, "Job", job.Name, job.OwnerReferences)
 		}
+	} else {
+		logger.WithError(err).Debug("Failed to list jobs, resolving their owners one by one")
 	}
+	if workloads, err := a.listWorkloads(ctx, namespace); err == nil {
+		for _, w := range workloads {
+			owners.resolver.seed(w.namespace, w.info.Kind, w.info.Name, w.ownerRefs)
+		}
+	} else {
+		logger.WithError(err).Debug("Failed to list workloads, resolving their owners one by one")
+	}
+	return owners
+}
 
-	// Strategy 2: Extract from owner reference
-	if len(pod.OwnerReferences) > 0 {
-		ownerName := pod.OwnerReferences[0].Name
+// Apply sets the root owner of a converted pod and the application it is listed under:
+// its system, the workload in its owner chain, or its grouping keys
+func (p *PodOwners) Apply(ctx context.Context, pod corev1.Pod, status *models.PodStatus) {
+	chain := p.resolver.chain(ctx, pod.Namespace, pod.OwnerReferences)
 
-		// For ReplicaSet, try to get the Deployment name
-		if pod.OwnerReferences[0].Kind == "ReplicaSet" {
+	var root *models.ObjectReference
+	if len(chain) > 0 {
+		root = &chain[len(chain)-1]
+		status.RootOwnerKind = root.Kind
+		status.RootOwnerName = root.Name
+	}
+
+	if system := p.service.grouper.System(pod.Namespace, pod.Labels); system != "" {
+		status.Application = system
+		return
+	}
+	for _, owner := range chain {
+		switch owner.Kind {
+		case models.WorkloadKindDeployment, models.WorkloadKindStatefulSet, models.WorkloadKindDaemonSet:
+			status.Application = owner.Name
+			return
+		}
+	}
+	status.Application = p.service.extractApplicationName(pod, root)
+}
+
+// extractApplicationName extracts the application name from a pod using various
+// strategies. root is the resolved root owner of the pod, if known.
+func (a *ApplicationService) extractApplicationName(pod corev1.Pod, root *models.ObjectReference) string {
+	// Strategy 1: Check the configured application labels and annotations
+	if name := a.grouper.Name(pod.Namespace, pod.Labels, pod.Annotations); name != "" {
+		return name
+	}
+
+	// Strategy 2: Extract from the root owner, or the direct owner when unresolved
+	if root == nil && len(pod.OwnerReferences) > 0 {
+		root = &models.ObjectReference{Kind: pod.OwnerReferences[0].Kind, Name: pod.OwnerReferences[0].Name}
+	}
+	if root != nil {
+		// For an unresolved ReplicaSet, guess the Deployment name
+		if root.Kind == "ReplicaSet" {
 			// ReplicaSet names typically follow the pattern: deploymentname-randomstring
-			if idx := findLastDash(ownerName); idx > 0 {
-				return ownerName[:idx]
+			if idx := findLastDash(root.Name); idx > 0 {
+				return root.Name[:idx]
 			}
 		}
 
-		return ownerName
+		return root.Name
 	}
 
 	// Strategy 3: Use pod name prefix (before first dash)
@@ -221,17 +325,68 @@ func (a *ApplicationService) extractApplicationName(pod corev1.Pod) string {
 	return pod.Name
 }
 
-// buildApplicationFromPods creates an Application model from grouped pods
-func (a *ApplicationService) buildApplicationFromPods(ctx context.Context, key applicationKey, k8sPods []corev1.Pod) models.Application {
+// buildApplications builds the applications of the allowed namespaces, sorted by namespace
+// and name, with a bounded number of workers, and counts them into a summary
+func (a *ApplicationService) buildApplications(groups map[applicationKey]*applicationGroup, metrics PodMetrics, services *serviceIndex) ([]models.Application, models.ApplicationsSummary) {
+	keys := make([]applicationKey, 0, len(groups))
+	for key := range groups {
+		// Check if namespace is allowed
+		if a.k8sService.IsNamespaceAllowed(key.namespace) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].namespace != keys[j].namespace {
+			return keys[i].namespace < keys[j].namespace
+		}
+		return keys[i].name < keys[j].name
+	})
+
+	// Each worker writes its own slot, so results need no locking
+	applications := make([]models.Application, len(keys))
+	sem := make(chan struct{}, maxConcurrentBuilds)
+
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, key applicationKey) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			applications[i] = a.buildApplication(key, groups[key], metrics, services)
+		}(i, key)
+	}
+	wg.Wait()
+
+	summary := models.ApplicationsSummary{}
+	for _, app := range applications {
+		summary.Count(app)
+	}
+	return applications, summary
+}
+
+// buildApplication creates an Application model from a discovered workload and its pods.
+// Applications without a workload are described by their pods alone.
+func (a *ApplicationService) buildApplication(key applicationKey, group *applicationGroup, metrics PodMetrics, services *serviceIndex) models.Application {
+	k8sPods := group.pods
+
 	// Convert k8s pods to our pod models
-	var pods []models.PodStatus
+	pods := make([]models.PodStatus, 0, len(k8sPods))
 	var oldestCreation time.Time
 	var newestUpdate time.Time
 	var labels map[string]string
 	var annotations map[string]string
+	resources := models.ResourceUsage{MetricsAvailable: metrics.Available}
 
 	for i, k8sPod := range k8sPods {
 		podStatus := models.FromK8sPod(&k8sPod)
+		podStatus.Application = key.name
+		if root := group.roots.get(k8sPod); root != nil {
+			podStatus.RootOwnerKind = root.Kind
+			podStatus.RootOwnerName = root.Name
+		}
+		metrics.Apply(&podStatus)
+		resources.Add(podStatus.Resources)
 		pods = append(pods, podStatus)
 
 		// Track creation and update times
@@ -264,69 +419,196 @@ func (a *ApplicationService) buildApplicationFromPods(ctx context.Context, key a
 	// Get version information
 	version := models.GetApplicationVersion(labels, annotations)
 
-	// Get services for this application (optional, can be implemented later)
-	services := a.getApplicationServices(ctx, key.namespace, key.name)
+	// Workloads know the desired state, even when none of their pods exist
+	var workloads []models.WorkloadInfo
+	for i, w := range group.workloads {
+		workloads = append(workloads, w.info)
+		workloadStatus := models.DetermineWorkloadStatus(w.info)
+
+		if i == 0 {
+			appType = string(w.info.ApplicationType())
+			status = string(workloadStatus)
+			oldestCreation = w.createdAt
+			if len(k8sPods) == 0 {
+				labels = w.template.Labels
+				annotations = w.template.Annotations
+				version = models.GetApplicationVersion(labels, annotations)
+			}
+			// Mid-rollout pods disagree on the version, the workload has the target one
+			if v := models.GetApplicationVersion(w.labels, nil); v != "" {
+				version = v
+			}
+		} else {
+			if appType != string(w.info.ApplicationType()) {
+				appType = string(models.TypeSystem)
+			}
+			if workloadStatus.Rank() > models.ApplicationStatus(status).Rank() {
+				status = string(workloadStatus)
+			}
+			if w.createdAt.Before(oldestCreation) {
+				oldestCreation = w.createdAt
+			}
+		}
+
+		if len(k8sPods) == 0 {
+			for _, c := range w.info.Conditions {
+				if c.LastTransitionTime.After(newestUpdate) {
+					newestUpdate = c.LastTransitionTime
+				}
+			}
+		}
+	}
+
+	// The root owner of the first workload, or of the first pod
+	var rootOwner models.ObjectReference
+	if len(group.workloads) > 0 {
+		rootOwner = group.workloads[0].root
+	} else if len(pods) > 0 {
+		rootOwner = models.ObjectReference{Kind: pods[0].RootOwnerKind, Name: pods[0].RootOwnerName}
+	}
+
+	// Systems and ArgoCD ownership are recorded on workloads, pods may not carry them
+	ownerLabels, ownerAnnotations := labels, annotations
+	if len(group.workloads) > 0 {
+		ownerLabels, ownerAnnotations = group.workloads[0].labels, group.workloads[0].annotations
+	}
+	system := a.grouper.PartOf(ownerLabels)
+	if system == "" {
+		system = a.grouper.PartOf(labels)
+	}
+	argoApplication := a.grouper.ArgoCDApplication(ownerLabels, ownerAnnotations)
+	if argoApplication == "" {
+		argoApplication = a.grouper.ArgoCDApplication(labels, annotations)
+	}
+
+	// Get the services selecting this application
+	appServices := a.getApplicationServices(key.namespace, key.name, group, services)
 
 	return models.Application{
-		Name:        key.name,
-		Namespace:   key.namespace,
-		Status:      status,
-		Type:        appType,
-		Version:     version,
-		Labels:      labels,
-		Annotations: annotations,
-		Pods:        pods,
-		Services:    services,
-		Summary:     summary,
-		CreatedAt:   oldestCreation,
-		UpdatedAt:   newestUpdate,
+		Name:          key.name,
+		Namespace:     key.namespace,
+		Cluster:       a.k8sService.ClusterName(),
+		Status:        status,
+		Type:          appType,
+		Version:       version,
+		Labels:        labels,
+		Annotations:   annotations,
+		System:        system,
+		ArgoCD:        argoApplication,
+		Workloads:     workloads,
+		RootOwnerKind: rootOwner.Kind,
+		RootOwnerName: rootOwner.Name,
+		Pods:          pods,
+		Services:      appServices,
+		Summary:       summary,
+		Resources:     resources,
+		CreatedAt:     oldestCreation,
+		UpdatedAt:     newestUpdate,
 	}
 }
 
-// getApplicationServices retrieves services associated with an application
-func (a *ApplicationService) getApplicationServices(ctx context.Context, namespace, appName string) []models.ServiceInfo {
-	// Get services in the namespace
+// serviceIndex holds the services and endpoint slices of the namespaces being listed, so
+// that they are listed once per request rather than once per application
+type serviceIndex struct {
+	services       map[string][]corev1.Service                       // by namespace
+	endpointSlices map[string]map[string][]discoveryv1.EndpointSlice // by namespace and service
+}
+
+// loadServices lists the services and endpoint slices of a namespace, or of all namespaces
+// when namespace is empty. Applications are still reported when they cannot be listed.
+func (a *ApplicationService) loadServices(ctx context.Context, namespace string) *serviceIndex {
+	logger := utils.WithComponent(a.logger, "application-service").WithField("namespace", namespace)
+	index := &serviceIndex{
+		services:       make(map[string][]corev1.Service),
+		endpointSlices: make(map[string]map[string][]discoveryv1.EndpointSlice),
+	}
+
 	serviceList, err := a.k8sService.GetServices(ctx, namespace)
 	if err != nil {
 		// Log error but don't fail the whole operation
-		utils.WithApplication(a.logger, namespace, appName).
-			WithError(err).Warn("Failed to get services for application")
-		return nil
+		logger.WithError(err).Warn("Failed to get services for applications")
+		return index
 	}
-
-	var services []models.ServiceInfo
 	for _, svc := range serviceList.Items {
-		// Check if service is related to this application
-		if a.isServiceRelatedToApplication(svc, appName) {
-			serviceInfo := a.convertServiceToModel(svc)
-			services = append(services, serviceInfo)
+		index.services[svc.Namespace] = append(index.services[svc.Namespace], svc)
+	}
+	if len(serviceList.Items) == 0 {
+		return index
+	}
+
+	// Endpoints are optional, services are still reported without them
+	sliceList, err := a.k8sService.GetEndpointSlices(ctx, namespace)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to get endpoint slices for applications")
+		return index
+	}
+	for _, slice := range sliceList.Items {
+		svcName := slice.Labels[discoveryv1.LabelServiceName]
+		if svcName == "" {
+			continue
+		}
+		byService := index.endpointSlices[slice.Namespace]
+		if byService == nil {
+			byService = make(map[string][]discoveryv1.EndpointSlice)
+			index.endpointSlices[slice.Namespace] = byService
 		}
+		byService[svcName] = append(byService[svcName], slice)
+	}
+	return index
+}
+
+// getApplicationServices returns the services associated with an application, with the
+// endpoints backing them
+func (a *ApplicationService) getApplicationServices(namespace, appName string, group *applicationGroup, index *serviceIndex) []models.ServiceInfo {
+	related := a.findApplicationServices(namespace, appName, group, index)
+	if len(related) == 0 {
+		return nil
+	}
+
+	services := make([]models.ServiceInfo, 0, len(related))
+	for _, svc := range related {
+		serviceInfo := a.convertServiceToModel(svc)
+		serviceInfo.Endpoints = models.FromK8sEndpointSlices(index.endpointSlices[namespace][svc.Name])
+		serviceInfo.ReadyEndpoints, serviceInfo.NotReadyEndpoints = models.CountEndpoints(serviceInfo.Endpoints)
+		services = append(services, serviceInfo)
 	}
 
 	return services
 }
 
-// isServiceRelatedToApplication checks if a service is related to an application
-func (a *ApplicationService) isServiceRelatedToApplication(service corev1.Service, appName string) bool {
-	// Check if service has labels that match the application
-	applicationKeys := []string{
-		"app.kubernetes.io/name",
-		"app.kubernetes.io/instance",
-		"app",
-		"application",
-		"k8s-app",
+// findApplicationServices returns the services of a namespace related to an application
+func (a *ApplicationService) findApplicationServices(namespace, appName string, group *applicationGroup, index *serviceIndex) []corev1.Service {
+	var services []corev1.Service
+	for _, svc := range index.services[namespace] {
+		if a.isServiceRelatedToApplication(svc, appName, group) {
+			services = append(services, svc)
+		}
+	}
+	return services
+}
+
+// isServiceRelatedToApplication checks if a service is related to an application: its
+// selector matches the labels of the application's pods, or of the pod templates of its
+// workloads when none are running. Services without a selector, whose endpoints are
+// managed by hand, are grouped by their labels like pods.
+func (a *ApplicationService) isServiceRelatedToApplication(service corev1.Service, appName string, group *applicationGroup) bool {
+	if len(service.Spec.Selector) == 0 {
+		return a.grouper.Name(service.Namespace, service.Labels, service.Annotations) == appName ||
+			a.grouper.System(service.Namespace, service.Labels) == appName
 	}
 
-	for _, key := range applicationKeys {
-		if value, exists := service.Labels[key]; exists && value == appName {
+	selector := labels.SelectorFromValidatedSet(service.Spec.Selector)
+	for _, pod := range group.pods {
+		if selector.Matches(labels.Set(pod.Labels)) {
 			return true
 		}
 	}
-
-	// Check if service name contains the application name
-	return service.Name == appName ||
-		service.Name == appName+"-service" ||
-		service.Name == appName+"-svc"
+	for _, w := range group.workloads {
+		if selector.Matches(labels.Set(w.template.Labels)) {
+			return true
+		}
+	}
+	return false
 }
 
 // convertServiceToModel converts a Kubernetes Service to our ServiceInfo model
@@ -359,6 +641,7 @@ func (a *ApplicationService) convertServiceToModel(svc corev1.Service) models.Se
 		ClusterIP:   svc.Spec.ClusterIP,
 		ExternalIP:  externalIPs,
 		Ports:       ports,
+		Selector:    svc.Spec.Selector,
 		Labels:      svc.Labels,
 		Annotations: svc.Annotations,
 	}
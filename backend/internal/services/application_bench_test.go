@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s-monitor/internal/config"
+)
+
+// newLargeClusterObjects returns the pods of appsPerNamespace applications of
+// podsPerApp pods in each namespace, with a service and an endpoint slice per application
+func newLargeClusterObjects(namespaces, appsPerNamespace, podsPerApp int) []runtime.Object {
+	var objects []runtime.Object
+	for n := 0; n < namespaces; n++ {
+		namespace := fmt.Sprintf("team-%d", n)
+		for a := 0; a < appsPerNamespace; a++ {
+			app := fmt.Sprintf("app-%d", a)
+			labels := map[string]string{"app": app}
+
+			slice := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      app + "-slice",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: app},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+			}
+			for p := 0; p < podsPerApp; p++ {
+				name := fmt.Sprintf("%s-7d9f8b6c5-%d", app, p)
+				objects = append(objects, testPod{
+					namespace: namespace,
+					name:      name,
+					labels:    labels,
+					ownerKind: "ReplicaSet",
+					ownerName: app + "-7d9f8b6c5",
+					ready:     true,
+				}.build())
+				slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{
+					Addresses: []string{fmt.Sprintf("10.%d.%d.%d", n, a, p)},
+					TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: name},
+				})
+			}
+
+			objects = append(objects, slice, &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: app},
+				Spec:       corev1.ServiceSpec{Selector: labels},
+			})
+		}
+	}
+	return objects
+}
+
+func BenchmarkGetApplications(b *testing.B) {
+	// 5000 pods making up 500 applications in 50 namespaces
+	k8sService := newTestKubernetesService(config.KubernetesConfig{}, newLargeClusterObjects(50, 10, 10))
+	service := NewApplicationService(k8sService, nil, newTestLogger())
+	clientset := k8sService.GetClientset().(*fake.Clientset)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := service.GetApplications(context.Background()); err != nil {
+			b.Fatalf("GetApplications() error = %v", err)
+		}
+	}
+	b.StopTimer()
+
+	// API calls are what grows with the cluster, report them next to the time
+	total := 0
+	for _, count := range countLists(clientset) {
+		total += count
+	}
+	b.ReportMetric(float64(total)/float64(b.N), "lists/op")
+}
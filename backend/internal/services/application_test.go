@@ -0,0 +1,575 @@
+package services
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+)
+
+// testPod describes a pod fixture
+type testPod struct {
+	namespace string
+	name      string
+	labels    map[string]string
+	ownerKind string
+	ownerName string
+	phase     corev1.PodPhase
+	ready     bool
+	restarts  int32
+}
+
+// testOwnerAPIVersions are the API versions of the owner kinds used by fixtures
+var testOwnerAPIVersions = map[string]string{
+	"ReplicaSet":  "apps/v1",
+	"Deployment":  "apps/v1",
+	"StatefulSet": "apps/v1",
+	"DaemonSet":   "apps/v1",
+	"Job":         "batch/v1",
+	"CronJob":     "batch/v1",
+	"Rollout":     "argoproj.io/v1alpha1",
+	"Node":        "v1",
+}
+
+func (p testPod) build() *corev1.Pod {
+	phase := p.phase
+	if phase == "" {
+		phase = corev1.PodRunning
+	}
+	readyStatus := corev1.ConditionFalse
+	if p.ready {
+		readyStatus = corev1.ConditionTrue
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: p.namespace,
+			Name:      p.name,
+			Labels:    p.labels,
+		},
+		Status: corev1.PodStatus{
+			Phase: phase,
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: readyStatus},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "main", Ready: p.ready, RestartCount: p.restarts},
+			},
+		},
+	}
+	if p.ownerKind != "" {
+		pod.OwnerReferences = []metav1.OwnerReference{{APIVersion: testOwnerAPIVersions[p.ownerKind], Kind: p.ownerKind, Name: p.ownerName}}
+	}
+	return pod
+}
+
+func newTestLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// testListKinds registers the dynamic resources listed by the services
+var testListKinds = map[schema.GroupVersionResource]string{
+	argoApplicationGVR: "ApplicationList",
+	podMetricsGVR:      "PodMetricsList",
+	nodeMetricsGVR:     "NodeMetricsList",
+}
+
+// testDiscoveryResources are the resources served to the REST mapper
+var testDiscoveryResources = []*metav1.APIResourceList{
+	{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{{Name: "nodes", Kind: "Node"}},
+	},
+	{
+		GroupVersion: "apps/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "deployments", Kind: "Deployment", Namespaced: true},
+			{Name: "statefulsets", Kind: "StatefulSet", Namespaced: true},
+			{Name: "daemonsets", Kind: "DaemonSet", Namespaced: true},
+			{Name: "replicasets", Kind: "ReplicaSet", Namespaced: true},
+		},
+	},
+	{
+		GroupVersion: "batch/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "jobs", Kind: "Job", Namespaced: true},
+			{Name: "cronjobs", Kind: "CronJob", Namespaced: true},
+		},
+	},
+	{
+		GroupVersion: "argoproj.io/v1alpha1",
+		APIResources: []metav1.APIResource{{Name: "rollouts", Kind: "Rollout", Namespaced: true}},
+	},
+}
+
+// newTestKubernetesService creates a service backed by fake clientsets
+func newTestKubernetesService(cfg config.KubernetesConfig, objects []runtime.Object, argoApps ...*unstructured.Unstructured) *KubernetesService {
+	clientset := fake.NewSimpleClientset(objects...)
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = testDiscoveryResources
+
+	dynamicObjects := make([]runtime.Object, 0, len(argoApps))
+	for _, app := range argoApps {
+		dynamicObjects = append(dynamicObjects, app)
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), testListKinds, dynamicObjects...)
+
+	return NewKubernetesServiceForClients("test", clientset, dynamicClient, cfg, newTestLogger())
+}
+
+func TestExtractApplicationName(t *testing.T) {
+	service := NewApplicationService(nil, nil, newTestLogger())
+
+	tests := []struct {
+		name string
+		pod  testPod
+		want string
+	}{
+		{
+			name: "recommended name label",
+			pod:  testPod{name: "web-1", labels: map[string]string{"app.kubernetes.io/name": "web", "app": "other"}},
+			want: "web",
+		},
+		{
+			name: "instance label",
+			pod:  testPod{name: "web-1", labels: map[string]string{"app.kubernetes.io/instance": "web-prod"}},
+			want: "web-prod",
+		},
+		{
+			name: "app label",
+			pod:  testPod{name: "web-1", labels: map[string]string{"app": "web"}},
+			want: "web",
+		},
+		{
+			name: "k8s-app label",
+			pod:  testPod{name: "dns-1", labels: map[string]string{"k8s-app": "kube-dns"}},
+			want: "kube-dns",
+		},
+		{
+			name: "empty label is ignored",
+			pod:  testPod{name: "web-1", labels: map[string]string{"app": "", "application": "web"}},
+			want: "web",
+		},
+		{
+			name: "replicaset owner strips hash",
+			pod:  testPod{name: "api-7d9f8-abcde", ownerKind: "ReplicaSet", ownerName: "api-7d9f8"},
+			want: "api",
+		},
+		{
+			name: "statefulset owner",
+			pod:  testPod{name: "db-0", ownerKind: "StatefulSet", ownerName: "db"},
+			want: "db",
+		},
+		{
+			name: "pod name prefix",
+			pod:  testPod{name: "standalone-pod"},
+			want: "standalone",
+		},
+		{
+			name: "bare pod name",
+			pod:  testPod{name: "debug"},
+			want: "debug",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := service.extractApplicationName(*tt.pod.build(), nil); got != tt.want {
+				t.Errorf("extractApplicationName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupPodsByApplication(t *testing.T) {
+	service := NewApplicationService(nil, nil, newTestLogger())
+
+	pods := []corev1.Pod{
+		*testPod{namespace: "shop", name: "api-1", labels: map[string]string{"app": "api"}}.build(),
+		*testPod{namespace: "shop", name: "api-2", labels: map[string]string{"app": "api"}}.build(),
+		*testPod{namespace: "staging", name: "api-1", labels: map[string]string{"app": "api"}}.build(),
+		*testPod{namespace: "shop", name: "web-1", labels: map[string]string{"app": "web"}}.build(),
+	}
+
+	groups := service.groupPodsByApplication(pods, nil)
+
+	want := map[applicationKey]int{
+		{namespace: "shop", name: "api"}:    2,
+		{namespace: "staging", name: "api"}: 1,
+		{namespace: "shop", name: "web"}:    1,
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("got %d groups, want %d", len(groups), len(want))
+	}
+	for key, count := range want {
+		if got := len(groups[key]); got != count {
+			t.Errorf("group %v has %d pods, want %d", key, got, count)
+		}
+	}
+}
+
+func TestIsServiceRelatedToApplication(t *testing.T) {
+	service := NewApplicationService(nil, nil, newTestLogger())
+
+	podLabels := map[string]string{"app.kubernetes.io/name": "web", "tier": "frontend"}
+	running := &applicationGroup{pods: []corev1.Pod{*testPod{namespace: "shop", name: "web-1", labels: podLabels}.build()}}
+	scaledDown := &applicationGroup{workloads: []*workload{{template: metav1.ObjectMeta{Labels: podLabels}}}}
+
+	tests := []struct {
+		name     string
+		svcName  string
+		selector map[string]string
+		labels   map[string]string
+		group    *applicationGroup
+		want     bool
+	}{
+		{name: "selector matches pods", svcName: "frontend", selector: map[string]string{"tier": "frontend"}, group: running, want: true},
+		{name: "selector matches pod template", svcName: "frontend", selector: map[string]string{"tier": "frontend"}, group: scaledDown, want: true},
+		{name: "selector matches nothing", svcName: "web", selector: map[string]string{"tier": "backend"}, labels: map[string]string{"app": "web"}, group: running, want: false},
+		{name: "label match without selector", svcName: "external", labels: map[string]string{"app": "web"}, group: running, want: true},
+		{name: "name alone is not enough", svcName: "web-svc", group: running, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: tt.svcName, Labels: tt.labels},
+				Spec:       corev1.ServiceSpec{Selector: tt.selector},
+			}
+			if got := service.isServiceRelatedToApplication(svc, "web", tt.group); got != tt.want {
+				t.Errorf("isServiceRelatedToApplication() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetApplications(t *testing.T) {
+	cfg := config.KubernetesConfig{}
+	cfg.Namespaces.Exclude = []string{"kube-system"}
+
+	objects := []runtime.Object{
+		testPod{namespace: "shop", name: "api-1", labels: map[string]string{"app": "api"}, ownerKind: "ReplicaSet", ownerName: "api-5f6d", ready: true, restarts: 1}.build(),
+		testPod{namespace: "shop", name: "api-2", labels: map[string]string{"app": "api"}, ownerKind: "ReplicaSet", ownerName: "api-5f6d", ready: true}.build(),
+		testPod{namespace: "shop", name: "db-0", labels: map[string]string{"app": "db"}, ownerKind: "StatefulSet", ownerName: "db", phase: corev1.PodFailed}.build(),
+		testPod{namespace: "shop", name: "worker-1", labels: map[string]string{"app": "worker"}, phase: corev1.PodPending}.build(),
+		testPod{namespace: "kube-system", name: "coredns-1", labels: map[string]string{"k8s-app": "kube-dns"}, ready: true}.build(),
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "api-svc"},
+			Spec: corev1.ServiceSpec{
+				Selector:  map[string]string{"app": "api"},
+				Type:      corev1.ServiceTypeClusterIP,
+				ClusterIP: "10.0.0.10",
+				Ports:     []corev1.ServicePort{{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP}},
+			},
+		},
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "shop",
+				Name:      "api-svc-x8f2k",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "api-svc"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.1.0.4"}, TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "api-1"}},
+				{Addresses: []string{"10.1.0.5"}, Conditions: discoveryv1.EndpointConditions{Ready: boolPtr(false)}, TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "api-2"}},
+			},
+		},
+	}
+
+	k8sService := newTestKubernetesService(cfg, objects)
+	service := NewApplicationService(k8sService, nil, newTestLogger())
+
+	response, err := service.GetApplications(context.Background())
+	if err != nil {
+		t.Fatalf("GetApplications() error = %v", err)
+	}
+
+	if response.Total != 3 {
+		t.Fatalf("Total = %d, want 3 (kube-system must be excluded)", response.Total)
+	}
+
+	wantSummary := models.ApplicationsSummary{
+		Healthy:     1,
+		Degraded:    1,
+		Unhealthy:   1,
+		TotalPods:   4,
+		ReadyPods:   2,
+		RunningPods: 2,
+	}
+	if response.Summary != wantSummary {
+		t.Errorf("Summary = %+v, want %+v", response.Summary, wantSummary)
+	}
+
+	want := []struct {
+		name    string
+		status  models.ApplicationStatus
+		appType models.ApplicationType
+		pods    int
+	}{
+		{name: "api", status: models.StatusHealthy, appType: models.TypeDeployment, pods: 2},
+		{name: "db", status: models.StatusUnhealthy, appType: models.TypeStatefulSet, pods: 1},
+		{name: "worker", status: models.StatusDegraded, appType: models.TypeStandalone, pods: 1},
+	}
+	for i, w := range want {
+		app := response.Applications[i]
+		if app.Name != w.name || app.Namespace != "shop" || app.Cluster != "test" {
+			t.Errorf("applications[%d] = %s/%s@%s, want shop/%s@test", i, app.Namespace, app.Name, app.Cluster, w.name)
+			continue
+		}
+		if app.Status != string(w.status) {
+			t.Errorf("%s status = %q, want %q", w.name, app.Status, w.status)
+		}
+		if app.Type != string(w.appType) {
+			t.Errorf("%s type = %q, want %q", w.name, app.Type, w.appType)
+		}
+		if len(app.Pods) != w.pods {
+			t.Errorf("%s has %d pods, want %d", w.name, len(app.Pods), w.pods)
+		}
+	}
+
+	api := response.Applications[0]
+	if len(api.Services) != 1 || api.Services[0].Name != "api-svc" || api.Services[0].ClusterIP != "10.0.0.10" {
+		t.Errorf("api services = %+v, want api-svc", api.Services)
+	} else if svc := api.Services[0]; svc.ReadyEndpoints != 1 || svc.NotReadyEndpoints != 1 || svc.Endpoints[0].PodName != "api-1" {
+		t.Errorf("api-svc endpoints = %+v, want api-1 ready and api-2 not ready", svc.Endpoints)
+	}
+	if api.Summary.RestartCount != 1 {
+		t.Errorf("api restart count = %d, want 1", api.Summary.RestartCount)
+	}
+
+	// The watcher's observation builds the same applications without reading metrics
+	metricsReads := 0
+	k8sService.dynamicClient.(*dynamicfake.FakeDynamicClient).PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		metricsReads++
+		return false, nil, nil
+	})
+	observed, err := service.ObserveApplications(context.Background())
+	if err != nil {
+		t.Fatalf("ObserveApplications() error = %v", err)
+	}
+	if len(observed) != len(response.Applications) {
+		t.Fatalf("ObserveApplications() returned %d applications, want %d", len(observed), len(response.Applications))
+	}
+	for i := range observed {
+		if observed[i].Name != response.Applications[i].Name || observed[i].Status != response.Applications[i].Status {
+			t.Errorf("observed[%d] = %s %s, want %s %s", i, observed[i].Name, observed[i].Status,
+				response.Applications[i].Name, response.Applications[i].Status)
+		}
+	}
+	if metricsReads != 0 {
+		t.Errorf("ObserveApplications() read pod metrics %d times", metricsReads)
+	}
+}
+
+func TestGetApplicationsByNamespace(t *testing.T) {
+	cfg := config.KubernetesConfig{}
+	cfg.Namespaces.Allowed = []string{"shop"}
+
+	objects := []runtime.Object{
+		testPod{namespace: "shop", name: "api-1", labels: map[string]string{"app": "api"}, ready: true}.build(),
+		testPod{namespace: "other", name: "api-1", labels: map[string]string{"app": "api"}, ready: true}.build(),
+	}
+	service := NewApplicationService(newTestKubernetesService(cfg, objects), nil, newTestLogger())
+
+	response, err := service.GetApplicationsByNamespace(context.Background(), "shop")
+	if err != nil {
+		t.Fatalf("GetApplicationsByNamespace() error = %v", err)
+	}
+	if response.Total != 1 || response.Namespace != "shop" {
+		t.Errorf("got %d applications in %q, want 1 in shop", response.Total, response.Namespace)
+	}
+
+	if _, err := service.GetApplicationsByNamespace(context.Background(), "other"); err == nil {
+		t.Error("expected an error for a namespace outside the allowed list")
+	}
+}
+
+// countLists counts the list calls made through a fake clientset, by resource
+func countLists(clientset *fake.Clientset) map[string]int {
+	lists := make(map[string]int)
+	for _, action := range clientset.Actions() {
+		if action.GetVerb() == "list" {
+			lists[action.GetResource().Resource]++
+		}
+	}
+	return lists
+}
+
+func TestGetApplicationsListsOnce(t *testing.T) {
+	k8sService := newTestKubernetesService(config.KubernetesConfig{}, newLargeClusterObjects(3, 4, 2))
+	service := NewApplicationService(k8sService, nil, newTestLogger())
+
+	response, err := service.GetApplications(context.Background())
+	if err != nil {
+		t.Fatalf("GetApplications() error = %v", err)
+	}
+	if response.Total != 12 {
+		t.Fatalf("got %d applications, want 12", response.Total)
+	}
+	for i, app := range response.Applications {
+		if len(app.Services) != 1 || app.Services[0].ReadyEndpoints != 2 {
+			t.Errorf("applications[%d] %s/%s has services %+v, want one with 2 ready endpoints", i, app.Namespace, app.Name, app.Services)
+		}
+		if i > 0 && response.Applications[i-1].Namespace == app.Namespace && response.Applications[i-1].Name > app.Name {
+			t.Errorf("applications not sorted at %d: %s after %s", i, app.Name, response.Applications[i-1].Name)
+		}
+	}
+
+	lists := countLists(k8sService.GetClientset().(*fake.Clientset))
+	for _, resource := range []string{"pods", "services", "endpointslices", "deployments", "statefulsets", "daemonsets"} {
+		if lists[resource] != 1 {
+			t.Errorf("listed %s %d times, want once", resource, lists[resource])
+		}
+	}
+}
+
+func int32Ptr(n int32) *int32 { return &n }
+
+func boolPtr(b bool) *bool { return &b }
+
+func newTestDeployment(namespace, name string, replicas, available int32, podLabels map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: map[string]string{"app.kubernetes.io/version": "1.4.0"}},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: int32Ptr(replicas),
+			Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: podLabels}},
+		},
+		Status: appsv1.DeploymentStatus{
+			Replicas:          replicas,
+			UpdatedReplicas:   replicas,
+			ReadyReplicas:     available,
+			AvailableReplicas: available,
+		},
+	}
+}
+
+func TestGetApplicationsFromWorkloads(t *testing.T) {
+	hashed := func(app, hash string) map[string]string {
+		return map[string]string{"app": app, appsv1.DefaultDeploymentUniqueLabelKey: hash}
+	}
+
+	objects := []runtime.Object{
+		// Scaled to zero: no pods, still listed
+		newTestDeployment("shop", "checkout-api", 0, 0, map[string]string{"app": "checkout"}),
+		// Every pod is crash looping
+		newTestDeployment("shop", "payments-api", 2, 0, map[string]string{"app": "payments"}),
+		testPod{namespace: "shop", name: "payments-api-6c9f-a", labels: hashed("payments", "6c9f"), ownerKind: "ReplicaSet", ownerName: "payments-api-6c9f", restarts: 7}.build(),
+		testPod{namespace: "shop", name: "payments-api-6c9f-b", labels: hashed("payments", "6c9f"), ownerKind: "ReplicaSet", ownerName: "payments-api-6c9f", restarts: 5}.build(),
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "db"},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: int32Ptr(1),
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			},
+			Status: appsv1.StatefulSetStatus{Replicas: 1, ReadyReplicas: 1, AvailableReplicas: 1, UpdatedReplicas: 1},
+		},
+		testPod{namespace: "shop", name: "db-0", labels: map[string]string{"app": "db"}, ownerKind: "StatefulSet", ownerName: "db", ready: true}.build(),
+		// A bare pod is still discovered from its labels
+		testPod{namespace: "shop", name: "debug-shell", labels: map[string]string{"app": "toolbox"}, ready: true}.build(),
+	}
+
+	service := NewApplicationService(newTestKubernetesService(config.KubernetesConfig{}, objects), nil, newTestLogger())
+
+	response, err := service.GetApplicationsByNamespace(context.Background(), "shop")
+	if err != nil {
+		t.Fatalf("GetApplicationsByNamespace() error = %v", err)
+	}
+
+	want := []struct {
+		name     string
+		status   models.ApplicationStatus
+		appType  models.ApplicationType
+		pods     int
+		workload string
+	}{
+		{name: "checkout-api", status: models.StatusHealthy, appType: models.TypeDeployment, workload: "Deployment"},
+		{name: "db", status: models.StatusHealthy, appType: models.TypeStatefulSet, pods: 1, workload: "StatefulSet"},
+		{name: "payments-api", status: models.StatusUnhealthy, appType: models.TypeDeployment, pods: 2, workload: "Deployment"},
+		{name: "toolbox", status: models.StatusHealthy, appType: models.TypeStandalone, pods: 1},
+	}
+	if len(response.Applications) != len(want) {
+		t.Fatalf("got %d applications, want %d", len(response.Applications), len(want))
+	}
+	for i, w := range want {
+		app := response.Applications[i]
+		if app.Name != w.name || app.Status != string(w.status) || app.Type != string(w.appType) || len(app.Pods) != w.pods {
+			t.Errorf("applications[%d] = %s (%s, %s, %d pods), want %s (%s, %s, %d pods)",
+				i, app.Name, app.Status, app.Type, len(app.Pods), w.name, w.status, w.appType, w.pods)
+		}
+		if (len(app.Workloads) == 0) != (w.workload == "") || (len(app.Workloads) > 0 && app.Workloads[0].Kind != w.workload) {
+			t.Errorf("%s workloads = %+v, want kind %q", w.name, app.Workloads, w.workload)
+		}
+	}
+
+	payments := response.Applications[2].Workloads[0]
+	if payments.DesiredReplicas != 2 || payments.Selector != "app=payments" || response.Applications[2].Version != "1.4.0" {
+		t.Errorf("unexpected payments workload %+v", payments)
+	}
+
+	pods, err := service.GetApplicationPods(context.Background(), "shop", "checkout-api")
+	if err != nil || len(pods) != 0 {
+		t.Errorf("GetApplicationPods(checkout-api) = %d pods, %v, want none without error", len(pods), err)
+	}
+}
+
+func TestWorkloadOwns(t *testing.T) {
+	deployment := newTestDeployment("shop", "api", 1, 1, map[string]string{"app": "api"})
+	w := newWorkload(models.FromK8sDeployment(deployment), deployment.ObjectMeta, deployment.Spec.Template, deployment.Spec.Selector)
+
+	tests := []struct {
+		name string
+		pod  testPod
+		want bool
+	}{
+		{
+			name: "replica set with template hash",
+			pod:  testPod{namespace: "shop", labels: map[string]string{"app": "api", "pod-template-hash": "5f6d"}, ownerKind: "ReplicaSet", ownerName: "api-5f6d"},
+			want: true,
+		},
+		{
+			name: "dashed deployment name sharing the prefix",
+			pod:  testPod{namespace: "shop", labels: map[string]string{"app": "api-gateway", "pod-template-hash": "77c4"}, ownerKind: "ReplicaSet", ownerName: "api-gateway-77c4"},
+			want: false,
+		},
+		{
+			name: "replica set without hash label matches the selector",
+			pod:  testPod{namespace: "shop", labels: map[string]string{"app": "api"}, ownerKind: "ReplicaSet", ownerName: "api-5f6d"},
+			want: true,
+		},
+		{
+			name: "other namespace",
+			pod:  testPod{namespace: "staging", labels: map[string]string{"app": "api", "pod-template-hash": "5f6d"}, ownerKind: "ReplicaSet", ownerName: "api-5f6d"},
+			want: false,
+		},
+		{
+			name: "bare pod",
+			pod:  testPod{namespace: "shop", labels: map[string]string{"app": "api"}},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := w.owns(*tt.pod.build()); got != tt.want {
+				t.Errorf("owns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
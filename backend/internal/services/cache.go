@@ -0,0 +1,526 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	discoveryv1listers "k8s.io/client-go/listers/discovery/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Cached resource names used in cache status reporting
+const (
+	cacheResourcePods       = "pods"
+	cacheResourceServices   = "services"
+	cacheResourceNamespaces = "namespaces"
+	cacheResourceArgoApps   = "argocd-applications"
+
+	cacheResourceDeployments  = "deployments"
+	cacheResourceStatefulSets = "statefulsets"
+	cacheResourceDaemonSets   = "daemonsets"
+
+	cacheResourceReplicaSets = "replicasets"
+	cacheResourceJobs        = "jobs"
+
+	cacheResourceEndpointSlices = "endpointslices"
+)
+
+// resourceCache keeps shared informers for the resources served by the API so
+// that handlers read from a local indexed store instead of listing on every request
+type resourceCache struct {
+	factory        informers.SharedInformerFactory
+	dynamicFactory dynamicinformer.DynamicSharedInformerFactory
+
+	podLister       corev1listers.PodLister
+	serviceLister   corev1listers.ServiceLister
+	namespaceLister corev1listers.NamespaceLister
+	argoLister      cache.GenericLister
+
+	deploymentLister  appsv1listers.DeploymentLister
+	statefulSetLister appsv1listers.StatefulSetLister
+	daemonSetLister   appsv1listers.DaemonSetLister
+
+	// Pod owners, read when resolving owner chains
+	replicaSetLister appsv1listers.ReplicaSetLister
+	jobLister        batchv1listers.JobLister
+
+	endpointSliceLister discoveryv1listers.EndpointSliceLister
+
+	informers map[string]cache.SharedIndexInformer
+	health    map[string]*watchHealth
+	stopCh    chan struct{}
+	logger    *logrus.Entry
+}
+
+// newResourceCache creates informers for pods, services, endpoint slices, namespaces,
+// workloads, pod owners and, when the CRD is installed, ArgoCD Applications. Informers are not started until start is called.
+func newResourceCache(clientset kubernetes.Interface, dynamicClient dynamic.Interface, resync time.Duration, argoInstalled bool, logger *logrus.Entry) *resourceCache {
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, resync,
+		informers.WithTransform(stripManagedFields))
+
+	rc := &resourceCache{
+		factory:         factory,
+		podLister:       factory.Core().V1().Pods().Lister(),
+		serviceLister:   factory.Core().V1().Services().Lister(),
+		namespaceLister: factory.Core().V1().Namespaces().Lister(),
+
+		deploymentLister:  factory.Apps().V1().Deployments().Lister(),
+		statefulSetLister: factory.Apps().V1().StatefulSets().Lister(),
+		daemonSetLister:   factory.Apps().V1().DaemonSets().Lister(),
+
+		replicaSetLister: factory.Apps().V1().ReplicaSets().Lister(),
+		jobLister:        factory.Batch().V1().Jobs().Lister(),
+
+		endpointSliceLister: factory.Discovery().V1().EndpointSlices().Lister(),
+
+		informers: map[string]cache.SharedIndexInformer{
+			cacheResourcePods:       factory.Core().V1().Pods().Informer(),
+			cacheResourceServices:   factory.Core().V1().Services().Informer(),
+			cacheResourceNamespaces: factory.Core().V1().Namespaces().Informer(),
+
+			cacheResourceDeployments:  factory.Apps().V1().Deployments().Informer(),
+			cacheResourceStatefulSets: factory.Apps().V1().StatefulSets().Informer(),
+			cacheResourceDaemonSets:   factory.Apps().V1().DaemonSets().Informer(),
+
+			cacheResourceReplicaSets: factory.Apps().V1().ReplicaSets().Informer(),
+			cacheResourceJobs:        factory.Batch().V1().Jobs().Informer(),
+
+			cacheResourceEndpointSlices: factory.Discovery().V1().EndpointSlices().Informer(),
+		},
+		stopCh: make(chan struct{}),
+		logger: logger.WithField("component", "kubernetes-cache"),
+	}
+
+	if argoInstalled {
+		rc.dynamicFactory = dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, resync)
+		argoInformer := rc.dynamicFactory.ForResource(argoApplicationGVR)
+		rc.argoLister = argoInformer.Lister()
+		rc.informers[cacheResourceArgoApps] = argoInformer.Informer()
+	}
+
+	rc.health = make(map[string]*watchHealth, len(rc.informers))
+	for resource, informer := range rc.informers {
+		health := &watchHealth{informer: informer}
+		rc.health[resource] = health
+		_ = informer.SetWatchErrorHandlerWithContext(func(ctx context.Context, r *cache.Reflector, err error) {
+			if health.failed(err) {
+				rc.logger.WithError(err).WithField("resource", resource).Warn("Informer watch failing, cached reads are stale")
+			}
+			cache.DefaultWatchErrorHandler(ctx, r, err)
+		})
+	}
+
+	return rc
+}
+
+// watchHealth tracks whether the watch of an informer is failing. A watch is failing from
+// its first error until the informer syncs a newer resource version, which happens once
+// a list or watch succeeds again.
+type watchHealth struct {
+	informer cache.SharedIndexInformer
+
+	mu            sync.Mutex
+	failedAt      time.Time // zero while the watch is healthy
+	failedVersion string    // resource version synced when the watch failed
+}
+
+// failed records a watch error, and reports whether the watch was healthy until then.
+// Watches closed normally or expired are restarted at once and are not failures.
+func (h *watchHealth) failed(err error) bool {
+	if errors.Is(err, io.EOF) || apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+		return false
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	version := h.informer.LastSyncResourceVersion()
+	if !h.failedAt.IsZero() && version == h.failedVersion {
+		return false
+	}
+	h.failedAt = time.Now()
+	h.failedVersion = version
+	return true
+}
+
+// staleSince returns when the watch started failing, or false when it is healthy
+func (h *watchHealth) staleSince() (time.Time, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.failedAt.IsZero() {
+		return time.Time{}, false
+	}
+	if h.informer.LastSyncResourceVersion() != h.failedVersion {
+		h.failedAt = time.Time{}
+		return time.Time{}, false
+	}
+	return h.failedAt, true
+}
+
+// start runs all informers and waits up to timeout for the initial sync.
+// Reads fall back to the API server for resources that have not synced yet.
+func (rc *resourceCache) start(timeout time.Duration) {
+	rc.factory.Start(rc.stopCh)
+	if rc.dynamicFactory != nil {
+		rc.dynamicFactory.Start(rc.stopCh)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	syncFuncs := make([]cache.InformerSynced, 0, len(rc.informers))
+	for _, informer := range rc.informers {
+		syncFuncs = append(syncFuncs, informer.HasSynced)
+	}
+
+	if cache.WaitForCacheSync(ctx.Done(), syncFuncs...) {
+		rc.logger.Info("Informer caches synced")
+		return
+	}
+	rc.logger.WithField("pending", rc.pending()).Warn("Informer caches not synced yet, serving reads from the API server")
+}
+
+// stop shuts down all informers
+func (rc *resourceCache) stop() {
+	close(rc.stopCh)
+	rc.factory.Shutdown()
+	if rc.dynamicFactory != nil {
+		rc.dynamicFactory.Shutdown()
+	}
+}
+
+// hasSynced reports whether the informer for the given resource has synced
+func (rc *resourceCache) hasSynced(resource string) bool {
+	informer, ok := rc.informers[resource]
+	return ok && informer.HasSynced()
+}
+
+// staleSince reports whether the watch of the given resource is failing, and since when.
+// Cached objects are only known to be current up to that time.
+func (rc *resourceCache) staleSince(resource string) (time.Time, bool) {
+	health, ok := rc.health[resource]
+	if !ok {
+		return time.Time{}, false
+	}
+	return health.staleSince()
+}
+
+// status returns the sync state of every cached resource
+func (rc *resourceCache) status() map[string]bool {
+	status := make(map[string]bool, len(rc.informers))
+	for resource, informer := range rc.informers {
+		status[resource] = informer.HasSynced()
+	}
+	return status
+}
+
+// pending returns the sorted names of resources that have not synced yet
+func (rc *resourceCache) pending() []string {
+	var pending []string
+	for resource, informer := range rc.informers {
+		if !informer.HasSynced() {
+			pending = append(pending, resource)
+		}
+	}
+	sort.Strings(pending)
+	return pending
+}
+
+// listPods returns cached pods for a namespace, or all namespaces when namespace is empty,
+// matching the label and field selectors
+func (rc *resourceCache) listPods(namespace string, labelSelector labels.Selector, fieldSelector fields.Selector) (*corev1.PodList, error) {
+	var pods []*corev1.Pod
+	var err error
+	if namespace == "" {
+		pods, err = rc.podLister.List(labelSelector)
+	} else {
+		pods, err = rc.podLister.Pods(namespace).List(labelSelector)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := &corev1.PodList{Items: make([]corev1.Pod, 0, len(pods))}
+	for _, pod := range pods {
+		if fieldSelector.Empty() || fieldSelector.Matches(podFields(pod)) {
+			list.Items = append(list.Items, *pod.DeepCopy())
+		}
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		return lessNamespaced(list.Items[i].Namespace, list.Items[i].Name, list.Items[j].Namespace, list.Items[j].Name)
+	})
+	return list, nil
+}
+
+// getPod returns a cached pod
+func (rc *resourceCache) getPod(namespace, name string) (*corev1.Pod, error) {
+	pod, err := rc.podLister.Pods(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return pod.DeepCopy(), nil
+}
+
+// listServices returns cached services for a namespace, or for all namespaces when
+// namespace is empty
+func (rc *resourceCache) listServices(namespace string) (*corev1.ServiceList, error) {
+	services, err := rc.serviceLister.Services(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ServiceList{Items: copySorted(services)}, nil
+}
+
+// listEndpointSlices returns cached endpoint slices for a namespace
+func (rc *resourceCache) listEndpointSlices(namespace string) (*discoveryv1.EndpointSliceList, error) {
+	slices, err := rc.endpointSliceLister.EndpointSlices(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	return &discoveryv1.EndpointSliceList{Items: copySorted(slices)}, nil
+}
+
+// listNamespaces returns all cached namespaces
+func (rc *resourceCache) listNamespaces() (*corev1.NamespaceList, error) {
+	namespaces, err := rc.namespaceLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	list := &corev1.NamespaceList{Items: make([]corev1.Namespace, 0, len(namespaces))}
+	for _, ns := range namespaces {
+		list.Items = append(list.Items, *ns.DeepCopy())
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].Name < list.Items[j].Name
+	})
+	return list, nil
+}
+
+// listDeployments returns cached deployments for a namespace, or all namespaces when empty
+func (rc *resourceCache) listDeployments(namespace string) (*appsv1.DeploymentList, error) {
+	var deployments []*appsv1.Deployment
+	var err error
+	if namespace == "" {
+		deployments, err = rc.deploymentLister.List(labels.Everything())
+	} else {
+		deployments, err = rc.deploymentLister.Deployments(namespace).List(labels.Everything())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &appsv1.DeploymentList{Items: copySorted(deployments)}, nil
+}
+
+// listStatefulSets returns cached stateful sets for a namespace, or all namespaces when empty
+func (rc *resourceCache) listStatefulSets(namespace string) (*appsv1.StatefulSetList, error) {
+	var statefulSets []*appsv1.StatefulSet
+	var err error
+	if namespace == "" {
+		statefulSets, err = rc.statefulSetLister.List(labels.Everything())
+	} else {
+		statefulSets, err = rc.statefulSetLister.StatefulSets(namespace).List(labels.Everything())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &appsv1.StatefulSetList{Items: copySorted(statefulSets)}, nil
+}
+
+// listDaemonSets returns cached daemon sets for a namespace, or all namespaces when empty
+func (rc *resourceCache) listDaemonSets(namespace string) (*appsv1.DaemonSetList, error) {
+	var daemonSets []*appsv1.DaemonSet
+	var err error
+	if namespace == "" {
+		daemonSets, err = rc.daemonSetLister.List(labels.Everything())
+	} else {
+		daemonSets, err = rc.daemonSetLister.DaemonSets(namespace).List(labels.Everything())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &appsv1.DaemonSetList{Items: copySorted(daemonSets)}, nil
+}
+
+// listReplicaSets returns cached replica sets for a namespace, or all namespaces when empty
+func (rc *resourceCache) listReplicaSets(namespace string) (*appsv1.ReplicaSetList, error) {
+	var replicaSets []*appsv1.ReplicaSet
+	var err error
+	if namespace == "" {
+		replicaSets, err = rc.replicaSetLister.List(labels.Everything())
+	} else {
+		replicaSets, err = rc.replicaSetLister.ReplicaSets(namespace).List(labels.Everything())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &appsv1.ReplicaSetList{Items: copySorted(replicaSets)}, nil
+}
+
+// listJobs returns cached jobs for a namespace, or all namespaces when empty
+func (rc *resourceCache) listJobs(namespace string) (*batchv1.JobList, error) {
+	var jobs []*batchv1.Job
+	var err error
+	if namespace == "" {
+		jobs, err = rc.jobLister.List(labels.Everything())
+	} else {
+		jobs, err = rc.jobLister.Jobs(namespace).List(labels.Everything())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &batchv1.JobList{Items: copySorted(jobs)}, nil
+}
+
+// getDeployment returns a cached deployment
+func (rc *resourceCache) getDeployment(namespace, name string) (*appsv1.Deployment, error) {
+	deployment, err := rc.deploymentLister.Deployments(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return deployment.DeepCopy(), nil
+}
+
+// getStatefulSet returns a cached stateful set
+func (rc *resourceCache) getStatefulSet(namespace, name string) (*appsv1.StatefulSet, error) {
+	statefulSet, err := rc.statefulSetLister.StatefulSets(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return statefulSet.DeepCopy(), nil
+}
+
+// getDaemonSet returns a cached daemon set
+func (rc *resourceCache) getDaemonSet(namespace, name string) (*appsv1.DaemonSet, error) {
+	daemonSet, err := rc.daemonSetLister.DaemonSets(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return daemonSet.DeepCopy(), nil
+}
+
+// getReplicaSet returns a cached replica set
+func (rc *resourceCache) getReplicaSet(namespace, name string) (*appsv1.ReplicaSet, error) {
+	replicaSet, err := rc.replicaSetLister.ReplicaSets(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return replicaSet.DeepCopy(), nil
+}
+
+// getJob returns a cached job
+func (rc *resourceCache) getJob(namespace, name string) (*batchv1.Job, error) {
+	job, err := rc.jobLister.Jobs(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return job.DeepCopy(), nil
+}
+
+// listArgoApplications returns cached ArgoCD Applications for a namespace, or all namespaces when empty
+func (rc *resourceCache) listArgoApplications(namespace string) (*unstructured.UnstructuredList, error) {
+	var objects []interface{}
+	var err error
+	if namespace == "" {
+		objects, err = listRuntimeObjects(rc.argoLister.List(labels.Everything()))
+	} else {
+		objects, err = listRuntimeObjects(rc.argoLister.ByNamespace(namespace).List(labels.Everything()))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := &unstructured.UnstructuredList{}
+	for _, obj := range objects {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object type %T in ArgoCD application cache", obj)
+		}
+		list.Items = append(list.Items, *u.DeepCopy())
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		return lessNamespaced(list.Items[i].GetNamespace(), list.Items[i].GetName(), list.Items[j].GetNamespace(), list.Items[j].GetName())
+	})
+	return list, nil
+}
+
+// getArgoApplication returns a cached ArgoCD Application
+func (rc *resourceCache) getArgoApplication(namespace, name string) (*unstructured.Unstructured, error) {
+	obj, err := rc.argoLister.ByNamespace(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object type %T in ArgoCD application cache", obj)
+	}
+	return u.DeepCopy(), nil
+}
+
+// stripManagedFields drops managed fields before objects are stored to reduce cache memory
+func stripManagedFields(obj interface{}) (interface{}, error) {
+	if accessor, err := meta.Accessor(obj); err == nil {
+		accessor.SetManagedFields(nil)
+	}
+	return obj, nil
+}
+
+// listRuntimeObjects adapts a lister result to a plain interface slice
+func listRuntimeObjects[T any](items []T, err error) ([]interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		objects = append(objects, item)
+	}
+	return objects, nil
+}
+
+// copySorted deep copies lister results into list items ordered by namespace, then name.
+// Lister results are shared with the informer store, so callers must never get them.
+func copySorted[T any, P interface {
+	*T
+	metav1.Object
+	DeepCopy() *T
+}](objects []P) []T {
+	items := make([]T, 0, len(objects))
+	for _, obj := range objects {
+		items = append(items, *obj.DeepCopy())
+	}
+	sort.Slice(items, func(i, j int) bool {
+		a, b := P(&items[i]), P(&items[j])
+		return lessNamespaced(a.GetNamespace(), a.GetName(), b.GetNamespace(), b.GetName())
+	})
+	return items
+}
+
+// lessNamespaced orders objects by namespace, then name
+func lessNamespaced(nsA, nameA, nsB, nameB string) bool {
+	if nsA != nsB {
+		return nsA < nsB
+	}
+	return nameA < nameB
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+	"k8s-monitor/pkg/utils"
+)
+
+// AllClusters is the cluster selector that aggregates every available cluster
+const AllClusters = "all"
+
+var (
+	// ErrClusterNotFound is returned when a cluster name is not in the registry
+	ErrClusterNotFound = errors.New("cluster not found")
+	// ErrClusterUnavailable is returned when a registered cluster could not be initialized
+	ErrClusterUnavailable = errors.New("cluster unavailable")
+)
+
+// Cluster bundles the services bound to a single Kubernetes cluster
+type Cluster struct {
+	Name         string
+	Context      string
+	K8s          KubernetesClient
+	Applications *ApplicationService
+	initErr      error
+}
+
+// Available reports whether the cluster was initialized successfully
+func (c *Cluster) Available() bool {
+	return c.initErr == nil && c.K8s != nil
+}
+
+// ClusterManager is the registry of clusters monitored by the server
+type ClusterManager struct {
+	clusters       map[string]*Cluster
+	names          []string
+	defaultCluster string
+	logger         *logrus.Logger
+}
+
+// NewClusterManager connects to every configured cluster. Clusters that fail to
+// initialize are kept in the registry as unavailable; an error is returned only
+// when no cluster could be reached at all. grouper decides how applications are
+// grouped in every cluster. wrapTransport is optional and applied to the clients
+// of every cluster.
+func NewClusterManager(cfg config.KubernetesConfig, grouper *Grouper, wrapTransport TransportWrapper, logger *logrus.Logger) (*ClusterManager, error) {
+	clusterConfigs, err := resolveClusterConfigs(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &ClusterManager{
+		clusters: make(map[string]*Cluster, len(clusterConfigs)),
+		logger:   logger,
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, clusterCfg := range clusterConfigs {
+		if _, exists := m.clusters[clusterCfg.Name]; exists {
+			return nil, fmt.Errorf("duplicate cluster name %q", clusterCfg.Name)
+		}
+		m.clusters[clusterCfg.Name] = &Cluster{Name: clusterCfg.Name, Context: clusterCfg.Context}
+		m.names = append(m.names, clusterCfg.Name)
+
+		wg.Add(1)
+		go func(clusterCfg config.ClusterConfig) {
+			defer wg.Done()
+
+			clusterLogger := utils.WithCluster(logger, clusterCfg.Name)
+			k8sService, err := NewKubernetesService(clusterCfg, cfg, wrapTransport, logger)
+
+			mu.Lock()
+			defer mu.Unlock()
+			cluster := m.clusters[clusterCfg.Name]
+			if err != nil {
+				clusterLogger.WithError(err).Warn("Failed to initialize cluster")
+				cluster.initErr = err
+				return
+			}
+			cluster.K8s = k8sService
+			cluster.Applications = NewApplicationService(k8sService, grouper, logger)
+			clusterLogger.Info("Cluster initialized")
+		}(clusterCfg)
+	}
+	wg.Wait()
+
+	sort.Strings(m.names)
+
+	m.defaultCluster = cfg.DefaultCluster
+	if _, ok := m.clusters[m.defaultCluster]; !ok {
+		m.defaultCluster = clusterConfigs[0].Name
+	}
+
+	if len(m.Available()) == 0 {
+		return nil, fmt.Errorf("no cluster could be initialized: %w", m.clusters[m.defaultCluster].initErr)
+	}
+
+	return m, nil
+}
+
+// NewClusterManagerFromClients creates a registry around already constructed clients,
+// keyed by their cluster names, with the default grouping. The first client is the
+// default cluster.
+func NewClusterManagerFromClients(clients []KubernetesClient, logger *logrus.Logger) *ClusterManager {
+	m := &ClusterManager{
+		clusters: make(map[string]*Cluster, len(clients)),
+		logger:   logger,
+	}
+
+	for _, client := range clients {
+		name := client.ClusterName()
+		m.clusters[name] = &Cluster{
+			Name:         name,
+			K8s:          client,
+			Applications: NewApplicationService(client, nil, logger),
+		}
+		m.names = append(m.names, name)
+	}
+
+	if len(clients) > 0 {
+		m.defaultCluster = clients[0].ClusterName()
+	}
+	sort.Strings(m.names)
+
+	return m
+}
+
+// resolveClusterConfigs expands the Kubernetes configuration into the list of clusters to monitor
+func resolveClusterConfigs(cfg config.KubernetesConfig) ([]config.ClusterConfig, error) {
+	var clusters []config.ClusterConfig
+
+	for _, cluster := range cfg.Clusters {
+		if cluster.ConfigPath == "" && !cluster.InCluster {
+			cluster.ConfigPath = cfg.ConfigPath
+		}
+		if cluster.Name == "" {
+			cluster.Name = cluster.Context
+		}
+		if cluster.Name == "" {
+			return nil, errors.New("cluster entries must set a name or a context")
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	contexts := cfg.Contexts
+	if len(contexts) == 1 && contexts[0] == "*" {
+		rawConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			kubeconfigLoadingRules(cfg.ConfigPath), &clientcmd.ConfigOverrides{}).RawConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read kubeconfig contexts: %w", err)
+		}
+		contexts = contexts[:0]
+		for name := range rawConfig.Contexts {
+			contexts = append(contexts, name)
+		}
+		sort.Strings(contexts)
+	}
+
+	for _, context := range contexts {
+		clusters = append(clusters, config.ClusterConfig{
+			Name:       context,
+			ConfigPath: cfg.ConfigPath,
+			Context:    context,
+		})
+	}
+
+	// Single-cluster mode using the top-level connection settings
+	if len(clusters) == 0 {
+		clusters = append(clusters, config.ClusterConfig{
+			Name:       cfg.DefaultCluster,
+			InCluster:  cfg.InCluster,
+			ConfigPath: cfg.ConfigPath,
+		})
+	}
+
+	return clusters, nil
+}
+
+// DefaultName returns the name of the cluster used when requests do not select one
+func (m *ClusterManager) DefaultName() string {
+	return m.defaultCluster
+}
+
+// Default returns the default cluster, or nil when it is unavailable
+func (m *ClusterManager) Default() *Cluster {
+	cluster, err := m.Get(m.defaultCluster)
+	if err != nil {
+		return nil
+	}
+	return cluster
+}
+
+// Get returns an available cluster by name; an empty name selects the default cluster
+func (m *ClusterManager) Get(name string) (*Cluster, error) {
+	if name == "" {
+		name = m.defaultCluster
+	}
+
+	cluster, ok := m.clusters[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrClusterNotFound, name)
+	}
+	if !cluster.Available() {
+		return nil, fmt.Errorf("%w: %s: %v", ErrClusterUnavailable, name, cluster.initErr)
+	}
+	return cluster, nil
+}
+
+// Resolve returns the clusters addressed by a selector: a single cluster name,
+// the default cluster when empty, or every available cluster for AllClusters
+func (m *ClusterManager) Resolve(selector string) ([]*Cluster, error) {
+	if selector == AllClusters {
+		return m.Available(), nil
+	}
+
+	cluster, err := m.Get(selector)
+	if err != nil {
+		return nil, err
+	}
+	return []*Cluster{cluster}, nil
+}
+
+// Available returns every initialized cluster sorted by name
+func (m *ClusterManager) Available() []*Cluster {
+	var clusters []*Cluster
+	for _, name := range m.names {
+		if cluster := m.clusters[name]; cluster.Available() {
+			clusters = append(clusters, cluster)
+		}
+	}
+	return clusters
+}
+
+// Status checks connectivity to every registered cluster
+func (m *ClusterManager) Status() []models.ClusterInfo {
+	infos := make([]models.ClusterInfo, len(m.names))
+
+	var wg sync.WaitGroup
+	for i, name := range m.names {
+		cluster := m.clusters[name]
+		infos[i] = models.ClusterInfo{
+			Name:    cluster.Name,
+			Context: cluster.Context,
+			Default: cluster.Name == m.defaultCluster,
+		}
+
+		if !cluster.Available() {
+			infos[i].Status = models.ClusterStatusDisconnected
+			infos[i].Error = cluster.initErr.Error()
+			continue
+		}
+
+		infos[i].Server = cluster.K8s.Host()
+		infos[i].Cache = cluster.K8s.CacheStatus()
+
+		wg.Add(1)
+		go func(info *models.ClusterInfo, k8sService KubernetesClient) {
+			defer wg.Done()
+			version, err := k8sService.ServerVersion()
+			if err != nil {
+				info.Status = models.ClusterStatusDisconnected
+				info.Error = err.Error()
+				return
+			}
+			info.Status = models.ClusterStatusConnected
+			info.Version = version
+		}(&infos[i], cluster.K8s)
+	}
+	wg.Wait()
+
+	return infos
+}
+
+// Stop shuts down the informers of every cluster
+func (m *ClusterManager) Stop() {
+	for _, cluster := range m.Available() {
+		cluster.K8s.Stop()
+	}
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s-monitor/internal/models"
+	"k8s-monitor/pkg/utils"
+)
+
+// eventKey groups the events of one reason for one object
+type eventKey struct {
+	object models.ObjectReference
+	reason string
+}
+
+// GetPodEvents builds the event timeline of a pod
+func GetPodEvents(ctx context.Context, client KubernetesClient, namespace, name string) (*models.EventsResponse, error) {
+	objects := []models.ObjectReference{{Kind: "Pod", Name: name}}
+	return buildEventsResponse(ctx, client, "Pod", namespace, name, objects)
+}
+
+// GetApplicationEvents builds the event timeline of an application from the events of
+// its workloads, its pods and their owner chains, and its services
+func (a *ApplicationService) GetApplicationEvents(ctx context.Context, namespace, name string) (*models.EventsResponse, error) {
+	group, err := a.findApplication(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := a.getApplicationObjects(ctx, namespace, name, group)
+	return buildEventsResponse(ctx, a.k8sService, "Application", namespace, name, objects)
+}
+
+// getApplicationObjects lists the objects whose events belong to an application
+func (a *ApplicationService) getApplicationObjects(ctx context.Context, namespace, name string, group *applicationGroup) []models.ObjectReference {
+	logger := utils.WithApplication(a.logger, namespace, name)
+
+	seen := make(map[models.ObjectReference]bool)
+	var objects []models.ObjectReference
+	add := func(kind, name string) {
+		ref := models.ObjectReference{Kind: kind, Name: name}
+		if !seen[ref] {
+			seen[ref] = true
+			objects = append(objects, ref)
+		}
+	}
+
+	for _, w := range group.workloads {
+		add(w.info.Kind, w.info.Name)
+	}
+
+	// Follow each pod up its owner chain, e.g. to its ReplicaSet and Deployment
+	resolver := newOwnerResolver(a.k8sService, logger)
+	for _, pod := range group.pods {
+		add("Pod", pod.Name)
+		for _, owner := range resolver.chain(ctx, namespace, pod.OwnerReferences) {
+			add(owner.Kind, owner.Name)
+		}
+	}
+
+	for _, svc := range a.findApplicationServices(namespace, name, group, a.loadServices(ctx, namespace)) {
+		add("Service", svc.Name)
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].Kind != objects[j].Kind {
+			return objects[i].Kind < objects[j].Kind
+		}
+		return objects[i].Name < objects[j].Name
+	})
+	return objects
+}
+
+// buildEventsResponse lists the namespace events and builds the timeline of the given objects
+func buildEventsResponse(ctx context.Context, client KubernetesClient, kind, namespace, name string, objects []models.ObjectReference) (*models.EventsResponse, error) {
+	eventList, err := client.GetEvents(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events for %s %s: %w", kind, name, err)
+	}
+
+	events := BuildEventTimeline(eventList.Items, objects)
+
+	warnings := 0
+	for _, event := range events {
+		if event.Type == models.EventTypeWarning {
+			warnings++
+		}
+	}
+
+	return &models.EventsResponse{
+		Kind:      kind,
+		Name:      name,
+		Namespace: namespace,
+		Cluster:   client.ClusterName(),
+		Objects:   objects,
+		Events:    events,
+		Total:     len(events),
+		Warnings:  warnings,
+	}, nil
+}
+
+// BuildEventTimeline selects the events of the given objects, deduplicates them by object
+// and reason, and orders them by when they were last seen
+func BuildEventTimeline(events []corev1.Event, objects []models.ObjectReference) []models.EventInfo {
+	wanted := make(map[models.ObjectReference]bool, len(objects))
+	for _, object := range objects {
+		wanted[object] = true
+	}
+
+	grouped := make(map[eventKey]*models.EventInfo)
+	for _, event := range events {
+		object := models.ObjectReference{Kind: event.InvolvedObject.Kind, Name: event.InvolvedObject.Name}
+		if !wanted[object] {
+			continue
+		}
+
+		firstSeen, lastSeen, count := eventOccurrences(event)
+		key := eventKey{object: object, reason: event.Reason}
+
+		info, exists := grouped[key]
+		if !exists {
+			grouped[key] = &models.EventInfo{
+				Type:      event.Type,
+				Reason:    event.Reason,
+				Message:   event.Message,
+				Object:    object,
+				Source:    eventSource(event),
+				Count:     count,
+				FirstSeen: firstSeen,
+				LastSeen:  lastSeen,
+			}
+			continue
+		}
+
+		info.Count += count
+		if firstSeen.Before(info.FirstSeen) {
+			info.FirstSeen = firstSeen
+		}
+		if !lastSeen.Before(info.LastSeen) {
+			// Keep the most recent message and type
+			info.LastSeen = lastSeen
+			info.Message = event.Message
+			info.Type = event.Type
+		}
+	}
+
+	timeline := make([]models.EventInfo, 0, len(grouped))
+	for _, info := range grouped {
+		timeline = append(timeline, *info)
+	}
+	sort.Slice(timeline, func(i, j int) bool {
+		a, b := timeline[i], timeline[j]
+		if !a.LastSeen.Equal(b.LastSeen) {
+			return a.LastSeen.Before(b.LastSeen)
+		}
+		if !a.FirstSeen.Equal(b.FirstSeen) {
+			return a.FirstSeen.Before(b.FirstSeen)
+		}
+		if a.Object != b.Object {
+			return a.Object.Kind+"/"+a.Object.Name < b.Object.Kind+"/"+b.Object.Name
+		}
+		return a.Reason < b.Reason
+	})
+
+	return timeline
+}
+
+// eventOccurrences returns when an event was first and last seen and how often it occurred,
+// covering both the legacy count/timestamp fields and event series
+func eventOccurrences(event corev1.Event) (time.Time, time.Time, int32) {
+	firstSeen := event.FirstTimestamp.Time
+	if firstSeen.IsZero() {
+		firstSeen = event.EventTime.Time
+	}
+	if firstSeen.IsZero() {
+		firstSeen = event.CreationTimestamp.Time
+	}
+
+	lastSeen := event.LastTimestamp.Time
+	count := event.Count
+	if event.Series != nil {
+		lastSeen = event.Series.LastObservedTime.Time
+		count = event.Series.Count
+	}
+	if lastSeen.IsZero() || lastSeen.Before(firstSeen) {
+		lastSeen = firstSeen
+	}
+	if count < 1 {
+		count = 1
+	}
+
+	return firstSeen, lastSeen, count
+}
+
+// eventSource returns the component that reported an event
+func eventSource(event corev1.Event) string {
+	if event.Source.Component != "" {
+		return event.Source.Component
+	}
+	return event.ReportingController
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+)
+
+var eventBase = time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)
+
+func newTestEvent(name, kind, object, eventType, reason, message string, first, last int, count int32) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "shop", Name: name},
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Namespace: "shop", Name: object},
+		Type:           eventType,
+		Reason:         reason,
+		Message:        message,
+		Count:          count,
+		FirstTimestamp: metav1.NewTime(eventBase.Add(time.Duration(first) * time.Minute)),
+		LastTimestamp:  metav1.NewTime(eventBase.Add(time.Duration(last) * time.Minute)),
+	}
+}
+
+func TestBuildEventTimeline(t *testing.T) {
+	events := []corev1.Event{
+		*newTestEvent("e1", "Pod", "api-1", "Warning", "BackOff", "Back-off restarting", 5, 8, 3),
+		*newTestEvent("e2", "Pod", "api-1", "Normal", "Scheduled", "Assigned to node-1", 0, 0, 1),
+		*newTestEvent("e3", "Pod", "api-1", "Warning", "BackOff", "Back-off restarting again", 2, 10, 2),
+		*newTestEvent("e4", "Pod", "web-1", "Warning", "BackOff", "unrelated", 1, 1, 1),
+	}
+
+	timeline := BuildEventTimeline(events, []models.ObjectReference{{Kind: "Pod", Name: "api-1"}})
+	if len(timeline) != 2 {
+		t.Fatalf("got %d events, want 2: %+v", len(timeline), timeline)
+	}
+
+	if timeline[0].Reason != "Scheduled" {
+		t.Errorf("timeline must be ordered by last seen, got %s first", timeline[0].Reason)
+	}
+
+	backOff := timeline[1]
+	if backOff.Count != 5 {
+		t.Errorf("Count = %d, want 5", backOff.Count)
+	}
+	if !backOff.FirstSeen.Equal(eventBase.Add(2*time.Minute)) || !backOff.LastSeen.Equal(eventBase.Add(10*time.Minute)) {
+		t.Errorf("FirstSeen/LastSeen = %v/%v", backOff.FirstSeen, backOff.LastSeen)
+	}
+	if backOff.Message != "Back-off restarting again" {
+		t.Errorf("Message = %q, want the most recent message", backOff.Message)
+	}
+}
+
+func TestGetApplicationEvents(t *testing.T) {
+	isController := true
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "shop",
+			Name:      "api-7d9f8b6c5",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "Deployment", Name: "api", Controller: &isController},
+			},
+		},
+	}
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "api", Labels: map[string]string{"app": "api"}}}
+
+	objects := []runtime.Object{
+		testPod{namespace: "shop", name: "api-7d9f8b6c5-abcde", labels: map[string]string{"app": "api"}, ownerKind: "ReplicaSet", ownerName: "api-7d9f8b6c5"}.build(),
+		replicaSet,
+		service,
+		newTestEvent("e1", "Pod", "api-7d9f8b6c5-abcde", "Warning", "Unhealthy", "Readiness probe failed", 3, 4, 2),
+		newTestEvent("e2", "ReplicaSet", "api-7d9f8b6c5", "Normal", "SuccessfulCreate", "Created pod", 1, 1, 1),
+		newTestEvent("e3", "Deployment", "api", "Normal", "ScalingReplicaSet", "Scaled up", 0, 0, 1),
+		newTestEvent("e4", "Service", "api", "Warning", "SyncLoadBalancerFailed", "quota exceeded", 2, 2, 1),
+		newTestEvent("e5", "Deployment", "web", "Normal", "ScalingReplicaSet", "unrelated", 0, 0, 1),
+	}
+
+	k8s := newTestKubernetesService(config.KubernetesConfig{}, objects)
+	response, err := NewApplicationService(k8s, nil, newTestLogger()).GetApplicationEvents(context.Background(), "shop", "api")
+	if err != nil {
+		t.Fatalf("GetApplicationEvents() error = %v", err)
+	}
+
+	var reasons []string
+	for _, event := range response.Events {
+		reasons = append(reasons, event.Reason)
+	}
+	want := []string{"ScalingReplicaSet", "SuccessfulCreate", "SyncLoadBalancerFailed", "Unhealthy"}
+	if !reflect.DeepEqual(reasons, want) {
+		t.Errorf("reasons = %v, want %v", reasons, want)
+	}
+	if response.Warnings != 2 || response.Total != 4 || response.Kind != "Application" {
+		t.Errorf("unexpected response %+v", response)
+	}
+}
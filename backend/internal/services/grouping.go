@@ -0,0 +1,200 @@
+package services
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+
+	"k8s-monitor/internal/config"
+)
+
+// DefaultPartOfLabel is the label that groups applications into systems
+const DefaultPartOfLabel = "app.kubernetes.io/part-of"
+
+// DefaultGroupingKeys name applications when no keys are configured
+var DefaultGroupingKeys = []config.GroupingKeyConfig{
+	{Label: "app.kubernetes.io/name"},
+	{Label: "app.kubernetes.io/instance"},
+	{Label: "app"},
+	{Label: "application"},
+	{Label: "k8s-app"},
+}
+
+// DefaultArgoCDKeys find the managing ArgoCD application when no keys are configured:
+// the annotation tracking method, then the default label tracking method
+var DefaultArgoCDKeys = []config.GroupingKeyConfig{
+	{Annotation: "argocd.argoproj.io/tracking-id", Pattern: `^([^:]+):`},
+	{Label: "app.kubernetes.io/instance"},
+}
+
+// Grouper decides which application, system and ArgoCD application an object belongs to
+// from its labels and annotations
+type Grouper struct {
+	keys          []groupingKey
+	argoKeys      []groupingKey
+	groupByPartOf bool
+	partOfLabel   string
+	namespaces    []namespaceGrouping
+}
+
+// groupingKey is a validated grouping key
+type groupingKey struct {
+	label      string
+	annotation string
+	pattern    *regexp.Regexp
+}
+
+// namespaceGrouping is a validated namespace override
+type namespaceGrouping struct {
+	pattern       string
+	keys          []groupingKey
+	groupByPartOf *bool
+}
+
+// NewGrouper validates a grouping configuration
+func NewGrouper(cfg config.GroupingConfig) (*Grouper, error) {
+	keys, err := newGroupingKeys(cfg.Keys, DefaultGroupingKeys)
+	if err != nil {
+		return nil, err
+	}
+	argoKeys, err := newGroupingKeys(cfg.ArgoCDKeys, DefaultArgoCDKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Grouper{
+		keys:          keys,
+		argoKeys:      argoKeys,
+		groupByPartOf: cfg.GroupByPartOf,
+		partOfLabel:   cfg.PartOfLabel,
+	}
+	if g.partOfLabel == "" {
+		g.partOfLabel = DefaultPartOfLabel
+	}
+
+	for _, override := range cfg.Namespaces {
+		if override.Namespace == "" {
+			return nil, fmt.Errorf("grouping override without a namespace")
+		}
+		if _, err := path.Match(override.Namespace, ""); err != nil {
+			return nil, fmt.Errorf("grouping override %q: invalid namespace pattern", override.Namespace)
+		}
+		ns := namespaceGrouping{pattern: override.Namespace, groupByPartOf: override.GroupByPartOf}
+		if len(override.Keys) > 0 {
+			if ns.keys, err = newGroupingKeys(override.Keys, nil); err != nil {
+				return nil, fmt.Errorf("grouping override %q: %w", override.Namespace, err)
+			}
+		}
+		g.namespaces = append(g.namespaces, ns)
+	}
+
+	return g, nil
+}
+
+// DefaultGrouper returns the grouping used without configuration
+func DefaultGrouper() *Grouper {
+	g, err := NewGrouper(config.GroupingConfig{})
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// newGroupingKeys validates keys, using the defaults when none are configured
+func newGroupingKeys(configs, defaults []config.GroupingKeyConfig) ([]groupingKey, error) {
+	if len(configs) == 0 {
+		configs = defaults
+	}
+
+	keys := make([]groupingKey, 0, len(configs))
+	for _, cfg := range configs {
+		if (cfg.Label == "") == (cfg.Annotation == "") {
+			return nil, fmt.Errorf("grouping key must set exactly one of label or annotation")
+		}
+		key := groupingKey{label: cfg.Label, annotation: cfg.Annotation}
+		if cfg.Pattern != "" {
+			pattern, err := regexp.Compile(cfg.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("grouping key pattern %q: %w", cfg.Pattern, err)
+			}
+			key.pattern = pattern
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// extract returns the name held by the key, if any. With a pattern, the name is the first
+// capture group, or the whole match when the pattern has no group.
+func (k groupingKey) extract(labels, annotations map[string]string) string {
+	value := labels[k.label]
+	if k.annotation != "" {
+		value = annotations[k.annotation]
+	}
+	if value == "" || k.pattern == nil {
+		return value
+	}
+
+	match := k.pattern.FindStringSubmatch(value)
+	switch {
+	case match == nil:
+		return ""
+	case len(match) > 1:
+		return match[1]
+	default:
+		return match[0]
+	}
+}
+
+// override returns the override of a namespace, if any
+func (g *Grouper) override(namespace string) *namespaceGrouping {
+	for i := range g.namespaces {
+		if ok, _ := path.Match(g.namespaces[i].pattern, namespace); ok {
+			return &g.namespaces[i]
+		}
+	}
+	return nil
+}
+
+// Name returns the application name held by the labels and annotations of an object in a
+// namespace, or an empty string when no key matches
+func (g *Grouper) Name(namespace string, labels, annotations map[string]string) string {
+	keys := g.keys
+	if override := g.override(namespace); override != nil && override.keys != nil {
+		keys = override.keys
+	}
+	return firstMatch(keys, labels, annotations)
+}
+
+// PartOf returns the system an object is part of, or an empty string
+func (g *Grouper) PartOf(labels map[string]string) string {
+	return labels[g.partOfLabel]
+}
+
+// System returns the system an object is grouped into, or an empty string when the
+// namespace is not grouped by systems or the object is not part of one
+func (g *Grouper) System(namespace string, labels map[string]string) string {
+	groupByPartOf := g.groupByPartOf
+	if override := g.override(namespace); override != nil && override.groupByPartOf != nil {
+		groupByPartOf = *override.groupByPartOf
+	}
+	if !groupByPartOf {
+		return ""
+	}
+	return g.PartOf(labels)
+}
+
+// ArgoCDApplication returns the ArgoCD application managing an object, or an empty string
+func (g *Grouper) ArgoCDApplication(labels, annotations map[string]string) string {
+	return firstMatch(g.argoKeys, labels, annotations)
+}
+
+// firstMatch returns the name held by the first matching key
+func firstMatch(keys []groupingKey, labels, annotations map[string]string) string {
+	for _, key := range keys {
+		if name := key.extract(labels, annotations); name != "" {
+			return name
+		}
+	}
+	return ""
+}
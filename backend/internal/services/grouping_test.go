@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+)
+
+func TestGrouperName(t *testing.T) {
+	enabled := true
+	grouper, err := NewGrouper(config.GroupingConfig{
+		Keys: []config.GroupingKeyConfig{
+			{Annotation: "example.com/service", Pattern: `^svc-(.+)$`},
+			{Label: "app"},
+		},
+		Namespaces: []config.GroupingNamespaceConfig{
+			{Namespace: "team-*", Keys: []config.GroupingKeyConfig{{Label: "team.example.com/component"}}, GroupByPartOf: &enabled},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewGrouper() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		namespace   string
+		labels      map[string]string
+		annotations map[string]string
+		want        string
+	}{
+		{name: "pattern capture group", namespace: "shop", labels: map[string]string{"app": "web"}, annotations: map[string]string{"example.com/service": "svc-checkout"}, want: "checkout"},
+		{name: "pattern mismatch falls through", namespace: "shop", labels: map[string]string{"app": "web"}, annotations: map[string]string{"example.com/service": "checkout"}, want: "web"},
+		{name: "default keys are replaced", namespace: "shop", labels: map[string]string{"app.kubernetes.io/name": "web"}, want: ""},
+		{name: "namespace override", namespace: "team-a", labels: map[string]string{"app": "web", "team.example.com/component": "frontend"}, want: "frontend"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := grouper.Name(tt.namespace, tt.labels, tt.annotations); got != tt.want {
+				t.Errorf("Name() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	partOf := map[string]string{DefaultPartOfLabel: "storefront"}
+	if got := grouper.System("shop", partOf); got != "" {
+		t.Errorf("System(shop) = %q, want no system without part-of grouping", got)
+	}
+	if got := grouper.System("team-a", partOf); got != "storefront" {
+		t.Errorf("System(team-a) = %q, want storefront", got)
+	}
+}
+
+func TestGrouperArgoCDApplication(t *testing.T) {
+	grouper := DefaultGrouper()
+
+	tracked := map[string]string{"argocd.argoproj.io/tracking-id": "shop-prod:apps/Deployment:shop/api"}
+	if got := grouper.ArgoCDApplication(nil, tracked); got != "shop-prod" {
+		t.Errorf("ArgoCDApplication(tracking-id) = %q, want shop-prod", got)
+	}
+	if got := grouper.ArgoCDApplication(map[string]string{"app.kubernetes.io/instance": "shop-dev"}, nil); got != "shop-dev" {
+		t.Errorf("ArgoCDApplication(instance label) = %q, want shop-dev", got)
+	}
+}
+
+func TestNewGrouperValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.GroupingConfig
+	}{
+		{name: "key without source", cfg: config.GroupingConfig{Keys: []config.GroupingKeyConfig{{Pattern: "x"}}}},
+		{name: "key with two sources", cfg: config.GroupingConfig{Keys: []config.GroupingKeyConfig{{Label: "app", Annotation: "app"}}}},
+		{name: "invalid pattern", cfg: config.GroupingConfig{Keys: []config.GroupingKeyConfig{{Label: "app", Pattern: "("}}}},
+		{name: "override without namespace", cfg: config.GroupingConfig{Namespaces: []config.GroupingNamespaceConfig{{}}}},
+		{name: "invalid namespace pattern", cfg: config.GroupingConfig{Namespaces: []config.GroupingNamespaceConfig{{Namespace: "team-["}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewGrouper(tt.cfg); err == nil {
+				t.Error("NewGrouper() accepted an invalid configuration")
+			}
+		})
+	}
+}
+
+func TestGroupApplicationsBySystem(t *testing.T) {
+	grouper, err := NewGrouper(config.GroupingConfig{GroupByPartOf: true})
+	if err != nil {
+		t.Fatalf("NewGrouper() error = %v", err)
+	}
+	service := NewApplicationService(nil, grouper, newTestLogger())
+
+	storefront := map[string]string{"app": "web", DefaultPartOfLabel: "storefront"}
+	web := newTestDeployment("shop", "web", 1, 1, storefront)
+	web.Labels[DefaultPartOfLabel] = "storefront"
+	cache := newTestDeployment("shop", "cache", 1, 1, map[string]string{"app": "cache"})
+	cache.Labels[DefaultPartOfLabel] = "storefront"
+	workloads := []workload{
+		newWorkload(models.FromK8sDeployment(web), web.ObjectMeta, web.Spec.Template, web.Spec.Selector),
+		newWorkload(models.FromK8sDeployment(cache), cache.ObjectMeta, cache.Spec.Template, cache.Spec.Selector),
+	}
+
+	pods := []corev1.Pod{
+		*testPod{namespace: "shop", name: "web-5f6d-a", labels: map[string]string{"app": "web", DefaultPartOfLabel: "storefront", "pod-template-hash": "5f6d"}, ownerKind: "ReplicaSet", ownerName: "web-5f6d"}.build(),
+		*testPod{namespace: "shop", name: "cache-77c4-a", labels: map[string]string{"app": "cache", "pod-template-hash": "77c4"}, ownerKind: "ReplicaSet", ownerName: "cache-77c4"}.build(),
+		*testPod{namespace: "shop", name: "migrate", labels: map[string]string{"app": "migrate", DefaultPartOfLabel: "storefront"}}.build(),
+		*testPod{namespace: "shop", name: "debug", labels: map[string]string{"app": "toolbox"}}.build(),
+	}
+
+	groups := service.groupApplications(context.Background(), pods, workloads, nil)
+	if len(groups) != 2 {
+		t.Fatalf("got %d applications, want storefront and toolbox", len(groups))
+	}
+	system := groups[applicationKey{namespace: "shop", name: "storefront"}]
+	if system == nil || len(system.workloads) != 2 || len(system.pods) != 3 {
+		t.Fatalf("unexpected storefront system %+v", system)
+	}
+	if root := system.roots.get(pods[1]); root == nil || *root != (models.ObjectReference{Kind: "Deployment", Name: "cache"}) {
+		t.Errorf("cache pod root owner = %v, want Deployment/cache", root)
+	}
+}
@@ -3,27 +3,81 @@ package services
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"path/filepath"
 	"time"
 
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
 
 	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
 )
 
+// KubernetesClient is the cluster access used by the application service and the
+// HTTP handlers. KubernetesService is the production implementation.
+type KubernetesClient interface {
+	ClusterName() string
+	Host() string
+	HealthCheck() error
+	ServerVersion() (string, error)
+	CacheStatus() map[string]bool
+	Stop()
+	IsNamespaceAllowed(namespace string) bool
+	GetPods(ctx context.Context, namespace string) (*corev1.PodList, error)
+	GetAllPods(ctx context.Context) (*corev1.PodList, error)
+	ListPods(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PodList, error)
+	GetPod(ctx context.Context, namespace, name string) (*corev1.Pod, error)
+	GetNamespaces(ctx context.Context) (*corev1.NamespaceList, error)
+	GetServices(ctx context.Context, namespace string) (*corev1.ServiceList, error)
+	GetEndpointSlices(ctx context.Context, namespace string) (*discoveryv1.EndpointSliceList, error)
+	GetArgoApplications(ctx context.Context, namespace string) (*unstructured.UnstructuredList, error)
+	GetArgoApplication(ctx context.Context, namespace, name string) (*unstructured.Unstructured, error)
+	GetPodLogs(ctx context.Context, namespace, name string, opts *corev1.PodLogOptions) (io.ReadCloser, error)
+	GetEvents(ctx context.Context, namespace string) (*corev1.EventList, error)
+	GetReplicaSet(ctx context.Context, namespace, name string) (*appsv1.ReplicaSet, error)
+	GetJob(ctx context.Context, namespace, name string) (*batchv1.Job, error)
+	GetReplicaSets(ctx context.Context, namespace string) (*appsv1.ReplicaSetList, error)
+	GetJobs(ctx context.Context, namespace string) (*batchv1.JobList, error)
+	GetOwnerReferences(ctx context.Context, namespace string, owner metav1.OwnerReference) ([]metav1.OwnerReference, error)
+	GetDeployments(ctx context.Context, namespace string) (*appsv1.DeploymentList, error)
+	GetStatefulSets(ctx context.Context, namespace string) (*appsv1.StatefulSetList, error)
+	GetDaemonSets(ctx context.Context, namespace string) (*appsv1.DaemonSetList, error)
+	GetNodes(ctx context.Context) (*corev1.NodeList, error)
+	GetPodMetrics(ctx context.Context, namespace string) (*unstructured.UnstructuredList, error)
+	GetNodeMetrics(ctx context.Context) (*unstructured.UnstructuredList, error)
+}
+
+var _ KubernetesClient = (*KubernetesService)(nil)
+
 // KubernetesService provides access to Kubernetes API
 type KubernetesService struct {
-	clientset     *kubernetes.Clientset
+	name          string
+	host          string
+	clientset     kubernetes.Interface
 	dynamicClient dynamic.Interface
 	config        config.KubernetesConfig
+	cache         *resourceCache
+	mapper        meta.RESTMapper // resolves the resources of owner kinds, e.g. CRDs
+	snapshots     *snapshotStore  // last lists read from the API server
+	logger        *logrus.Logger
 }
 
 // ArgoCD Application GVR
@@ -33,35 +87,40 @@ var argoApplicationGVR = schema.GroupVersionResource{
 	Resource: "applications",
 }
 
-// NewKubernetesService creates a new Kubernetes service instance
-func NewKubernetesService(cfg config.KubernetesConfig) (*KubernetesService, error) {
-	var kubeConfig *rest.Config
-	var err error
+// metrics.k8s.io GVRs served by metrics-server
+var (
+	podMetricsGVR = schema.GroupVersionResource{
+		Group:    "metrics.k8s.io",
+		Version:  "v1beta1",
+		Resource: "pods",
+	}
+	nodeMetricsGVR = schema.GroupVersionResource{
+		Group:    "metrics.k8s.io",
+		Version:  "v1beta1",
+		Resource: "nodes",
+	}
+)
 
-	if cfg.InCluster {
-		// Create in-cluster config
-		kubeConfig, err = rest.InClusterConfig()
-		if err != nil {
-			return nil, fmt.Errorf("failed to create in-cluster config: %w", err)
-		}
-	} else {
-		// Create out-of-cluster config
-		kubeConfigPath := cfg.ConfigPath
-		if kubeConfigPath == "" {
-			if home := homedir.HomeDir(); home != "" {
-				kubeConfigPath = filepath.Join(home, ".kube", "config")
-			}
-		}
+// TransportWrapper wraps the HTTP transport of a cluster's clients, e.g. for instrumentation
+type TransportWrapper func(cluster string, rt http.RoundTripper) http.RoundTripper
 
-		kubeConfig, err = clientcmd.BuildConfigFromFlags("", kubeConfigPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to build kubeconfig: %w", err)
-		}
+// NewKubernetesService creates a new Kubernetes service instance for one cluster.
+// wrapTransport is optional.
+func NewKubernetesService(cluster config.ClusterConfig, cfg config.KubernetesConfig, wrapTransport TransportWrapper, logger *logrus.Logger) (*KubernetesService, error) {
+	kubeConfig, err := buildRestConfig(cluster)
+	if err != nil {
+		return nil, err
 	}
 
 	// Set timeouts for better reliability
 	kubeConfig.Timeout = 30 * time.Second
 
+	if wrapTransport != nil {
+		kubeConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return wrapTransport(cluster.Name, rt)
+		})
+	}
+
 	// Create the clientset
 	clientset, err := kubernetes.NewForConfig(kubeConfig)
 	if err != nil {
@@ -74,9 +133,14 @@ func NewKubernetesService(cfg config.KubernetesConfig) (*KubernetesService, erro
 	}
 
 	service := &KubernetesService{
+		name:          cluster.Name,
+		host:          kubeConfig.Host,
 		clientset:     clientset,
 		dynamicClient: dynamicClient,
 		config:        cfg,
+		mapper:        newRESTMapper(clientset),
+		snapshots:     newSnapshotStore(),
+		logger:        logger,
 	}
 
 	// Test the connection
@@ -84,29 +148,156 @@ func NewKubernetesService(cfg config.KubernetesConfig) (*KubernetesService, erro
 		return nil, fmt.Errorf("kubernetes connection health check failed: %w", err)
 	}
 
+	// Start informer caches
+	if cfg.Cache.Enabled {
+		service.cache = newResourceCache(clientset, dynamicClient,
+			time.Duration(cfg.Cache.ResyncPeriod)*time.Second, service.isArgoCDInstalled(),
+			logger.WithField("cluster", cluster.Name))
+		service.cache.start(time.Duration(cfg.Cache.SyncTimeout) * time.Second)
+	}
+
 	return service, nil
 }
 
+// NewKubernetesServiceForClients creates a Kubernetes service around existing clients,
+// such as the fake clientsets from client-go. No connectivity check is performed and
+// reads always go to the given clients.
+func NewKubernetesServiceForClients(name string, clientset kubernetes.Interface, dynamicClient dynamic.Interface, cfg config.KubernetesConfig, logger *logrus.Logger) *KubernetesService {
+	return &KubernetesService{
+		name:          name,
+		clientset:     clientset,
+		dynamicClient: dynamicClient,
+		config:        cfg,
+		mapper:        newRESTMapper(clientset),
+		snapshots:     newSnapshotStore(),
+		logger:        logger,
+	}
+}
+
+// newRESTMapper creates a mapper from kinds to resources that discovers the served
+// resources on first use and again when a kind is unknown
+func newRESTMapper(clientset kubernetes.Interface) meta.RESTMapper {
+	return restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(clientset.Discovery()))
+}
+
+// buildRestConfig creates the client configuration for a cluster
+func buildRestConfig(cluster config.ClusterConfig) (*rest.Config, error) {
+	if cluster.InCluster {
+		// Create in-cluster config
+		kubeConfig, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create in-cluster config: %w", err)
+		}
+		return kubeConfig, nil
+	}
+
+	// Create out-of-cluster config, honoring an explicit context when set
+	kubeConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		kubeconfigLoadingRules(cluster.ConfigPath),
+		&clientcmd.ConfigOverrides{CurrentContext: cluster.Context},
+	).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kubeconfig: %w", err)
+	}
+	return kubeConfig, nil
+}
+
+// kubeconfigLoadingRules resolves kubeconfig files from a path list, defaulting to ~/.kube/config
+func kubeconfigLoadingRules(configPath string) *clientcmd.ClientConfigLoadingRules {
+	if configPath == "" {
+		if home := homedir.HomeDir(); home != "" {
+			configPath = filepath.Join(home, ".kube", "config")
+		}
+	}
+	return &clientcmd.ClientConfigLoadingRules{Precedence: filepath.SplitList(configPath)}
+}
+
+// ClusterName returns the registry name of the cluster this service talks to
+func (k *KubernetesService) ClusterName() string {
+	return k.name
+}
+
+// Host returns the API server address of the cluster
+func (k *KubernetesService) Host() string {
+	return k.host
+}
+
+// Stop shuts down the informer caches
+func (k *KubernetesService) Stop() {
+	if k.cache != nil {
+		k.cache.stop()
+	}
+}
+
+// CacheStatus returns the sync state of each cached resource, or nil when caching is disabled
+func (k *KubernetesService) CacheStatus() map[string]bool {
+	if k.cache == nil {
+		return nil
+	}
+	return k.cache.status()
+}
+
+// cacheSynced reports whether reads for the given resource can be served from the cache.
+// While the watch of the resource is failing, the cache is still served, and the read is
+// recorded as stale in the context.
+func (k *KubernetesService) cacheSynced(ctx context.Context, resource string) bool {
+	if k.cache == nil || !k.cache.hasSynced(resource) {
+		return false
+	}
+	if since, stale := k.cache.staleSince(resource); stale {
+		models.StalenessFrom(ctx).Record(since)
+	}
+	return true
+}
+
+// isArgoCDInstalled checks whether the ArgoCD Application CRD is served by the cluster
+func (k *KubernetesService) isArgoCDInstalled() bool {
+	resources, err := k.clientset.Discovery().ServerResourcesForGroupVersion(argoApplicationGVR.GroupVersion().String())
+	if err != nil {
+		return false
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == argoApplicationGVR.Resource {
+			return true
+		}
+	}
+	return false
+}
+
 // ArgoCD methods
 func (k *KubernetesService) GetArgoApplications(ctx context.Context, namespace string) (*unstructured.UnstructuredList, error) {
-	if namespace == "" {
-		return k.dynamicClient.Resource(argoApplicationGVR).List(ctx, metav1.ListOptions{})
+	if k.cacheSynced(ctx, cacheResourceArgoApps) {
+		return k.cache.listArgoApplications(namespace)
 	}
-	return k.dynamicClient.Resource(argoApplicationGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	return listWithSnapshot(ctx, k, "argocd-applications/"+namespace, func() (*unstructured.UnstructuredList, error) {
+		if namespace == "" {
+			return k.dynamicClient.Resource(argoApplicationGVR).List(ctx, metav1.ListOptions{})
+		}
+		return k.dynamicClient.Resource(argoApplicationGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	})
 }
 
 func (k *KubernetesService) GetArgoApplication(ctx context.Context, namespace, name string) (*unstructured.Unstructured, error) {
+	if k.cacheSynced(ctx, cacheResourceArgoApps) {
+		return k.cache.getArgoApplication(namespace, name)
+	}
 	return k.dynamicClient.Resource(argoApplicationGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
 }
 
 // HealthCheck performs a basic connectivity test to the Kubernetes API server
 func (k *KubernetesService) HealthCheck() error {
 	// Try to get server version
-	_, err := k.clientset.Discovery().ServerVersion()
+	_, err := k.ServerVersion()
+	return err
+}
+
+// ServerVersion returns the Kubernetes version reported by the API server
+func (k *KubernetesService) ServerVersion() (string, error) {
+	info, err := k.clientset.Discovery().ServerVersion()
 	if err != nil {
-		return fmt.Errorf("failed to get server version: %w", err)
+		return "", fmt.Errorf("failed to get server version: %w", err)
 	}
-	return nil
+	return info.GitVersion, nil
 }
 
 // GetPods retrieves pods from specified namespace
@@ -114,33 +305,93 @@ func (k *KubernetesService) GetPods(ctx context.Context, namespace string) (*cor
 	if namespace == "" {
 		namespace = "default"
 	}
-	pods, err := k.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+	if k.cacheSynced(ctx, cacheResourcePods) {
+		return k.cache.listPods(namespace, labels.Everything(), fields.Everything())
 	}
-	return pods, nil
+	return listWithSnapshot(ctx, k, "pods/"+namespace, func() (*corev1.PodList, error) {
+		pods, err := k.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+		}
+		return pods, nil
+	})
+}
+
+// ListPods retrieves the pods of a namespace, or of all namespaces when namespace is
+// empty, matching the label and field selectors of opts. Lists chunked with a limit or a
+// continue token are always read from the API server, which issues the tokens.
+func (k *KubernetesService) ListPods(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PodList, error) {
+	chunked := opts.Limit > 0 || opts.Continue != ""
+	if !chunked && k.cacheSynced(ctx, cacheResourcePods) {
+		labelSelector, err := labels.Parse(opts.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector: %w", err)
+		}
+		fieldSelector, err := fields.ParseSelector(opts.FieldSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid field selector: %w", err)
+		}
+		return k.cache.listPods(namespace, labelSelector, fieldSelector)
+	}
+
+	list := func() (*corev1.PodList, error) {
+		pods, err := k.clientset.CoreV1().Pods(namespace).List(ctx, opts)
+		if err != nil {
+			if namespace == "" {
+				return nil, fmt.Errorf("failed to list all pods: %w", err)
+			}
+			return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+		}
+		return pods, nil
+	}
+	if chunked {
+		return list()
+	}
+
+	key := "pods/" + namespace
+	if opts.LabelSelector != "" || opts.FieldSelector != "" {
+		key += "?" + opts.LabelSelector + "&" + opts.FieldSelector
+	}
+	return listWithSnapshot(ctx, k, key, list)
 }
 
 // GetAllPods retrieves pods from all accessible namespaces
 func (k *KubernetesService) GetAllPods(ctx context.Context) (*corev1.PodList, error) {
-	pods, err := k.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list all pods: %w", err)
+	if k.cacheSynced(ctx, cacheResourcePods) {
+		return k.cache.listPods("", labels.Everything(), fields.Everything())
 	}
-	return pods, nil
+	return listWithSnapshot(ctx, k, "pods/", func() (*corev1.PodList, error) {
+		pods, err := k.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list all pods: %w", err)
+		}
+		return pods, nil
+	})
 }
 
 // GetNamespaces retrieves all accessible namespaces
 func (k *KubernetesService) GetNamespaces(ctx context.Context) (*corev1.NamespaceList, error) {
-	namespaces, err := k.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	if k.cacheSynced(ctx, cacheResourceNamespaces) {
+		return k.cache.listNamespaces()
 	}
-	return namespaces, nil
+	return listWithSnapshot(ctx, k, "namespaces", func() (*corev1.NamespaceList, error) {
+		namespaces, err := k.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list namespaces: %w", err)
+		}
+		return namespaces, nil
+	})
 }
 
 // GetPod retrieves a specific pod by name and namespace
 func (k *KubernetesService) GetPod(ctx context.Context, namespace, name string) (*corev1.Pod, error) {
+	if k.cacheSynced(ctx, cacheResourcePods) {
+		pod, err := k.cache.getPod(namespace, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod %s in namespace %s: %w", name, namespace, err)
+		}
+		return pod, nil
+	}
 	pod, err := k.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pod %s in namespace %s: %w", name, namespace, err)
@@ -148,16 +399,275 @@ func (k *KubernetesService) GetPod(ctx context.Context, namespace, name string)
 	return pod, nil
 }
 
-// GetServices retrieves services from specified namespace
-func (k *KubernetesService) GetServices(ctx context.Context, namespace string) (*corev1.ServiceList, error) {
+// GetPodLogs opens a stream of container logs for a pod
+func (k *KubernetesService) GetPodLogs(ctx context.Context, namespace, name string, opts *corev1.PodLogOptions) (io.ReadCloser, error) {
+	stream, err := k.clientset.CoreV1().Pods(namespace).GetLogs(name, opts).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs of pod %s in namespace %s: %w", name, namespace, err)
+	}
+	return stream, nil
+}
+
+// GetEvents retrieves the events of a namespace. Events churn too much to be worth
+// caching, so they are always listed from the API server.
+func (k *KubernetesService) GetEvents(ctx context.Context, namespace string) (*corev1.EventList, error) {
+	return listWithSnapshot(ctx, k, "events/"+namespace, func() (*corev1.EventList, error) {
+		events, err := k.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list events in namespace %s: %w", namespace, err)
+		}
+		return events, nil
+	})
+}
+
+// GetReplicaSet retrieves a specific replica set
+func (k *KubernetesService) GetReplicaSet(ctx context.Context, namespace, name string) (*appsv1.ReplicaSet, error) {
+	var replicaSet *appsv1.ReplicaSet
+	var err error
+	if k.cacheSynced(ctx, cacheResourceReplicaSets) {
+		replicaSet, err = k.cache.getReplicaSet(namespace, name)
+	} else {
+		replicaSet, err = k.clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replica set %s in namespace %s: %w", name, namespace, err)
+	}
+	return replicaSet, nil
+}
+
+// GetJob retrieves a specific job
+func (k *KubernetesService) GetJob(ctx context.Context, namespace, name string) (*batchv1.Job, error) {
+	var job *batchv1.Job
+	var err error
+	if k.cacheSynced(ctx, cacheResourceJobs) {
+		job, err = k.cache.getJob(namespace, name)
+	} else {
+		job, err = k.clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %s in namespace %s: %w", name, namespace, err)
+	}
+	return job, nil
+}
+
+// GetReplicaSets retrieves replica sets from a namespace, or from all namespaces when
+// namespace is empty
+func (k *KubernetesService) GetReplicaSets(ctx context.Context, namespace string) (*appsv1.ReplicaSetList, error) {
+	if k.cacheSynced(ctx, cacheResourceReplicaSets) {
+		return k.cache.listReplicaSets(namespace)
+	}
+	return listWithSnapshot(ctx, k, "replicasets/"+namespace, func() (*appsv1.ReplicaSetList, error) {
+		replicaSets, err := k.clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list replica sets: %w", err)
+		}
+		return replicaSets, nil
+	})
+}
+
+// GetJobs retrieves jobs from a namespace, or from all namespaces when namespace is empty
+func (k *KubernetesService) GetJobs(ctx context.Context, namespace string) (*batchv1.JobList, error) {
+	if k.cacheSynced(ctx, cacheResourceJobs) {
+		return k.cache.listJobs(namespace)
+	}
+	return listWithSnapshot(ctx, k, "jobs/"+namespace, func() (*batchv1.JobList, error) {
+		jobs, err := k.clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list jobs: %w", err)
+		}
+		return jobs, nil
+	})
+}
+
+// GetOwnerReferences retrieves the owner references of the object an owner reference
+// points to. ReplicaSets, Jobs and the workloads owning them are read from the cache.
+// CronJobs are taken as root owners and not read at all. Any other kind, such as a
+// custom resource, is read through the dynamic client.
+func (k *KubernetesService) GetOwnerReferences(ctx context.Context, namespace string, owner metav1.OwnerReference) ([]metav1.OwnerReference, error) {
+	if owner.APIVersion == "" {
+		return nil, fmt.Errorf("owner %s %s has no API version", owner.Kind, owner.Name)
+	}
+	gv, err := schema.ParseGroupVersion(owner.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid owner API version %q: %w", owner.APIVersion, err)
+	}
+
+	switch (schema.GroupKind{Group: gv.Group, Kind: owner.Kind}) {
+	case schema.GroupKind{Group: appsv1.GroupName, Kind: "ReplicaSet"}:
+		replicaSet, err := k.GetReplicaSet(ctx, namespace, owner.Name)
+		if err != nil {
+			return nil, err
+		}
+		return replicaSet.OwnerReferences, nil
+	case schema.GroupKind{Group: batchv1.GroupName, Kind: "Job"}:
+		job, err := k.GetJob(ctx, namespace, owner.Name)
+		if err != nil {
+			return nil, err
+		}
+		return job.OwnerReferences, nil
+	case schema.GroupKind{Group: appsv1.GroupName, Kind: "Deployment"}:
+		deployment, err := getObject(ctx, k, cacheResourceDeployments, namespace, owner.Name,
+			k.cache.getDeployment, k.clientset.AppsV1().Deployments(namespace).Get)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deployment %s in namespace %s: %w", owner.Name, namespace, err)
+		}
+		return deployment.OwnerReferences, nil
+	case schema.GroupKind{Group: appsv1.GroupName, Kind: "StatefulSet"}:
+		statefulSet, err := getObject(ctx, k, cacheResourceStatefulSets, namespace, owner.Name,
+			k.cache.getStatefulSet, k.clientset.AppsV1().StatefulSets(namespace).Get)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get stateful set %s in namespace %s: %w", owner.Name, namespace, err)
+		}
+		return statefulSet.OwnerReferences, nil
+	case schema.GroupKind{Group: appsv1.GroupName, Kind: "DaemonSet"}:
+		daemonSet, err := getObject(ctx, k, cacheResourceDaemonSets, namespace, owner.Name,
+			k.cache.getDaemonSet, k.clientset.AppsV1().DaemonSets(namespace).Get)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get daemon set %s in namespace %s: %w", owner.Name, namespace, err)
+		}
+		return daemonSet.OwnerReferences, nil
+	case schema.GroupKind{Group: batchv1.GroupName, Kind: "CronJob"}:
+		return nil, nil
+	}
+
+	mapping, err := k.mapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: owner.Kind}, gv.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve owner kind %s: %w", owner.Kind, err)
+	}
+
+	resource := k.dynamicClient.Resource(mapping.Resource)
+	var obj *unstructured.Unstructured
+	if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		obj, err = resource.Get(ctx, owner.Name, metav1.GetOptions{})
+	} else {
+		obj, err = resource.Namespace(namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s in namespace %s: %w", owner.Kind, owner.Name, namespace, err)
+	}
+	return obj.GetOwnerReferences(), nil
+}
+
+// getObject reads an object from the cache when the resource is synced, or else from
+// the API server
+func getObject[T any](ctx context.Context, k *KubernetesService, resource, namespace, name string,
+	fromCache func(namespace, name string) (T, error),
+	fromAPI func(ctx context.Context, name string, opts metav1.GetOptions) (T, error)) (T, error) {
+	if k.cacheSynced(ctx, resource) {
+		return fromCache(namespace, name)
+	}
+	return fromAPI(ctx, name, metav1.GetOptions{})
+}
+
+// GetDeployments retrieves deployments from a namespace, or from all namespaces when
+// namespace is empty
+func (k *KubernetesService) GetDeployments(ctx context.Context, namespace string) (*appsv1.DeploymentList, error) {
+	if k.cacheSynced(ctx, cacheResourceDeployments) {
+		return k.cache.listDeployments(namespace)
+	}
+	return listWithSnapshot(ctx, k, "deployments/"+namespace, func() (*appsv1.DeploymentList, error) {
+		deployments, err := k.clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list deployments: %w", err)
+		}
+		return deployments, nil
+	})
+}
+
+// GetStatefulSets retrieves stateful sets from a namespace, or from all namespaces when
+// namespace is empty
+func (k *KubernetesService) GetStatefulSets(ctx context.Context, namespace string) (*appsv1.StatefulSetList, error) {
+	if k.cacheSynced(ctx, cacheResourceStatefulSets) {
+		return k.cache.listStatefulSets(namespace)
+	}
+	return listWithSnapshot(ctx, k, "statefulsets/"+namespace, func() (*appsv1.StatefulSetList, error) {
+		statefulSets, err := k.clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list stateful sets: %w", err)
+		}
+		return statefulSets, nil
+	})
+}
+
+// GetDaemonSets retrieves daemon sets from a namespace, or from all namespaces when
+// namespace is empty
+func (k *KubernetesService) GetDaemonSets(ctx context.Context, namespace string) (*appsv1.DaemonSetList, error) {
+	if k.cacheSynced(ctx, cacheResourceDaemonSets) {
+		return k.cache.listDaemonSets(namespace)
+	}
+	return listWithSnapshot(ctx, k, "daemonsets/"+namespace, func() (*appsv1.DaemonSetList, error) {
+		daemonSets, err := k.clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list daemon sets: %w", err)
+		}
+		return daemonSets, nil
+	})
+}
+
+// GetNodes retrieves all cluster nodes
+func (k *KubernetesService) GetNodes(ctx context.Context) (*corev1.NodeList, error) {
+	return listWithSnapshot(ctx, k, "nodes", func() (*corev1.NodeList, error) {
+		nodes, err := k.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list nodes: %w", err)
+		}
+		return nodes, nil
+	})
+}
+
+// GetPodMetrics retrieves pod usage from metrics-server for a namespace, or for all
+// namespaces when namespace is empty
+func (k *KubernetesService) GetPodMetrics(ctx context.Context, namespace string) (*unstructured.UnstructuredList, error) {
+	var list *unstructured.UnstructuredList
+	var err error
 	if namespace == "" {
-		namespace = "default"
+		list, err = k.dynamicClient.Resource(podMetricsGVR).List(ctx, metav1.ListOptions{})
+	} else {
+		list, err = k.dynamicClient.Resource(podMetricsGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
 	}
-	services, err := k.clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list services in namespace %s: %w", namespace, err)
+		return nil, fmt.Errorf("failed to list pod metrics: %w", err)
+	}
+	return list, nil
+}
+
+// GetNodeMetrics retrieves node usage from metrics-server
+func (k *KubernetesService) GetNodeMetrics(ctx context.Context) (*unstructured.UnstructuredList, error) {
+	list, err := k.dynamicClient.Resource(nodeMetricsGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list node metrics: %w", err)
+	}
+	return list, nil
+}
+
+// GetServices retrieves the services of a namespace, or of all namespaces when namespace
+// is empty
+func (k *KubernetesService) GetServices(ctx context.Context, namespace string) (*corev1.ServiceList, error) {
+	if k.cacheSynced(ctx, cacheResourceServices) {
+		return k.cache.listServices(namespace)
+	}
+	return listWithSnapshot(ctx, k, "services/"+namespace, func() (*corev1.ServiceList, error) {
+		services, err := k.clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list services in namespace %s: %w", namespace, err)
+		}
+		return services, nil
+	})
+}
+
+// GetEndpointSlices retrieves the endpoint slices of a namespace, or of all namespaces when
+// namespace is empty
+func (k *KubernetesService) GetEndpointSlices(ctx context.Context, namespace string) (*discoveryv1.EndpointSliceList, error) {
+	if k.cacheSynced(ctx, cacheResourceEndpointSlices) {
+		return k.cache.listEndpointSlices(namespace)
 	}
-	return services, nil
+	return listWithSnapshot(ctx, k, "endpointslices/"+namespace, func() (*discoveryv1.EndpointSliceList, error) {
+		slices, err := k.clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list endpoint slices in namespace %s: %w", namespace, err)
+		}
+		return slices, nil
+	})
 }
 
 // IsNamespaceAllowed checks if a namespace is allowed based on configuration
@@ -185,7 +695,7 @@ func (k *KubernetesService) IsNamespaceAllowed(namespace string) bool {
 }
 
 // GetClientset returns the underlying Kubernetes clientset
-func (k *KubernetesService) GetClientset() *kubernetes.Clientset {
+func (k *KubernetesService) GetClientset() kubernetes.Interface {
 	return k.clientset
 }
 
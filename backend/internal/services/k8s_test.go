@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+)
+
+func newTestArgoApplication(namespace, name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata": map[string]interface{}{
+			"namespace": namespace,
+			"name":      name,
+		},
+		"status": map[string]interface{}{
+			"sync":   map[string]interface{}{"status": "Synced"},
+			"health": map[string]interface{}{"status": "Healthy"},
+		},
+	}}
+}
+
+func TestIsNamespaceAllowed(t *testing.T) {
+	tests := []struct {
+		name      string
+		allowed   []string
+		exclude   []string
+		namespace string
+		want      bool
+	}{
+		{name: "no restrictions", namespace: "shop", want: true},
+		{name: "excluded", exclude: []string{"kube-system"}, namespace: "kube-system", want: false},
+		{name: "in allowed list", allowed: []string{"shop"}, namespace: "shop", want: true},
+		{name: "not in allowed list", allowed: []string{"shop"}, namespace: "other", want: false},
+		{name: "exclude wins over allow", allowed: []string{"shop"}, exclude: []string{"shop"}, namespace: "shop", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.KubernetesConfig{}
+			cfg.Namespaces.Allowed = tt.allowed
+			cfg.Namespaces.Exclude = tt.exclude
+
+			k8sService := newTestKubernetesService(cfg, nil)
+			if got := k8sService.IsNamespaceAllowed(tt.namespace); got != tt.want {
+				t.Errorf("IsNamespaceAllowed(%q) = %v, want %v", tt.namespace, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetArgoApplications(t *testing.T) {
+	k8sService := newTestKubernetesService(config.KubernetesConfig{}, nil,
+		newTestArgoApplication("argocd", "checkout"),
+		newTestArgoApplication("argocd", "payments"),
+		newTestArgoApplication("platform", "ingress"),
+	)
+	ctx := context.Background()
+
+	all, err := k8sService.GetArgoApplications(ctx, "")
+	if err != nil {
+		t.Fatalf("GetArgoApplications() error = %v", err)
+	}
+	if len(all.Items) != 3 {
+		t.Errorf("got %d applications, want 3", len(all.Items))
+	}
+
+	scoped, err := k8sService.GetArgoApplications(ctx, "argocd")
+	if err != nil {
+		t.Fatalf("GetArgoApplications(argocd) error = %v", err)
+	}
+	if len(scoped.Items) != 2 {
+		t.Errorf("got %d applications in argocd, want 2", len(scoped.Items))
+	}
+
+	app, err := k8sService.GetArgoApplication(ctx, "platform", "ingress")
+	if err != nil {
+		t.Fatalf("GetArgoApplication() error = %v", err)
+	}
+	if app.GetName() != "ingress" {
+		t.Errorf("got application %q, want ingress", app.GetName())
+	}
+
+	if _, err := k8sService.GetArgoApplication(ctx, "argocd", "missing"); err == nil {
+		t.Error("expected an error for a missing application")
+	}
+}
+
+func TestListPodsFromCache(t *testing.T) {
+	onNode := func(pod *corev1.Pod, node string) *corev1.Pod {
+		pod.Spec.NodeName = node
+		return pod
+	}
+	k8sService := newTestKubernetesService(config.KubernetesConfig{}, []runtime.Object{
+		onNode(testPod{namespace: "shop", name: "api-1", labels: map[string]string{"app": "api"}}.build(), "node-a"),
+		onNode(testPod{namespace: "shop", name: "api-2", labels: map[string]string{"app": "api"}}.build(), "node-b"),
+		onNode(testPod{namespace: "shop", name: "web-1", labels: map[string]string{"app": "web"}, phase: corev1.PodPending}.build(), "node-a"),
+	})
+	k8sService.cache = newResourceCache(k8sService.clientset, k8sService.dynamicClient, 0, false, logrus.NewEntry(newTestLogger()))
+	k8sService.cache.start(5 * time.Second)
+	defer k8sService.Stop()
+
+	tests := []struct {
+		name string
+		opts metav1.ListOptions
+		want []string
+	}{
+		{name: "everything", want: []string{"api-1", "api-2", "web-1"}},
+		{name: "label selector", opts: metav1.ListOptions{LabelSelector: "app=api"}, want: []string{"api-1", "api-2"}},
+		{name: "node", opts: metav1.ListOptions{FieldSelector: "spec.nodeName=node-a"}, want: []string{"api-1", "web-1"}},
+		{name: "both", opts: metav1.ListOptions{LabelSelector: "app=api", FieldSelector: "spec.nodeName=node-a"}, want: []string{"api-1"}},
+		{name: "phase", opts: metav1.ListOptions{FieldSelector: "status.phase!=Running"}, want: []string{"web-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pods, err := k8sService.ListPods(context.Background(), "shop", tt.opts)
+			if err != nil {
+				t.Fatalf("ListPods() error = %v", err)
+			}
+			var got []string
+			for _, pod := range pods.Items {
+				got = append(got, pod.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ListPods() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCachedListsAreCopies(t *testing.T) {
+	k8sService := newTestKubernetesService(config.KubernetesConfig{}, []runtime.Object{
+		testPod{namespace: "shop", name: "api-1", labels: map[string]string{"app": "api"}}.build(),
+	})
+	k8sService.cache = newResourceCache(k8sService.clientset, k8sService.dynamicClient, 0, false, logrus.NewEntry(newTestLogger()))
+	k8sService.cache.start(5 * time.Second)
+	defer k8sService.Stop()
+
+	pods, err := k8sService.GetPods(context.Background(), "shop")
+	if err != nil || len(pods.Items) != 1 {
+		t.Fatalf("GetPods() = %v, %v", pods, err)
+	}
+	pods.Items[0].Labels["app"] = "changed"
+
+	pods, _ = k8sService.GetPods(context.Background(), "shop")
+	if got := pods.Items[0].Labels["app"]; got != "api" {
+		t.Errorf("cached pod label = %q after changing a listed copy, want api", got)
+	}
+}
+
+func TestCachedReadsDuringWatchFailure(t *testing.T) {
+	k8sService := newTestKubernetesService(config.KubernetesConfig{}, []runtime.Object{
+		testPod{namespace: "shop", name: "api-1"}.build(),
+	})
+	// Pods list, but their watch fails
+	k8sService.clientset.(*fake.Clientset).PrependWatchReactor("pods", func(k8stesting.Action) (bool, watch.Interface, error) {
+		return true, nil, errors.New("connection refused")
+	})
+	k8sService.cache = newResourceCache(k8sService.clientset, k8sService.dynamicClient, 0, false, logrus.NewEntry(newTestLogger()))
+	k8sService.cache.start(5 * time.Second)
+	defer k8sService.Stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, stale := k8sService.cache.staleSince(cacheResourcePods); stale {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("pod watch failure was not recorded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx, staleness := models.WithStaleness(context.Background())
+	pods, err := k8sService.GetPods(ctx, "shop")
+	if err != nil || len(pods.Items) != 1 {
+		t.Fatalf("GetPods() = %v, %v", pods, err)
+	}
+	if _, stale := staleness.Oldest(); !stale {
+		t.Errorf("pods served from a cache with a failing watch were not flagged stale")
+	}
+
+	ctx, staleness = models.WithStaleness(context.Background())
+	if _, err := k8sService.GetServices(ctx, "shop"); err != nil {
+		t.Fatalf("GetServices() error = %v", err)
+	}
+	if _, stale := staleness.Oldest(); stale {
+		t.Errorf("services served from a healthy cache were flagged stale")
+	}
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s-monitor/internal/models"
+)
+
+// ErrContainerNotFound is returned when logs are requested for a container the pod does not have
+var ErrContainerNotFound = errors.New("container not found")
+
+// defaultContainerAnnotation selects the default container, as honored by kubectl
+const defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+const (
+	// maxLogLineSize bounds a single log line read from the API server
+	maxLogLineSize = 1024 * 1024
+	// maxConcurrentLogReads bounds parallel log requests when not following
+	maxConcurrentLogReads = 10
+)
+
+// LogOptions describes which container logs to read
+type LogOptions struct {
+	Container    string
+	TailLines    *int64
+	SinceSeconds *int64
+	LimitBytes   *int64
+	Previous     bool
+	Timestamps   bool
+	Follow       bool
+}
+
+// podLogOptions converts the options for a resolved container
+func (o LogOptions) podLogOptions(container string) *corev1.PodLogOptions {
+	return &corev1.PodLogOptions{
+		Container:    container,
+		TailLines:    o.TailLines,
+		SinceSeconds: o.SinceSeconds,
+		LimitBytes:   o.LimitBytes,
+		Previous:     o.Previous,
+		Timestamps:   o.Timestamps,
+		Follow:       o.Follow,
+	}
+}
+
+// ResolveLogContainer validates the requested container against the pod spec. When no
+// container is requested, the kubectl default-container annotation or the first container is used.
+func ResolveLogContainer(pod *corev1.Pod, container string) (string, error) {
+	if container == "" {
+		if annotated := pod.Annotations[defaultContainerAnnotation]; annotated != "" {
+			container = annotated
+		} else if len(pod.Spec.Containers) > 0 {
+			return pod.Spec.Containers[0].Name, nil
+		}
+	}
+
+	for _, c := range pod.Spec.Containers {
+		if c.Name == container {
+			return container, nil
+		}
+	}
+	for _, c := range pod.Spec.InitContainers {
+		if c.Name == container {
+			return container, nil
+		}
+	}
+	for _, c := range pod.Spec.EphemeralContainers {
+		if c.Name == container {
+			return container, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q in pod %s", ErrContainerNotFound, container, pod.Name)
+}
+
+// OpenPodLogs resolves the container and opens its log stream. The caller must close the stream.
+func OpenPodLogs(ctx context.Context, client KubernetesClient, namespace, name string, opts LogOptions) (io.ReadCloser, string, error) {
+	pod, err := client.GetPod(ctx, namespace, name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	container, err := ResolveLogContainer(pod, opts.Container)
+	if err != nil {
+		return nil, "", err
+	}
+
+	stream, err := client.GetPodLogs(ctx, namespace, name, opts.podLogOptions(container))
+	if err != nil {
+		return nil, container, err
+	}
+	return stream, container, nil
+}
+
+// ReadLogLines scans a log stream and calls fn for every line
+func ReadLogLines(stream io.Reader, timestamps bool, fn func(models.LogLine) error) error {
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineSize)
+
+	for scanner.Scan() {
+		if err := fn(models.ParseLogLine(scanner.Text(), timestamps)); err != nil {
+			return err
+		}
+	}
+
+	// A cancelled follow request ends the stream with a read error
+	if err := scanner.Err(); err != nil && !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("failed to read log stream: %w", err)
+	}
+	return nil
+}
+
+// LogFilter selects log lines by substring and/or regular expression
+type LogFilter struct {
+	Contains string
+	Pattern  *regexp.Regexp
+}
+
+// Match reports whether a log line passes the filter
+func (f LogFilter) Match(line models.LogLine) bool {
+	if f.Contains != "" && !strings.Contains(line.Message, f.Contains) {
+		return false
+	}
+	if f.Pattern != nil && !f.Pattern.MatchString(line.Message) {
+		return false
+	}
+	return true
+}
+
+// StreamLogs reads several container log streams of one namespace concurrently and calls fn,
+// serially, for every line that passes the filter, with the pod and container set on the line.
+// Streams that fail are reported in the returned map keyed by pod/container; an error is
+// returned when fn fails or when no stream could be read at all.
+func StreamLogs(ctx context.Context, client KubernetesClient, namespace string, sources []models.LogSource, opts LogOptions, filter LogFilter, fn func(models.LogLine) error) (map[string]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lines := make(chan models.LogLine, 256)
+
+	var mu sync.Mutex
+	var firstErr error
+	sourceErrors := make(map[string]string)
+	recordErr := func(source models.LogSource, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		sourceErrors[source.Pod+"/"+source.Container] = err.Error()
+	}
+
+	// Following keeps every stream open, otherwise reads are bounded
+	limit := maxConcurrentLogReads
+	if opts.Follow {
+		limit = len(sources)
+	}
+	sem := make(chan struct{}, max(limit, 1))
+
+	var wg sync.WaitGroup
+	for _, source := range sources {
+		wg.Add(1)
+		go func(source models.LogSource) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			stream, err := client.GetPodLogs(ctx, namespace, source.Pod, opts.podLogOptions(source.Container))
+			if err != nil {
+				recordErr(source, err)
+				return
+			}
+			defer stream.Close()
+
+			err = ReadLogLines(stream, opts.Timestamps, func(line models.LogLine) error {
+				if !filter.Match(line) {
+					return nil
+				}
+				line.Pod = source.Pod
+				line.Container = source.Container
+				select {
+				case lines <- line:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			})
+			if err != nil && ctx.Err() == nil {
+				recordErr(source, err)
+			}
+		}(source)
+	}
+
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+
+	var fnErr error
+	for line := range lines {
+		if fnErr != nil {
+			continue
+		}
+		if fnErr = fn(line); fnErr != nil {
+			cancel()
+		}
+	}
+
+	if fnErr != nil {
+		return sourceErrors, fnErr
+	}
+	if len(sources) > 0 && len(sourceErrors) == len(sources) {
+		return sourceErrors, firstErr
+	}
+	if len(sourceErrors) == 0 {
+		return nil, nil
+	}
+	return sourceErrors, nil
+}
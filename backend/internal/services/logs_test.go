@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+)
+
+func TestResolveLogContainer(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-1"},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "migrate"}},
+			Containers:     []corev1.Container{{Name: "api"}, {Name: "proxy"}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		annotation string
+		container  string
+		want       string
+		wantErr    bool
+	}{
+		{name: "first container by default", want: "api"},
+		{name: "default container annotation", annotation: "proxy", want: "proxy"},
+		{name: "explicit container", container: "proxy", want: "proxy"},
+		{name: "init container", container: "migrate", want: "migrate"},
+		{name: "unknown container", container: "sidecar", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := pod.DeepCopy()
+			if tt.annotation != "" {
+				p.Annotations = map[string]string{defaultContainerAnnotation: tt.annotation}
+			}
+
+			got, err := ResolveLogContainer(p, tt.container)
+			if tt.wantErr {
+				if !errors.Is(err, ErrContainerNotFound) {
+					t.Errorf("error = %v, want ErrContainerNotFound", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ResolveLogContainer() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadLogLines(t *testing.T) {
+	input := "2025-01-02T03:04:05.123456789Z starting server\n" +
+		"2025-01-02T03:04:06Z listening on :8080\n" +
+		"not a timestamp line\n"
+
+	var lines []models.LogLine
+	err := ReadLogLines(strings.NewReader(input), true, func(line models.LogLine) error {
+		lines = append(lines, line)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ReadLogLines() error = %v", err)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3", len(lines))
+	}
+
+	want := time.Date(2025, 1, 2, 3, 4, 5, 123456789, time.UTC)
+	if lines[0].Timestamp == nil || !lines[0].Timestamp.Equal(want) || lines[0].Message != "starting server" {
+		t.Errorf("unexpected first line %+v", lines[0])
+	}
+	if lines[1].Message != "listening on :8080" {
+		t.Errorf("unexpected second line %+v", lines[1])
+	}
+	if lines[2].Timestamp != nil || lines[2].Message != "not a timestamp line" {
+		t.Errorf("lines without a timestamp must be kept verbatim, got %+v", lines[2])
+	}
+
+	stop := errors.New("stop")
+	count := 0
+	err = ReadLogLines(strings.NewReader(input), false, func(models.LogLine) error {
+		count++
+		return stop
+	})
+	if !errors.Is(err, stop) || count != 1 {
+		t.Errorf("callback errors must stop reading, got %v after %d lines", err, count)
+	}
+}
+
+func TestStreamLogs(t *testing.T) {
+	api1 := testPod{namespace: "shop", name: "api-1", labels: map[string]string{"app": "api"}, phase: corev1.PodRunning, ready: true}.build()
+	api1.Spec.Containers = []corev1.Container{{Name: "api"}, {Name: "proxy"}}
+	api2 := testPod{namespace: "shop", name: "api-2", labels: map[string]string{"app": "api"}, phase: corev1.PodRunning, ready: true}.build()
+	api2.Spec.Containers = []corev1.Container{{Name: "api"}}
+	web := testPod{namespace: "shop", name: "web-1", labels: map[string]string{"app": "web"}, phase: corev1.PodRunning, ready: true}.build()
+	web.Spec.Containers = []corev1.Container{{Name: "web"}}
+
+	idle := newTestDeployment("shop", "idle", 0, 0, map[string]string{"app": "idle"})
+
+	k8s := newTestKubernetesService(config.KubernetesConfig{}, []runtime.Object{api1, api2, web, idle})
+	apps := NewApplicationService(k8s, nil, newTestLogger())
+	ctx := context.Background()
+
+	sources, err := apps.GetApplicationLogSources(ctx, "shop", "api", "")
+	if err != nil {
+		t.Fatalf("GetApplicationLogSources() error = %v", err)
+	}
+	want := []models.LogSource{{Pod: "api-1", Container: "api"}, {Pod: "api-1", Container: "proxy"}, {Pod: "api-2", Container: "api"}}
+	if !reflect.DeepEqual(sources, want) {
+		t.Fatalf("sources = %+v, want %+v", sources, want)
+	}
+
+	if _, err := apps.GetApplicationLogSources(ctx, "shop", "missing", ""); !errors.Is(err, ErrApplicationNotFound) {
+		t.Errorf("unknown application error = %v, want ErrApplicationNotFound", err)
+	}
+	if _, err := apps.GetApplicationLogSources(ctx, "shop", "api", "sidecar"); !errors.Is(err, ErrContainerNotFound) {
+		t.Errorf("unknown container error = %v, want ErrContainerNotFound", err)
+	}
+	if sources, err := apps.GetApplicationLogSources(ctx, "shop", "idle", ""); err != nil || len(sources) != 0 {
+		t.Errorf("scaled to zero application = %v, %v, want no sources", sources, err)
+	}
+
+	// The fake clientset answers every log request with "fake logs"
+	tests := []struct {
+		name   string
+		filter LogFilter
+		want   int
+	}{
+		{name: "no filter", want: 3},
+		{name: "substring", filter: LogFilter{Contains: "fake"}, want: 3},
+		{name: "regex", filter: LogFilter{Pattern: regexp.MustCompile(`^fake\s+logs$`)}, want: 3},
+		{name: "no match", filter: LogFilter{Contains: "panic"}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := make(map[models.LogSource]bool)
+			sourceErrors, err := StreamLogs(ctx, k8s, "shop", sources, LogOptions{}, tt.filter, func(line models.LogLine) error {
+				seen[models.LogSource{Pod: line.Pod, Container: line.Container}] = true
+				return nil
+			})
+			if err != nil || len(sourceErrors) != 0 {
+				t.Fatalf("StreamLogs() = %v, %v", sourceErrors, err)
+			}
+			if len(seen) != tt.want {
+				t.Errorf("got lines from %d sources, want %d", len(seen), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s-monitor/internal/models"
+)
+
+// PodMetrics holds the container usage reported by metrics-server
+type PodMetrics struct {
+	// Available is false when the metrics.k8s.io API could not be queried,
+	// typically because metrics-server is not installed
+	Available bool
+
+	containers map[string]map[string]models.ResourceQuantities // namespace/pod -> container -> usage
+}
+
+// GetPodMetrics fetches container usage for a namespace, or for all namespaces when empty.
+// Failures are not errors: the returned metrics report Available false instead.
+func GetPodMetrics(ctx context.Context, client KubernetesClient, namespace string, logger *logrus.Entry) PodMetrics {
+	list, err := client.GetPodMetrics(ctx, namespace)
+	if err != nil {
+		logMetricsUnavailable(logger, err)
+		return PodMetrics{}
+	}
+
+	metrics := PodMetrics{
+		Available:  true,
+		containers: make(map[string]map[string]models.ResourceQuantities, len(list.Items)),
+	}
+	for _, item := range list.Items {
+		containers, _, _ := unstructured.NestedSlice(item.Object, "containers")
+
+		usage := make(map[string]models.ResourceQuantities, len(containers))
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(container, "name")
+			usage[name] = parseUsage(container)
+		}
+		metrics.containers[item.GetNamespace()+"/"+item.GetName()] = usage
+	}
+
+	return metrics
+}
+
+// Apply attaches usage to a pod converted with models.FromK8sPod
+func (m PodMetrics) Apply(pod *models.PodStatus) {
+	pod.Resources.MetricsAvailable = m.Available
+	for i := range pod.Containers {
+		pod.Containers[i].Resources.MetricsAvailable = m.Available
+	}
+
+	usage, ok := m.containers[pod.Namespace+"/"+pod.Name]
+	if !ok {
+		// No sample yet, e.g. for pods that just started
+		return
+	}
+
+	total := models.ResourceQuantities{}
+	for i := range pod.Containers {
+		if containerUsage, ok := usage[pod.Containers[i].Name]; ok {
+			containerUsage := containerUsage
+			pod.Containers[i].Resources.Usage = &containerUsage
+		}
+	}
+	for _, containerUsage := range usage {
+		total.Add(containerUsage)
+	}
+	pod.Resources.Usage = &total
+}
+
+// GetNodes lists the cluster nodes with their capacity, their usage from metrics-server,
+// and the requests and limits of the pods scheduled on them
+func GetNodes(ctx context.Context, client KubernetesClient, logger *logrus.Entry) (*models.NodeListResponse, error) {
+	nodeList, err := client.GetNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	podList, err := client.GetAllPods(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pods: %w", err)
+	}
+
+	// Sum the requests and limits of the pods that still hold resources on each node
+	scheduled := make(map[string]*models.NodeInfo, len(nodeList.Items))
+	nodes := make([]models.NodeInfo, 0, len(nodeList.Items))
+	for _, node := range nodeList.Items {
+		nodes = append(nodes, models.NodeInfo{
+			Name:           node.Name,
+			Cluster:        client.ClusterName(),
+			Ready:          isNodeReady(&node),
+			KubeletVersion: node.Status.NodeInfo.KubeletVersion,
+			Capacity:       models.QuantitiesFromList(node.Status.Capacity),
+			Allocatable:    models.QuantitiesFromList(node.Status.Allocatable),
+		})
+	}
+	for i := range nodes {
+		scheduled[nodes[i].Name] = &nodes[i]
+	}
+	for _, pod := range podList.Items {
+		node, ok := scheduled[pod.Spec.NodeName]
+		if !ok || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		node.PodCount++
+		for _, container := range pod.Spec.Containers {
+			node.Resources.Requests.Add(models.QuantitiesFromList(container.Resources.Requests))
+			node.Resources.Limits.Add(models.QuantitiesFromList(container.Resources.Limits))
+		}
+	}
+
+	metricsAvailable := true
+	metricsList, err := client.GetNodeMetrics(ctx)
+	if err != nil {
+		logMetricsUnavailable(logger, err)
+		metricsAvailable = false
+	} else {
+		for _, item := range metricsList.Items {
+			if node, ok := scheduled[item.GetName()]; ok {
+				usage := parseUsage(item.Object)
+				node.Resources.Usage = &usage
+			}
+		}
+	}
+	for i := range nodes {
+		nodes[i].Resources.MetricsAvailable = metricsAvailable
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	return &models.NodeListResponse{
+		Nodes:            nodes,
+		Total:            len(nodes),
+		MetricsAvailable: metricsAvailable,
+	}, nil
+}
+
+// parseUsage reads the cpu and memory quantities of a metrics object's usage field
+func parseUsage(object map[string]interface{}) models.ResourceQuantities {
+	var usage models.ResourceQuantities
+	if value, found, _ := unstructured.NestedString(object, "usage", "cpu"); found {
+		if quantity, err := resource.ParseQuantity(value); err == nil {
+			usage.CPU = quantity.MilliValue()
+		}
+	}
+	if value, found, _ := unstructured.NestedString(object, "usage", "memory"); found {
+		if quantity, err := resource.ParseQuantity(value); err == nil {
+			usage.Memory = quantity.Value()
+		}
+	}
+	return usage
+}
+
+// isNodeReady reports whether the node's Ready condition is true
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// logMetricsUnavailable logs a failed metrics query. A missing metrics API is expected on
+// clusters without metrics-server and is only logged at debug level.
+func logMetricsUnavailable(logger *logrus.Entry, err error) {
+	if apierrors.IsNotFound(err) {
+		logger.WithError(err).Debug("Metrics API not available")
+		return
+	}
+	logger.WithError(err).Warn("Failed to query metrics API")
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+)
+
+func newTestPodMetrics(namespace, name string, containers map[string][2]string) *unstructured.Unstructured {
+	var items []interface{}
+	for container, usage := range containers {
+		items = append(items, map[string]interface{}{
+			"name":  container,
+			"usage": map[string]interface{}{"cpu": usage[0], "memory": usage[1]},
+		})
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "metrics.k8s.io/v1beta1",
+		"kind":       "PodMetrics",
+		"metadata":   map[string]interface{}{"namespace": namespace, "name": name},
+		"containers": items,
+	}}
+}
+
+func TestApplicationResourceUsage(t *testing.T) {
+	pod := testPod{namespace: "shop", name: "api-1", labels: map[string]string{"app": "api"}}.build()
+	pod.Spec.Containers = []corev1.Container{
+		{
+			Name: "api",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("128Mi")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("256Mi")},
+			},
+		},
+		{Name: "proxy"},
+	}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "api", Ready: true}, {Name: "proxy", Ready: true}}
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), testListKinds)
+	metrics := newTestPodMetrics("shop", "api-1", map[string][2]string{"api": {"250m", "100Mi"}, "proxy": {"5m", "10Mi"}})
+	if err := dynamicClient.Tracker().Create(podMetricsGVR, metrics, "shop"); err != nil {
+		t.Fatalf("failed to add pod metrics: %v", err)
+	}
+
+	k8s := NewKubernetesServiceForClients("test", fake.NewSimpleClientset(pod), dynamicClient, config.KubernetesConfig{}, newTestLogger())
+	response, err := NewApplicationService(k8s, nil, newTestLogger()).GetApplicationsByNamespace(context.Background(), "shop")
+	if err != nil {
+		t.Fatalf("GetApplicationsByNamespace() error = %v", err)
+	}
+	if !response.MetricsAvailable || len(response.Applications) != 1 {
+		t.Fatalf("unexpected response %+v", response)
+	}
+
+	app := response.Applications[0]
+	want := models.ResourceUsage{
+		MetricsAvailable: true,
+		Usage:            &models.ResourceQuantities{CPU: 255, Memory: 110 * 1024 * 1024},
+		Requests:         models.ResourceQuantities{CPU: 100, Memory: 128 * 1024 * 1024},
+		Limits:           models.ResourceQuantities{CPU: 1000, Memory: 256 * 1024 * 1024},
+	}
+	if app.Resources.MetricsAvailable != want.MetricsAvailable || app.Resources.Usage == nil ||
+		*app.Resources.Usage != *want.Usage || app.Resources.Requests != want.Requests || app.Resources.Limits != want.Limits {
+		t.Errorf("application resources = %+v, want %+v", app.Resources, want)
+	}
+
+	container := app.Pods[0].Containers[0]
+	if container.Resources.Usage == nil || container.Resources.Usage.CPU != 250 || container.Resources.Requests.CPU != 100 {
+		t.Errorf("container resources = %+v", container.Resources)
+	}
+}
+
+func TestResourceUsageWithoutMetricsServer(t *testing.T) {
+	pod := testPod{namespace: "shop", name: "api-1", labels: map[string]string{"app": "api"}}.build()
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), testListKinds)
+	dynamicClient.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(podMetricsGVR.GroupResource(), "")
+	})
+
+	k8s := NewKubernetesServiceForClients("test", fake.NewSimpleClientset(pod), dynamicClient, config.KubernetesConfig{}, newTestLogger())
+	response, err := NewApplicationService(k8s, nil, newTestLogger()).GetApplicationsByNamespace(context.Background(), "shop")
+	if err != nil {
+		t.Fatalf("GetApplicationsByNamespace() error = %v", err)
+	}
+
+	if response.MetricsAvailable {
+		t.Error("MetricsAvailable = true without metrics-server")
+	}
+	resources := response.Applications[0].Resources
+	if resources.MetricsAvailable || resources.Usage != nil {
+		t.Errorf("application resources = %+v, want no usage", resources)
+	}
+}
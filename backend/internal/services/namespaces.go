@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxConcurrentPodCounts bounds the namespaces counted at once when pods cannot be listed
+// across all namespaces
+const maxConcurrentPodCounts = 8
+
+// CountPods counts the pods of the given namespaces with a single list of all pods. When
+// that is not allowed, such as with RBAC scoped to namespaces, each namespace is listed on
+// its own with bounded parallelism. Namespaces whose pods cannot be listed are left out.
+func CountPods(ctx context.Context, client KubernetesClient, namespaces []string, logger *logrus.Entry) map[string]int {
+	counts := make(map[string]int, len(namespaces))
+	for _, namespace := range namespaces {
+		counts[namespace] = 0
+	}
+
+	podList, err := client.GetAllPods(ctx)
+	if err == nil {
+		for _, pod := range podList.Items {
+			if _, ok := counts[pod.Namespace]; ok {
+				counts[pod.Namespace]++
+			}
+		}
+		return counts
+	}
+	logger.WithError(err).Debug("Failed to list pods of all namespaces, counting each namespace")
+
+	var mu sync.Mutex
+	sem := make(chan struct{}, maxConcurrentPodCounts)
+
+	var wg sync.WaitGroup
+	for _, namespace := range namespaces {
+		wg.Add(1)
+		go func(namespace string) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				mu.Lock()
+				delete(counts, namespace)
+				mu.Unlock()
+				return
+			}
+
+			podList, err := client.GetPods(ctx, namespace)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				delete(counts, namespace)
+				return
+			}
+			counts[namespace] = len(podList.Items)
+		}(namespace)
+	}
+	wg.Wait()
+
+	return counts
+}
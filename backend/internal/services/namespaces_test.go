@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"k8s-monitor/internal/config"
+)
+
+func TestCountPodsLeavesOutCancelledNamespaces(t *testing.T) {
+	var objects []runtime.Object
+	var namespaces []string
+	for i := 0; i < maxConcurrentPodCounts+2; i++ {
+		namespace := fmt.Sprintf("team-%d", i)
+		namespaces = append(namespaces, namespace)
+		objects = append(objects, testPod{namespace: namespace, name: "api-1", ready: true}.build())
+	}
+	k8sService := newTestKubernetesService(config.KubernetesConfig{}, objects)
+
+	// Pods can only be listed per namespace, and the request ends during the first lists
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clientset := k8sService.GetClientset().(*fake.Clientset)
+	clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "" {
+			return true, nil, apierrors.NewForbidden(corev1.Resource("pods"), "", nil)
+		}
+		cancel()
+		time.Sleep(20 * time.Millisecond)
+		return false, nil, nil
+	})
+
+	counts := CountPods(ctx, k8sService, namespaces, logrus.NewEntry(newTestLogger()))
+	if len(counts) == len(namespaces) {
+		t.Fatalf("CountPods() counted every namespace after the context was cancelled")
+	}
+	for namespace, count := range counts {
+		if count != 1 {
+			t.Errorf("counts[%s] = %d, want 1 or left out", namespace, count)
+		}
+	}
+}
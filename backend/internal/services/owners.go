@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s-monitor/internal/models"
+)
+
+// maxOwnerDepth bounds owner chains, guarding against reference cycles
+const maxOwnerDepth = 10
+
+// ownerKey identifies an owner looked up by the resolver
+type ownerKey struct {
+	namespace string
+	kind      string
+	name      string
+}
+
+// podRef identifies a pod
+type podRef struct {
+	namespace string
+	name      string
+}
+
+// podRoots maps pods to their root owners
+type podRoots map[podRef]models.ObjectReference
+
+// get returns the root owner of a pod, or nil when it has no owner
+func (r podRoots) get(pod corev1.Pod) *models.ObjectReference {
+	if root, ok := r[podRef{namespace: pod.Namespace, name: pod.Name}]; ok {
+		return &root
+	}
+	return nil
+}
+
+// ownerResolver walks owner references up to the root owner. Each owner is looked up
+// once per resolver, so a resolver should live for a single request.
+type ownerResolver struct {
+	client KubernetesClient
+	owners map[ownerKey][]metav1.OwnerReference
+	logger *logrus.Entry
+}
+
+// newOwnerResolver creates a resolver reading owners through the client
+func newOwnerResolver(client KubernetesClient, logger *logrus.Entry) *ownerResolver {
+	return &ownerResolver{
+		client: client,
+		owners: make(map[ownerKey][]metav1.OwnerReference),
+		logger: logger,
+	}
+}
+
+// seed records the owner references of an object listed in bulk, so that resolving it
+// needs no lookup
+func (r *ownerResolver) seed(namespace, kind, name string, refs []metav1.OwnerReference) {
+	r.owners[ownerKey{namespace: namespace, kind: kind, name: name}] = refs
+}
+
+// chain returns the owners of an object in a namespace, from its controller to the root
+// owner. Owners that cannot be read end the chain.
+func (r *ownerResolver) chain(ctx context.Context, namespace string, refs []metav1.OwnerReference) []models.ObjectReference {
+	var chain []models.ObjectReference
+	seen := make(map[ownerKey]bool)
+
+	for len(chain) < maxOwnerDepth {
+		owner := controllerRef(refs)
+		if owner == nil {
+			break
+		}
+		key := ownerKey{namespace: namespace, kind: owner.Kind, name: owner.Name}
+		if seen[key] {
+			break
+		}
+		seen[key] = true
+		chain = append(chain, models.ObjectReference{Kind: owner.Kind, Name: owner.Name})
+
+		parents, cached := r.owners[key]
+		if !cached {
+			var err error
+			parents, err = r.client.GetOwnerReferences(ctx, namespace, *owner)
+			if err != nil {
+				r.logger.WithError(err).WithField("owner", owner.Kind+"/"+owner.Name).Debug("Failed to resolve owner")
+			}
+			r.owners[key] = parents
+		}
+		refs = parents
+	}
+	return chain
+}
+
+// root returns the root owner of an object, or false when it has no owner. Without a
+// resolver the controller is taken as the root.
+func (r *ownerResolver) root(ctx context.Context, namespace string, refs []metav1.OwnerReference) (models.ObjectReference, bool) {
+	if r == nil {
+		if owner := controllerRef(refs); owner != nil {
+			return models.ObjectReference{Kind: owner.Kind, Name: owner.Name}, true
+		}
+		return models.ObjectReference{}, false
+	}
+
+	chain := r.chain(ctx, namespace, refs)
+	if len(chain) == 0 {
+		return models.ObjectReference{}, false
+	}
+	return chain[len(chain)-1], true
+}
+
+// controllerRef returns the controlling owner reference, or the first one when none is
+// marked as the controller
+func controllerRef(refs []metav1.OwnerReference) *metav1.OwnerReference {
+	for i := range refs {
+		if refs[i].Controller != nil && *refs[i].Controller {
+			return &refs[i]
+		}
+	}
+	if len(refs) > 0 {
+		return &refs[0]
+	}
+	return nil
+}
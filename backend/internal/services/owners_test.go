@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+)
+
+// ownedBy returns a controller owner reference
+func ownedBy(kind, name string) []metav1.OwnerReference {
+	isController := true
+	return []metav1.OwnerReference{{APIVersion: testOwnerAPIVersions[kind], Kind: kind, Name: name, Controller: &isController}}
+}
+
+func newTestReplicaSet(name string, owners []metav1.OwnerReference) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name, OwnerReferences: owners}}
+}
+
+func TestOwnerResolverChain(t *testing.T) {
+	objects := []runtime.Object{
+		newTestReplicaSet("canary-6c8d7", ownedBy("Rollout", "canary")),
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "report-28901", OwnerReferences: ownedBy("CronJob", "report")}},
+		// Owner references can form a cycle
+		newTestReplicaSet("loop-a", ownedBy("ReplicaSet", "loop-b")),
+		newTestReplicaSet("loop-b", ownedBy("ReplicaSet", "loop-a")),
+	}
+	k8sService := newTestKubernetesService(config.KubernetesConfig{}, objects)
+
+	rollout := &unstructured.Unstructured{}
+	rollout.SetAPIVersion("argoproj.io/v1alpha1")
+	rollout.SetKind("Rollout")
+	rollout.SetNamespace("shop")
+	rollout.SetName("canary")
+	rolloutGVR := schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"}
+	if err := k8sService.dynamicClient.(*dynamicfake.FakeDynamicClient).Tracker().Create(rolloutGVR, rollout, "shop"); err != nil {
+		t.Fatalf("failed to create rollout: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		refs []metav1.OwnerReference
+		want []models.ObjectReference
+	}{
+		{
+			name: "custom resource owner",
+			refs: ownedBy("ReplicaSet", "canary-6c8d7"),
+			want: []models.ObjectReference{{Kind: "ReplicaSet", Name: "canary-6c8d7"}, {Kind: "Rollout", Name: "canary"}},
+		},
+		{
+			name: "cron job",
+			refs: ownedBy("Job", "report-28901"),
+			want: []models.ObjectReference{{Kind: "Job", Name: "report-28901"}, {Kind: "CronJob", Name: "report"}},
+		},
+		{
+			name: "missing owner ends the chain",
+			refs: ownedBy("ReplicaSet", "gone-5d4f"),
+			want: []models.ObjectReference{{Kind: "ReplicaSet", Name: "gone-5d4f"}},
+		},
+		{
+			name: "cycle",
+			refs: ownedBy("ReplicaSet", "loop-a"),
+			want: []models.ObjectReference{{Kind: "ReplicaSet", Name: "loop-a"}, {Kind: "ReplicaSet", Name: "loop-b"}},
+		},
+		{
+			name: "no owner",
+		},
+	}
+
+	resolver := newOwnerResolver(k8sService, logrus.NewEntry(newTestLogger()))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolver.chain(context.Background(), "shop", tt.refs)
+			if len(got) != len(tt.want) {
+				t.Fatalf("chain() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("chain()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	// Custom resources are read through the dynamic client
+	if _, err := k8sService.GetOwnerReferences(context.Background(), "shop", ownedBy("Rollout", "canary")[0]); err != nil {
+		t.Errorf("GetOwnerReferences(Rollout) error = %v", err)
+	}
+}
+
+func TestOwnerResolverReadsWorkloadsFromCache(t *testing.T) {
+	objects := []runtime.Object{
+		newTestReplicaSet("api-6c8d7", ownedBy("Deployment", "api")),
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "api"}},
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "report-28901", OwnerReferences: ownedBy("CronJob", "report")}},
+	}
+	k8sService := newTestKubernetesService(config.KubernetesConfig{}, objects)
+	k8sService.cache = newResourceCache(k8sService.clientset, k8sService.dynamicClient, 0, false, logrus.NewEntry(newTestLogger()))
+	k8sService.cache.start(5 * time.Second)
+	defer k8sService.Stop()
+
+	var gets atomic.Int32
+	countGets := func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetVerb() == "get" {
+			gets.Add(1)
+		}
+		return false, nil, nil
+	}
+	k8sService.clientset.(*fake.Clientset).PrependReactor("*", "*", countGets)
+	k8sService.dynamicClient.(*dynamicfake.FakeDynamicClient).PrependReactor("*", "*", countGets)
+
+	resolver := newOwnerResolver(k8sService, logrus.NewEntry(newTestLogger()))
+	if root, ok := resolver.root(context.Background(), "shop", ownedBy("ReplicaSet", "api-6c8d7")); !ok || root.Kind != "Deployment" || root.Name != "api" {
+		t.Errorf("root() = %v, %v, want Deployment/api", root, ok)
+	}
+	if root, ok := resolver.root(context.Background(), "shop", ownedBy("Job", "report-28901")); !ok || root.Kind != "CronJob" || root.Name != "report" {
+		t.Errorf("root() = %v, %v, want CronJob/report", root, ok)
+	}
+	if n := gets.Load(); n != 0 {
+		t.Errorf("resolving owners made %d API requests, want 0", n)
+	}
+}
+
+func TestPodListOwnersListsOwnersOnce(t *testing.T) {
+	objects := []runtime.Object{
+		newTestReplicaSet("api-6c8d7", ownedBy("Deployment", "api")),
+		newTestReplicaSet("api-5f9b4", ownedBy("Deployment", "api")),
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "api"}},
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "report-28901", OwnerReferences: ownedBy("CronJob", "report")}},
+	}
+	k8sService := newTestKubernetesService(config.KubernetesConfig{}, objects)
+	service := NewApplicationService(k8sService, nil, newTestLogger())
+
+	var gets atomic.Int32
+	k8sService.clientset.(*fake.Clientset).PrependReactor("get", "*", func(k8stesting.Action) (bool, runtime.Object, error) {
+		gets.Add(1)
+		return false, nil, nil
+	})
+
+	pods := []testPod{
+		{namespace: "shop", name: "api-6c8d7-x1", ownerKind: "ReplicaSet", ownerName: "api-6c8d7"},
+		{namespace: "shop", name: "api-5f9b4-x2", ownerKind: "ReplicaSet", ownerName: "api-5f9b4"},
+		{namespace: "shop", name: "report-28901-x3", ownerKind: "Job", ownerName: "report-28901"},
+	}
+	want := []string{"Deployment/api", "Deployment/api", "CronJob/report"}
+
+	owners := service.PodListOwners(context.Background(), "shop")
+	for i, p := range pods {
+		pod := p.build()
+		status := models.FromK8sPod(pod)
+		owners.Apply(context.Background(), *pod, &status)
+		if got := status.RootOwnerKind + "/" + status.RootOwnerName; got != want[i] {
+			t.Errorf("%s root owner = %s, want %s", pod.Name, got, want[i])
+		}
+	}
+	if n := gets.Load(); n != 0 {
+		t.Errorf("describing listed pods made %d GET requests, want 0", n)
+	}
+}
+
+func TestPodOwnersApply(t *testing.T) {
+	objects := []runtime.Object{
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "report-28901", OwnerReferences: ownedBy("CronJob", "report")}},
+	}
+	k8sService := newTestKubernetesService(config.KubernetesConfig{}, objects)
+	service := NewApplicationService(k8sService, nil, newTestLogger())
+
+	pod := testPod{namespace: "shop", name: "report-28901-x7k2p", ownerKind: "Job", ownerName: "report-28901"}.build()
+	status := models.FromK8sPod(pod)
+	service.PodOwners().Apply(context.Background(), *pod, &status)
+
+	if status.RootOwnerKind != "CronJob" || status.RootOwnerName != "report" {
+		t.Errorf("root owner = %s/%s, want CronJob/report", status.RootOwnerKind, status.RootOwnerName)
+	}
+	if status.Application != "report" {
+		t.Errorf("Application = %q, want report", status.Application)
+	}
+}
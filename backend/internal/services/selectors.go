@@ -0,0 +1,37 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// podFields returns the fields of a pod that the API server supports in field selectors
+func podFields(pod *corev1.Pod) fields.Set {
+	return fields.Set{
+		"metadata.name":            pod.Name,
+		"metadata.namespace":       pod.Namespace,
+		"spec.nodeName":            pod.Spec.NodeName,
+		"spec.restartPolicy":       string(pod.Spec.RestartPolicy),
+		"spec.schedulerName":       pod.Spec.SchedulerName,
+		"spec.serviceAccountName":  pod.Spec.ServiceAccountName,
+		"spec.hostNetwork":         strconv.FormatBool(pod.Spec.HostNetwork),
+		"status.phase":             string(pod.Status.Phase),
+		"status.podIP":             pod.Status.PodIP,
+		"status.nominatedNodeName": pod.Status.NominatedNodeName,
+	}
+}
+
+// ValidatePodFieldSelector checks that a field selector only uses supported pod fields, so
+// that it selects the same pods from the cache as from the API server
+func ValidatePodFieldSelector(selector fields.Selector) error {
+	supported := podFields(&corev1.Pod{})
+	for _, requirement := range selector.Requirements() {
+		if _, ok := supported[requirement.Field]; !ok {
+			return fmt.Errorf("field %q is not supported in pod field selectors", requirement.Field)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"k8s-monitor/internal/models"
+	"k8s-monitor/pkg/utils"
+)
+
+// maxSnapshots bounds the lists kept per cluster. Lists with selectors make the number
+// of keys open-ended, so the oldest snapshot is dropped beyond it.
+const maxSnapshots = 256
+
+// snapshot is the last list of a resource read from the API server
+type snapshot struct {
+	list      runtime.Object
+	fetchedAt time.Time
+}
+
+// snapshotStore keeps the last good list of each resource, so that reads can still be
+// answered while the API server is unreachable
+type snapshotStore struct {
+	mu        sync.Mutex
+	snapshots map[string]snapshot // by resource, namespace and selectors
+	now       func() time.Time
+}
+
+// newSnapshotStore creates an empty snapshot store
+func newSnapshotStore() *snapshotStore {
+	return &snapshotStore{
+		snapshots: make(map[string]snapshot),
+		now:       time.Now,
+	}
+}
+
+// save keeps a copy of a list as the snapshot of a key, as callers may change the list
+func (s *snapshotStore) save(key string, list runtime.Object) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.snapshots[key]; !exists && len(s.snapshots) >= maxSnapshots {
+		oldest := ""
+		for k, snap := range s.snapshots {
+			if oldest == "" || snap.fetchedAt.Before(s.snapshots[oldest].fetchedAt) {
+				oldest = k
+			}
+		}
+		delete(s.snapshots, oldest)
+	}
+	s.snapshots[key] = snapshot{list: list.DeepCopyObject(), fetchedAt: s.now()}
+}
+
+// load returns the snapshot of a key, if any
+func (s *snapshotStore) load(key string) (snapshot, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, ok := s.snapshots[key]
+	return snap, ok
+}
+
+// listWithSnapshot lists a resource from the API server and keeps the result. When the
+// API server cannot be reached, the last good list is returned instead if the context
+// records staleness, which is then flagged on the response. Background readers, whose
+// contexts do not, keep seeing the error.
+func listWithSnapshot[L runtime.Object](ctx context.Context, k *KubernetesService, key string, list func() (L, error)) (L, error) {
+	result, err := list()
+	if err == nil {
+		k.snapshots.save(key, result)
+		return result, nil
+	}
+
+	staleness := models.StalenessFrom(ctx)
+	if staleness == nil || !isUnreachable(err) {
+		return result, err
+	}
+	snap, ok := k.snapshots.load(key)
+	if !ok {
+		return result, err
+	}
+
+	staleness.Record(snap.fetchedAt)
+	utils.WithCluster(k.logger, k.name).WithError(err).
+		WithFields(logrus.Fields{"snapshot": key, "fetched_at": snap.fetchedAt}).
+		Warn("Cluster unreachable, serving last known data")
+	return snap.list.DeepCopyObject().(L), nil
+}
+
+// isUnreachable reports whether an error means that the API server could not be reached
+// or did not answer in time, rather than that it refused the request
+func isUnreachable(err error) bool {
+	if apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err) || apierrors.IsServiceUnavailable(err) {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
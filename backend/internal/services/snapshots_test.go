@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+)
+
+func TestIsUnreachable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "connection refused", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, want: true},
+		{name: "deadline", err: fmt.Errorf("failed to list pods: %w", context.DeadlineExceeded), want: true},
+		{name: "server timeout", err: apierrors.NewServerTimeout(corev1.Resource("pods"), "list", 1), want: true},
+		{name: "unavailable", err: apierrors.NewServiceUnavailable("etcd down"), want: true},
+		{name: "forbidden", err: apierrors.NewForbidden(corev1.Resource("pods"), "", nil)},
+		{name: "other", err: errors.New("boom")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isUnreachable(tt.err); got != tt.want {
+				t.Errorf("isUnreachable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListWithSnapshot(t *testing.T) {
+	k8sService := newTestKubernetesService(config.KubernetesConfig{}, []runtime.Object{
+		testPod{namespace: "shop", name: "api-1", ready: true}.build(),
+	})
+	fetchedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	k8sService.snapshots.now = func() time.Time { return fetchedAt }
+
+	// Prime the snapshot while the cluster is reachable. Changing the list read does not
+	// change the snapshot.
+	primed, err := k8sService.GetPods(context.Background(), "shop")
+	if err != nil {
+		t.Fatalf("GetPods() error = %v", err)
+	}
+	primed.Items[0].Name = "changed"
+
+	var listErr error
+	clientset := k8sService.GetClientset().(*fake.Clientset)
+	clientset.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, listErr
+	})
+
+	listErr = &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	ctx, staleness := models.WithStaleness(context.Background())
+	pods, err := k8sService.GetPods(ctx, "shop")
+	if err != nil || len(pods.Items) != 1 {
+		t.Fatalf("GetPods() = %v, %v, want the snapshot", pods, err)
+	}
+	if pods.Items[0].Name != "api-1" {
+		t.Errorf("snapshot pod = %s, want api-1", pods.Items[0].Name)
+	}
+	if oldest, stale := staleness.Oldest(); !stale || !oldest.Equal(fetchedAt) {
+		t.Errorf("Oldest() = %s, %v, want %s", oldest, stale, fetchedAt)
+	}
+
+	// Background reads and reads refused by the cluster keep the error
+	if _, err := k8sService.GetPods(context.Background(), "shop"); err == nil {
+		t.Error("GetPods() served a snapshot without staleness in the context")
+	}
+	if _, err := k8sService.GetPods(ctx, "other"); err == nil {
+		t.Error("GetPods() served a snapshot of another namespace")
+	}
+	listErr = apierrors.NewForbidden(corev1.Resource("pods"), "", nil)
+	if _, err := k8sService.GetPods(ctx, "shop"); !apierrors.IsForbidden(err) {
+		t.Errorf("GetPods() error = %v, want forbidden", err)
+	}
+}
@@ -0,0 +1,373 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+	"k8s-monitor/pkg/utils"
+)
+
+// ErrEventsExpired is returned when a subscriber resumes from an event that is no longer buffered
+var ErrEventsExpired = errors.New("requested events are no longer available")
+
+const (
+	// subscriptionBuffer is the number of events a subscriber may lag behind before it is dropped
+	subscriptionBuffer = 64
+
+	// pollTimeout bounds how much longer than the interval a poll of one cluster may take
+	pollTimeout = 30 * time.Second
+
+	// defaultPollInterval and defaultEventBuffer replace invalid stream configuration
+	defaultPollInterval = 5 * time.Second
+	defaultEventBuffer  = 1000
+)
+
+// ApplicationWatcher periodically observes the applications of every cluster and publishes
+// an event whenever one is added, removed, or changes status, pod counts or version
+type ApplicationWatcher struct {
+	clusters   *ClusterManager
+	interval   time.Duration
+	bufferSize int
+	logger     *logrus.Entry
+
+	mu            sync.Mutex
+	states        map[string]map[string]models.Application // cluster -> namespace/name -> last observation
+	argoStates    map[string][]models.ArgoCDApplication    // cluster -> last observation
+	observedAt    map[string]time.Time                     // cluster -> time of the last successful observation
+	buffer        []models.ApplicationEvent
+	lastID        uint64
+	subscriptions map[*Subscription]struct{}
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Subscription delivers application events to one consumer
+type Subscription struct {
+	Events <-chan models.ApplicationEvent
+
+	events  chan models.ApplicationEvent
+	watcher *ApplicationWatcher
+	closed  bool
+}
+
+// NewApplicationWatcher creates a watcher over every cluster of the registry. A
+// non-positive poll interval or a negative buffer size is replaced by the default.
+func NewApplicationWatcher(clusters *ClusterManager, cfg config.StreamConfig, logger *logrus.Logger) *ApplicationWatcher {
+	interval := time.Duration(cfg.PollInterval) * time.Second
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	bufferSize := cfg.BufferSize
+	if bufferSize < 0 {
+		bufferSize = defaultEventBuffer
+	}
+
+	return &ApplicationWatcher{
+		clusters:      clusters,
+		interval:      interval,
+		bufferSize:    bufferSize,
+		logger:        utils.WithComponent(logger, "application-watcher"),
+		states:        make(map[string]map[string]models.Application),
+		argoStates:    make(map[string][]models.ArgoCDApplication),
+		observedAt:    make(map[string]time.Time),
+		subscriptions: make(map[*Subscription]struct{}),
+		// Seed IDs from the clock so that IDs held by clients stay ordered across restarts
+		lastID: uint64(time.Now().UnixMilli()) * 1000,
+	}
+}
+
+// Start begins observing applications in the background
+func (w *ApplicationWatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		w.Poll(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.Poll(ctx)
+			}
+		}
+	}()
+}
+
+// Stop ends observation and closes every subscription
+func (w *ApplicationWatcher) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	<-w.done
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for sub := range w.subscriptions {
+		w.closeLocked(sub)
+	}
+}
+
+// Poll observes all clusters once and publishes the detected changes
+func (w *ApplicationWatcher) Poll(ctx context.Context) {
+	for _, cluster := range w.clusters.Available() {
+		pollCtx, cancel := context.WithTimeout(ctx, w.interval+pollTimeout)
+		applications, err := cluster.Applications.ObserveApplications(pollCtx)
+		cancel()
+		if err != nil {
+			// Keep the previous observation so an outage is not reported as removals
+			w.logger.WithError(err).WithField("cluster", cluster.Name).Warn("Failed to observe applications")
+			continue
+		}
+		w.observe(cluster.Name, applications)
+
+		pollCtx, cancel = context.WithTimeout(ctx, w.interval+pollTimeout)
+		w.observeArgoCD(pollCtx, cluster)
+		cancel()
+	}
+}
+
+// observeArgoCD records the ArgoCD applications of a cluster. Clusters without ArgoCD
+// simply have none.
+func (w *ApplicationWatcher) observeArgoCD(ctx context.Context, cluster *Cluster) {
+	appList, err := cluster.K8s.GetArgoApplications(ctx, "")
+	if err != nil {
+		w.logger.WithError(err).WithField("cluster", cluster.Name).Debug("Failed to observe ArgoCD applications")
+		return
+	}
+
+	applications := make([]models.ArgoCDApplication, 0, len(appList.Items))
+	for _, item := range appList.Items {
+		if !cluster.K8s.IsNamespaceAllowed(item.GetNamespace()) {
+			continue
+		}
+		app := models.FromArgoApplication(&item)
+		app.Cluster = cluster.Name
+		applications = append(applications, app)
+	}
+	sort.Slice(applications, func(i, j int) bool {
+		return lessNamespaced(applications[i].Namespace, applications[i].Name, applications[j].Namespace, applications[j].Name)
+	})
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.argoStates[cluster.Name] = applications
+}
+
+// Applications returns the last observed applications of every cluster, keyed by cluster
+func (w *ApplicationWatcher) Applications() map[string][]models.Application {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	result := make(map[string][]models.Application, len(w.states))
+	for cluster, state := range w.states {
+		applications := make([]models.Application, 0, len(state))
+		for _, app := range state {
+			applications = append(applications, app)
+		}
+		sort.Slice(applications, func(i, j int) bool {
+			return lessNamespaced(applications[i].Namespace, applications[i].Name, applications[j].Namespace, applications[j].Name)
+		})
+		result[cluster] = applications
+	}
+	return result
+}
+
+// ObservedAt returns when the applications of every cluster were last observed successfully,
+// keyed by cluster. A cluster whose polls fail keeps the time of its last good observation.
+func (w *ApplicationWatcher) ObservedAt() map[string]time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	result := make(map[string]time.Time, len(w.observedAt))
+	for cluster, at := range w.observedAt {
+		result[cluster] = at
+	}
+	return result
+}
+
+// StaleAfter returns how long after its last successful observation the observation of
+// a cluster is out of date: two polls that ran into their timeout
+func (w *ApplicationWatcher) StaleAfter() time.Duration {
+	return 2 * (w.interval + pollTimeout)
+}
+
+// ArgoCDApplications returns the last observed ArgoCD applications of every cluster, keyed by cluster
+func (w *ApplicationWatcher) ArgoCDApplications() map[string][]models.ArgoCDApplication {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	result := make(map[string][]models.ArgoCDApplication, len(w.argoStates))
+	for cluster, applications := range w.argoStates {
+		result[cluster] = applications
+	}
+	return result
+}
+
+// observe diffs the applications of a cluster against the previous observation
+func (w *ApplicationWatcher) observe(cluster string, applications []models.Application) {
+	current := make(map[string]models.Application, len(applications))
+	for _, app := range applications {
+		current[app.Namespace+"/"+app.Name] = app
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	previous, seen := w.states[cluster]
+	w.states[cluster] = current
+	w.observedAt[cluster] = now
+
+	// The first observation of a cluster is the baseline
+	if !seen {
+		return
+	}
+
+	var events []models.ApplicationEvent
+
+	for key, app := range current {
+		app := app
+		state := models.StateOf(&app)
+
+		old, existed := previous[key]
+		if !existed {
+			events = append(events, models.ApplicationEvent{
+				Type:        models.ApplicationAdded,
+				Cluster:     cluster,
+				Namespace:   app.Namespace,
+				Name:        app.Name,
+				Current:     &state,
+				Application: &app,
+				Timestamp:   now,
+			})
+			continue
+		}
+
+		oldState := models.StateOf(&old)
+		if changes := oldState.Diff(state); len(changes) > 0 {
+			events = append(events, models.ApplicationEvent{
+				Type:        models.ApplicationUpdated,
+				Cluster:     cluster,
+				Namespace:   app.Namespace,
+				Name:        app.Name,
+				Changes:     changes,
+				Previous:    &oldState,
+				Current:     &state,
+				Application: &app,
+				Timestamp:   now,
+			})
+		}
+	}
+
+	for key, old := range previous {
+		if _, exists := current[key]; exists {
+			continue
+		}
+		oldState := models.StateOf(&old)
+		events = append(events, models.ApplicationEvent{
+			Type:      models.ApplicationRemoved,
+			Cluster:   cluster,
+			Namespace: old.Namespace,
+			Name:      old.Name,
+			Previous:  &oldState,
+			Timestamp: now,
+		})
+	}
+
+	// Publish in a stable order
+	sort.Slice(events, func(i, j int) bool {
+		return lessNamespaced(events[i].Namespace, events[i].Name, events[j].Namespace, events[j].Name)
+	})
+	for _, event := range events {
+		w.publishLocked(event)
+	}
+}
+
+// publishLocked assigns an ID, buffers the event and fans it out to subscribers
+func (w *ApplicationWatcher) publishLocked(event models.ApplicationEvent) {
+	w.lastID++
+	event.ID = w.lastID
+
+	w.buffer = append(w.buffer, event)
+	if len(w.buffer) > w.bufferSize {
+		w.buffer = w.buffer[len(w.buffer)-w.bufferSize:]
+	}
+
+	for sub := range w.subscriptions {
+		select {
+		case sub.events <- event:
+		default:
+			// Drop subscribers that cannot keep up; they can resume from their last event
+			w.logger.Warn("Dropping slow application event subscriber")
+			w.closeLocked(sub)
+		}
+	}
+}
+
+// Subscribe registers a consumer. When afterID is non-zero, buffered events newer than
+// afterID are returned for replay; ErrEventsExpired is returned if they were evicted.
+func (w *ApplicationWatcher) Subscribe(afterID uint64) (*Subscription, []models.ApplicationEvent, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var replay []models.ApplicationEvent
+	if afterID > 0 && afterID != w.lastID {
+		if afterID > w.lastID || len(w.buffer) == 0 || afterID < w.buffer[0].ID-1 {
+			return nil, nil, ErrEventsExpired
+		}
+		for _, event := range w.buffer {
+			if event.ID > afterID {
+				replay = append(replay, event)
+			}
+		}
+	}
+
+	events := make(chan models.ApplicationEvent, subscriptionBuffer)
+	sub := &Subscription{
+		Events:  events,
+		events:  events,
+		watcher: w,
+	}
+	w.subscriptions[sub] = struct{}{}
+
+	return sub, replay, nil
+}
+
+// LastEventID returns the ID of the most recent event
+func (w *ApplicationWatcher) LastEventID() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lastID
+}
+
+// Close unsubscribes and closes the event channel
+func (s *Subscription) Close() {
+	s.watcher.mu.Lock()
+	defer s.watcher.mu.Unlock()
+	s.watcher.closeLocked(s)
+}
+
+func (w *ApplicationWatcher) closeLocked(sub *Subscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(w.subscriptions, sub)
+	close(sub.events)
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+)
+
+func newTestWatcher(bufferSize int) *ApplicationWatcher {
+	return NewApplicationWatcher(NewClusterManagerFromClients(nil, newTestLogger()),
+		config.StreamConfig{PollInterval: 1, BufferSize: bufferSize}, newTestLogger())
+}
+
+func testApplication(name, status string, readyPods int, version string) models.Application {
+	return models.Application{
+		Name:      name,
+		Namespace: "shop",
+		Status:    status,
+		Version:   version,
+		Summary:   models.ApplicationSummary{TotalPods: 2, ReadyPods: readyPods, RunningPods: 2},
+	}
+}
+
+func TestApplicationWatcherEvents(t *testing.T) {
+	watcher := newTestWatcher(100)
+	sub, _, err := watcher.Subscribe(0)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	defer sub.Close()
+
+	// Baseline observation publishes nothing
+	watcher.observe("prod", []models.Application{
+		testApplication("api", "healthy", 2, "1.0"),
+		testApplication("web", "healthy", 2, "1.0"),
+		testApplication("worker", "healthy", 2, "1.0"),
+	})
+	if len(sub.Events) != 0 {
+		t.Fatalf("baseline published %d events, want 0", len(sub.Events))
+	}
+	if _, ok := watcher.ObservedAt()["prod"]; !ok {
+		t.Errorf("baseline observation was not timestamped")
+	}
+
+	watcher.observe("prod", []models.Application{
+		testApplication("api", "degraded", 1, "1.0"),
+		testApplication("web", "healthy", 2, "1.1"),
+		testApplication("cache", "healthy", 2, ""),
+	})
+
+	want := []struct {
+		eventType models.ApplicationEventType
+		name      string
+		changes   []string
+	}{
+		{eventType: models.ApplicationUpdated, name: "api", changes: []string{"status", "pods"}},
+		{eventType: models.ApplicationAdded, name: "cache"},
+		{eventType: models.ApplicationUpdated, name: "web", changes: []string{"version"}},
+		{eventType: models.ApplicationRemoved, name: "worker"},
+	}
+	if len(sub.Events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(sub.Events), len(want))
+	}
+
+	var lastID uint64
+	for _, w := range want {
+		event := <-sub.Events
+		if event.Type != w.eventType || event.Name != w.name || event.Cluster != "prod" {
+			t.Errorf("got %s %s@%s, want %s %s@prod", event.Type, event.Name, event.Cluster, w.eventType, w.name)
+		}
+		if !reflect.DeepEqual(event.Changes, w.changes) {
+			t.Errorf("%s changes = %v, want %v", w.name, event.Changes, w.changes)
+		}
+		if event.ID <= lastID {
+			t.Errorf("event IDs must increase, got %d after %d", event.ID, lastID)
+		}
+		lastID = event.ID
+	}
+}
+
+func TestApplicationWatcherResume(t *testing.T) {
+	watcher := newTestWatcher(2)
+	watcher.observe("prod", []models.Application{testApplication("api", "healthy", 2, "1")})
+
+	for _, version := range []string{"2", "3", "4"} {
+		watcher.observe("prod", []models.Application{testApplication("api", "healthy", 2, version)})
+	}
+	lastID := watcher.LastEventID()
+
+	// Only the two most recent events are buffered
+	_, replay, err := watcher.Subscribe(lastID - 2)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if len(replay) != 2 || replay[0].ID != lastID-1 || replay[1].ID != lastID {
+		t.Errorf("unexpected replay %+v", replay)
+	}
+
+	if _, _, err := watcher.Subscribe(lastID - 3); !errors.Is(err, ErrEventsExpired) {
+		t.Errorf("Subscribe(evicted) error = %v, want ErrEventsExpired", err)
+	}
+	if _, _, err := watcher.Subscribe(lastID + 10); !errors.Is(err, ErrEventsExpired) {
+		t.Errorf("Subscribe(future) error = %v, want ErrEventsExpired", err)
+	}
+
+	_, replay, err = watcher.Subscribe(lastID)
+	if err != nil || len(replay) != 0 {
+		t.Errorf("Subscribe(latest) = %d events, %v; want none", len(replay), err)
+	}
+}
+
+func TestNewApplicationWatcherDefaults(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          config.StreamConfig
+		wantInterval time.Duration
+		wantBuffer   int
+	}{
+		{name: "configured", cfg: config.StreamConfig{PollInterval: 10, BufferSize: 50}, wantInterval: 10 * time.Second, wantBuffer: 50},
+		{name: "zero", cfg: config.StreamConfig{}, wantInterval: defaultPollInterval, wantBuffer: 0},
+		{name: "negative", cfg: config.StreamConfig{PollInterval: -1, BufferSize: -1}, wantInterval: defaultPollInterval, wantBuffer: defaultEventBuffer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			watcher := NewApplicationWatcher(NewClusterManagerFromClients(nil, newTestLogger()), tt.cfg, newTestLogger())
+			if watcher.interval != tt.wantInterval || watcher.bufferSize != tt.wantBuffer {
+				t.Errorf("interval = %s, buffer = %d, want %s and %d", watcher.interval, watcher.bufferSize, tt.wantInterval, tt.wantBuffer)
+			}
+
+			// Publishing must not panic whatever the buffer size
+			watcher.mu.Lock()
+			watcher.publishLocked(models.ApplicationEvent{})
+			watcher.mu.Unlock()
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"k8s-monitor/internal/models"
+	"k8s-monitor/pkg/utils"
+)
+
+// workload is a Deployment, StatefulSet or DaemonSet that applications are discovered from
+type workload struct {
+	info        models.WorkloadInfo
+	namespace   string
+	labels      map[string]string
+	annotations map[string]string
+	ownerRefs   []metav1.OwnerReference
+	template    metav1.ObjectMeta // pod template metadata
+	selector    labels.Selector
+	createdAt   time.Time
+	root        models.ObjectReference // the workload itself unless it has owners
+}
+
+// applicationGroup is a discovered application: its workloads, if any, and its pods.
+// Only systems grouped by the part-of label have more than one workload.
+type applicationGroup struct {
+	workloads []*workload
+	pods      []corev1.Pod
+	roots     podRoots // shared by all groups of a discovery
+}
+
+// workloadKey identifies a workload
+type workloadKey struct {
+	namespace string
+	kind      string
+	name      string
+}
+
+// newWorkload wraps a workload object. Workloads without a valid selector select nothing.
+func newWorkload(info models.WorkloadInfo, meta metav1.ObjectMeta, template corev1.PodTemplateSpec, selector *metav1.LabelSelector) workload {
+	parsed, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil || selector == nil {
+		parsed = labels.Nothing()
+	}
+	return workload{
+		info:        info,
+		namespace:   meta.Namespace,
+		labels:      meta.Labels,
+		annotations: meta.Annotations,
+		ownerRefs:   meta.OwnerReferences,
+		template:    template.ObjectMeta,
+		selector:    parsed,
+		createdAt:   meta.CreationTimestamp.Time,
+		root:        models.ObjectReference{Kind: info.Kind, Name: info.Name},
+	}
+}
+
+// owns reports whether a pod is controlled by the workload. Deployment pods are owned
+// through a ReplicaSet named after the Deployment and the pod template hash.
+func (w *workload) owns(pod corev1.Pod) bool {
+	if pod.Namespace != w.namespace {
+		return false
+	}
+	owner := controllerRef(pod.OwnerReferences)
+	if owner == nil {
+		return false
+	}
+
+	switch w.info.Kind {
+	case models.WorkloadKindDeployment:
+		if owner.Kind != "ReplicaSet" {
+			return false
+		}
+		if hash := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; hash != "" {
+			return owner.Name == w.info.Name+"-"+hash
+		}
+		return strings.HasPrefix(owner.Name, w.info.Name+"-") && w.selector.Matches(labels.Set(pod.Labels))
+	default:
+		return owner.Kind == w.info.Kind && owner.Name == w.info.Name
+	}
+}
+
+// ownerWorkload returns the workload that would control a pod, derived from its owner.
+// It is only a candidate until confirmed with owns.
+func ownerWorkload(pod corev1.Pod) (workloadKey, bool) {
+	owner := controllerRef(pod.OwnerReferences)
+	if owner == nil {
+		return workloadKey{}, false
+	}
+
+	switch owner.Kind {
+	case models.WorkloadKindStatefulSet, models.WorkloadKindDaemonSet:
+		return workloadKey{namespace: pod.Namespace, kind: owner.Kind, name: owner.Name}, true
+	case "ReplicaSet":
+		name := ""
+		if hash := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; hash != "" {
+			name = strings.TrimSuffix(owner.Name, "-"+hash)
+		} else if idx := findLastDash(owner.Name); idx > 0 {
+			name = owner.Name[:idx]
+		}
+		return workloadKey{namespace: pod.Namespace, kind: models.WorkloadKindDeployment, name: name}, name != ""
+	}
+	return workloadKey{}, false
+}
+
+// listWorkloads lists the Deployments, StatefulSets and DaemonSets of a namespace, or of
+// all namespaces when namespace is empty
+func (a *ApplicationService) listWorkloads(ctx context.Context, namespace string) ([]workload, error) {
+	deployments, err := a.k8sService.GetDeployments(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	statefulSets, err := a.k8sService.GetStatefulSets(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	daemonSets, err := a.k8sService.GetDaemonSets(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	workloads := make([]workload, 0, len(deployments.Items)+len(statefulSets.Items)+len(daemonSets.Items))
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		workloads = append(workloads, newWorkload(models.FromK8sDeployment(d), d.ObjectMeta, d.Spec.Template, d.Spec.Selector))
+	}
+	for i := range statefulSets.Items {
+		s := &statefulSets.Items[i]
+		workloads = append(workloads, newWorkload(models.FromK8sStatefulSet(s), s.ObjectMeta, s.Spec.Template, s.Spec.Selector))
+	}
+	for i := range daemonSets.Items {
+		d := &daemonSets.Items[i]
+		workloads = append(workloads, newWorkload(models.FromK8sDaemonSet(d), d.ObjectMeta, d.Spec.Template, d.Spec.Selector))
+	}
+	return workloads, nil
+}
+
+// discoverApplications lists the pods and workloads of a namespace, or of all namespaces
+// when namespace is empty, and groups them into applications. When workloads cannot be
+// listed, applications are discovered from pods alone.
+func (a *ApplicationService) discoverApplications(ctx context.Context, namespace string) (map[applicationKey]*applicationGroup, error) {
+	var podList *corev1.PodList
+	var err error
+	if namespace == "" {
+		podList, err = a.k8sService.GetAllPods(ctx)
+	} else {
+		podList, err = a.k8sService.GetPods(ctx, namespace)
+	}
+	if err != nil {
+		if namespace == "" {
+			return nil, fmt.Errorf("failed to get pods: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get pods from namespace %s: %w", namespace, err)
+	}
+
+	workloads, err := a.listWorkloads(ctx, namespace)
+	if err != nil {
+		utils.WithComponent(a.logger, "application-service").WithField("namespace", namespace).
+			WithError(err).Warn("Failed to list workloads, discovering applications from pods only")
+	}
+
+	resolver := newOwnerResolver(a.k8sService, utils.WithComponent(a.logger, "application-service"))
+	return a.groupApplications(ctx, podList.Items, workloads, resolver), nil
+}
+
+// groupApplications builds one application per workload, named after the workload, with
+// the pods it controls. Pods without a known workload are grouped by the configured keys,
+// joining the workload application of the same name if there is one. Namespaces grouped
+// by systems merge everything that is part of the same system. The resolver finds the
+// root owners of workloads and pods; when nil, direct owners are taken as roots.
+func (a *ApplicationService) groupApplications(ctx context.Context, pods []corev1.Pod, workloads []workload, resolver *ownerResolver) map[applicationKey]*applicationGroup {
+	groups := make(map[applicationKey]*applicationGroup, len(workloads))
+	owners := make(map[workloadKey]*applicationGroup, len(workloads))
+	roots := make(podRoots, len(pods))
+
+	group := func(key applicationKey) *applicationGroup {
+		g, exists := groups[key]
+		if !exists {
+			g = &applicationGroup{roots: roots}
+			groups[key] = g
+		}
+		return g
+	}
+
+	for i := range workloads {
+		w := &workloads[i]
+		if len(w.ownerRefs) > 0 {
+			if root, ok := resolver.root(ctx, w.namespace, w.ownerRefs); ok {
+				w.root = root
+			}
+		}
+
+		name := a.grouper.System(w.namespace, w.labels)
+		if name == "" {
+			name = a.grouper.System(w.namespace, w.template.Labels)
+		}
+		if name == "" {
+			name = w.info.Name
+		}
+
+		g := group(applicationKey{namespace: w.namespace, name: name})
+		g.workloads = append(g.workloads, w)
+		owners[workloadKey{namespace: w.namespace, kind: w.info.Kind, name: w.info.Name}] = g
+	}
+
+	var unowned []corev1.Pod
+	for _, pod := range pods {
+		ref := podRef{namespace: pod.Namespace, name: pod.Name}
+		if key, ok := ownerWorkload(pod); ok {
+			if g, exists := owners[key]; exists {
+				if w := g.owner(key, pod); w != nil {
+					g.pods = append(g.pods, pod)
+					roots[ref] = w.root
+					continue
+				}
+			}
+		}
+		if root, ok := resolver.root(ctx, pod.Namespace, pod.OwnerReferences); ok {
+			roots[ref] = root
+		}
+		unowned = append(unowned, pod)
+	}
+
+	for key, pods := range a.groupPodsByApplication(unowned, roots) {
+		g := group(key)
+		g.pods = append(g.pods, pods...)
+	}
+
+	return groups
+}
+
+// owner returns the given workload of the group if it controls a pod
+func (g *applicationGroup) owner(key workloadKey, pod corev1.Pod) *workload {
+	for _, w := range g.workloads {
+		if w.info.Kind == key.kind && w.info.Name == key.name && w.owns(pod) {
+			return w
+		}
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+
+	"k8s-monitor/internal/models"
+	"k8s-monitor/pkg/utils"
+)
+
+// Buckets of the bolt database
+var (
+	metaBucket      = []byte("meta")
+	historyBucket   = []byte("history")   // application key -> timestamp -> sample
+	incidentsBucket = []byte("incidents") // id -> incident
+	settingsBucket  = []byte("settings")  // key -> JSON value
+
+	schemaVersionKey = []byte("schema_version")
+)
+
+// BoltStore persists data in an embedded bbolt file
+type BoltStore struct {
+	db     *bolt.DB
+	logger *logrus.Entry
+}
+
+var _ Store = (*BoltStore)(nil)
+
+// OpenBolt opens or creates the database file and applies pending schema migrations
+func OpenBolt(path string, logger *logrus.Logger) (*BoltStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("storage path is required for the bolt driver")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open storage file %s: %w", path, err)
+	}
+
+	s := &BoltStore{
+		db:     db,
+		logger: utils.WithComponent(logger, "storage").WithField("path", path),
+	}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// AppendStatus implements Store
+func (s *BoltStore) AppendStatus(_ context.Context, samples []models.StatusSample) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		history := tx.Bucket(historyBucket)
+		for _, sample := range samples {
+			series, err := history.CreateBucketIfNotExists([]byte(sampleKey(sample).String()))
+			if err != nil {
+				return err
+			}
+			value, err := json.Marshal(sample)
+			if err != nil {
+				return err
+			}
+			if err := series.Put(timeKey(sample.Timestamp), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// StatusHistory implements Store
+func (s *BoltStore) StatusHistory(_ context.Context, key ApplicationKey, from, to time.Time) ([]models.StatusSample, error) {
+	samples := []models.StatusSample{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		series := tx.Bucket(historyBucket).Bucket([]byte(key.String()))
+		if series == nil {
+			return nil
+		}
+
+		end := timeKey(to)
+		cursor := series.Cursor()
+		for k, v := cursor.Seek(timeKey(from)); k != nil && bytes.Compare(k, end) < 0; k, v = cursor.Next() {
+			var sample models.StatusSample
+			if err := json.Unmarshal(v, &sample); err != nil {
+				return err
+			}
+			samples = append(samples, sample)
+		}
+		return nil
+	})
+	return samples, err
+}
+
+// SaveIncident implements Store
+func (s *BoltStore) SaveIncident(_ context.Context, incident models.Incident) error {
+	value, err := json.Marshal(incident)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(incidentsBucket).Put([]byte(incident.ID), value)
+	})
+}
+
+// GetIncident implements Store
+func (s *BoltStore) GetIncident(_ context.Context, id string) (models.Incident, error) {
+	var incident models.Incident
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(incidentsBucket).Get([]byte(id))
+		if value == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(value, &incident)
+	})
+	return incident, err
+}
+
+// ListIncidents implements Store
+func (s *BoltStore) ListIncidents(_ context.Context, filter IncidentFilter) ([]models.Incident, error) {
+	incidents := []models.Incident{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(incidentsBucket).ForEach(func(_, value []byte) error {
+			var incident models.Incident
+			if err := json.Unmarshal(value, &incident); err != nil {
+				return err
+			}
+			if filter.Matches(&incident) {
+				incidents = append(incidents, incident)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortIncidents(incidents)
+	return incidents, nil
+}
+
+// GetSetting implements Store
+func (s *BoltStore) GetSetting(_ context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		stored := tx.Bucket(settingsBucket).Get([]byte(key))
+		if stored == nil {
+			return ErrNotFound
+		}
+		// Values are only valid during the transaction
+		value = append([]byte(nil), stored...)
+		return nil
+	})
+	return value, err
+}
+
+// PutSetting implements Store
+func (s *BoltStore) PutSetting(_ context.Context, key string, value []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(settingsBucket).Put([]byte(key), value)
+	})
+}
+
+// DeleteSetting implements Store
+func (s *BoltStore) DeleteSetting(_ context.Context, key string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(settingsBucket).Delete([]byte(key))
+	})
+}
+
+// Settings implements Store
+func (s *BoltStore) Settings(_ context.Context) (map[string][]byte, error) {
+	settings := make(map[string][]byte)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(settingsBucket).ForEach(func(key, value []byte) error {
+			settings[string(key)] = append([]byte(nil), value...)
+			return nil
+		})
+	})
+	return settings, err
+}
+
+// Prune implements Store
+func (s *BoltStore) Prune(_ context.Context, historyBefore, incidentsBefore time.Time) (PruneResult, error) {
+	var result PruneResult
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		if !historyBefore.IsZero() {
+			history := tx.Bucket(historyBucket)
+			cutoff := timeKey(historyBefore)
+
+			var emptied [][]byte
+			err := history.ForEachBucket(func(name []byte) error {
+				series := history.Bucket(name)
+				cursor := series.Cursor()
+				// Deleting moves the cursor to the next key
+				for k, _ := cursor.First(); k != nil && bytes.Compare(k, cutoff) < 0; k, _ = cursor.First() {
+					if err := cursor.Delete(); err != nil {
+						return err
+					}
+					result.Samples++
+				}
+				if k, _ := cursor.First(); k == nil {
+					emptied = append(emptied, append([]byte(nil), name...))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, name := range emptied {
+				if err := history.DeleteBucket(name); err != nil {
+					return err
+				}
+			}
+		}
+
+		if !incidentsBefore.IsZero() {
+			incidents := tx.Bucket(incidentsBucket)
+
+			var expired [][]byte
+			err := incidents.ForEach(func(id, value []byte) error {
+				var incident models.Incident
+				if err := json.Unmarshal(value, &incident); err != nil {
+					return err
+				}
+				if closedBefore(&incident, incidentsBefore) {
+					expired = append(expired, append([]byte(nil), id...))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, id := range expired {
+				if err := incidents.Delete(id); err != nil {
+					return err
+				}
+			}
+			result.Incidents = len(expired)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Close implements Store
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// timeKey encodes a timestamp so that keys sort chronologically. Times before the epoch,
+// including the zero time, map to the smallest key.
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	if t.Before(time.Unix(0, 0)) {
+		return key
+	}
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	return key
+}
@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s-monitor/internal/models"
+)
+
+// MemoryStore keeps everything in memory; data is lost on restart
+type MemoryStore struct {
+	mu        sync.RWMutex
+	history   map[string][]models.StatusSample // application key -> samples ordered by time
+	incidents map[string]models.Incident
+	settings  map[string][]byte
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		history:   make(map[string][]models.StatusSample),
+		incidents: make(map[string]models.Incident),
+		settings:  make(map[string][]byte),
+	}
+}
+
+// AppendStatus implements Store
+func (s *MemoryStore) AppendStatus(_ context.Context, samples []models.StatusSample) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sample := range samples {
+		key := sampleKey(sample).String()
+		series := s.history[key]
+		i := sort.Search(len(series), func(i int) bool { return !series[i].Timestamp.Before(sample.Timestamp) })
+		if i < len(series) && series[i].Timestamp.Equal(sample.Timestamp) {
+			series[i] = sample
+			continue
+		}
+		series = append(series, models.StatusSample{})
+		copy(series[i+1:], series[i:])
+		series[i] = sample
+		s.history[key] = series
+	}
+	return nil
+}
+
+// StatusHistory implements Store
+func (s *MemoryStore) StatusHistory(_ context.Context, key ApplicationKey, from, to time.Time) ([]models.StatusSample, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	samples := []models.StatusSample{}
+	for _, sample := range s.history[key.String()] {
+		if !sample.Timestamp.Before(from) && sample.Timestamp.Before(to) {
+			samples = append(samples, sample)
+		}
+	}
+	return samples, nil
+}
+
+// SaveIncident implements Store
+func (s *MemoryStore) SaveIncident(_ context.Context, incident models.Incident) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.incidents[incident.ID] = incident
+	return nil
+}
+
+// GetIncident implements Store
+func (s *MemoryStore) GetIncident(_ context.Context, id string) (models.Incident, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	incident, ok := s.incidents[id]
+	if !ok {
+		return models.Incident{}, ErrNotFound
+	}
+	return incident, nil
+}
+
+// ListIncidents implements Store
+func (s *MemoryStore) ListIncidents(_ context.Context, filter IncidentFilter) ([]models.Incident, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	incidents := []models.Incident{}
+	for _, incident := range s.incidents {
+		if filter.Matches(&incident) {
+			incidents = append(incidents, incident)
+		}
+	}
+	sortIncidents(incidents)
+	return incidents, nil
+}
+
+// GetSetting implements Store
+func (s *MemoryStore) GetSetting(_ context.Context, key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, ok := s.settings[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), value...), nil
+}
+
+// PutSetting implements Store
+func (s *MemoryStore) PutSetting(_ context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settings[key] = append([]byte(nil), value...)
+	return nil
+}
+
+// DeleteSetting implements Store
+func (s *MemoryStore) DeleteSetting(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.settings, key)
+	return nil
+}
+
+// Settings implements Store
+func (s *MemoryStore) Settings(_ context.Context) (map[string][]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	settings := make(map[string][]byte, len(s.settings))
+	for key, value := range s.settings {
+		settings[key] = append([]byte(nil), value...)
+	}
+	return settings, nil
+}
+
+// Prune implements Store
+func (s *MemoryStore) Prune(_ context.Context, historyBefore, incidentsBefore time.Time) (PruneResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result PruneResult
+	if !historyBefore.IsZero() {
+		for key, series := range s.history {
+			i := sort.Search(len(series), func(i int) bool { return !series[i].Timestamp.Before(historyBefore) })
+			result.Samples += i
+			if i == len(series) {
+				delete(s.history, key)
+			} else {
+				s.history[key] = series[i:]
+			}
+		}
+	}
+	if !incidentsBefore.IsZero() {
+		for id, incident := range s.incidents {
+			if closedBefore(&incident, incidentsBefore) {
+				delete(s.incidents, id)
+				result.Incidents++
+			}
+		}
+	}
+	return result, nil
+}
+
+// Close implements Store
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+func sampleKey(sample models.StatusSample) ApplicationKey {
+	return ApplicationKey{Cluster: sample.Cluster, Namespace: sample.Namespace, Name: sample.Name}
+}
+
+// closedBefore reports whether an incident was closed before the cutoff; open incidents are kept
+func closedBefore(incident *models.Incident, cutoff time.Time) bool {
+	return incident.Status == models.IncidentClosed && incident.EndedAt != nil && incident.EndedAt.Before(cutoff)
+}
+
+func sortIncidents(incidents []models.Incident) {
+	sort.Slice(incidents, func(i, j int) bool {
+		if !incidents[i].StartedAt.Equal(incidents[j].StartedAt) {
+			return incidents[i].StartedAt.After(incidents[j].StartedAt)
+		}
+		return incidents[i].ID < incidents[j].ID
+	})
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// migration upgrades the bolt schema by one version
+type migration struct {
+	description string
+	apply       func(tx *bolt.Tx) error
+}
+
+// migrations are applied in order; migration i upgrades the schema to version i+1.
+// Never edit or reorder released migrations, append new ones instead.
+var migrations = []migration{
+	{
+		description: "create history, incidents and settings buckets",
+		apply: func(tx *bolt.Tx) error {
+			for _, name := range [][]byte{historyBucket, incidentsBucket, settingsBucket} {
+				if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+}
+
+// schemaVersion is the schema version written by this build
+var schemaVersion = len(migrations)
+
+// migrate applies pending migrations, each in its own transaction
+func (s *BoltStore) migrate() error {
+	var current int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+		if value := meta.Get(schemaVersionKey); value != nil {
+			current = int(binary.BigEndian.Uint64(value))
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	if current > schemaVersion {
+		return fmt.Errorf("storage schema version %d is newer than the supported version %d", current, schemaVersion)
+	}
+
+	for version := current; version < schemaVersion; version++ {
+		m := migrations[version]
+		err := s.db.Update(func(tx *bolt.Tx) error {
+			if err := m.apply(tx); err != nil {
+				return err
+			}
+			value := make([]byte, 8)
+			binary.BigEndian.PutUint64(value, uint64(version+1))
+			return tx.Bucket(metaBucket).Put(schemaVersionKey, value)
+		})
+		if err != nil {
+			return fmt.Errorf("storage migration %d (%s) failed: %w", version+1, m.description, err)
+		}
+		s.logger.WithField("version", version+1).Infof("Applied storage migration: %s", m.description)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/pkg/utils"
+)
+
+const day = 24 * time.Hour
+
+// Retention periodically deletes records that are older than the retention policy
+type Retention struct {
+	store     Store
+	history   time.Duration
+	incidents time.Duration
+	interval  time.Duration
+	logger    *logrus.Entry
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewRetention creates a retention runner for the store
+func NewRetention(store Store, cfg config.StorageRetentionConfig, logger *logrus.Logger) *Retention {
+	r := &Retention{
+		store:     store,
+		history:   time.Duration(cfg.History) * day,
+		incidents: time.Duration(cfg.Incidents) * day,
+		interval:  time.Duration(cfg.Interval) * time.Second,
+		logger:    utils.WithComponent(logger, "storage-retention"),
+	}
+	if r.interval <= 0 {
+		r.interval = time.Hour
+	}
+	return r
+}
+
+// Start prunes once and then on every interval in the background
+func (r *Retention) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		r.Run(ctx, time.Now())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				r.Run(ctx, now)
+			}
+		}
+	}()
+}
+
+// Stop ends the background runs
+func (r *Retention) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	<-r.done
+}
+
+// Run applies the retention policy once
+func (r *Retention) Run(ctx context.Context, now time.Time) {
+	var historyBefore, incidentsBefore time.Time
+	if r.history > 0 {
+		historyBefore = now.Add(-r.history)
+	}
+	if r.incidents > 0 {
+		incidentsBefore = now.Add(-r.incidents)
+	}
+	if historyBefore.IsZero() && incidentsBefore.IsZero() {
+		return
+	}
+
+	result, err := r.store.Prune(ctx, historyBefore, incidentsBefore)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to apply storage retention")
+		return
+	}
+	if result.Samples > 0 || result.Incidents > 0 {
+		r.logger.WithFields(logrus.Fields{
+			"samples":   result.Samples,
+			"incidents": result.Incidents,
+		}).Info("Pruned expired records")
+	}
+}
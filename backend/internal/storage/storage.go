@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"k8s-monitor/internal/config"
+	"k8s-monitor/internal/models"
+)
+
+// Storage drivers
+const (
+	DriverBolt   = "bolt"
+	DriverMemory = "memory"
+)
+
+// ErrNotFound is returned when a stored record does not exist
+var ErrNotFound = errors.New("record not found")
+
+// IncidentFilter selects incidents. Empty fields match everything.
+type IncidentFilter struct {
+	Cluster     string
+	Namespace   string
+	Application string
+	Status      models.IncidentStatus
+}
+
+// Matches reports whether an incident is selected by the filter
+func (f IncidentFilter) Matches(incident *models.Incident) bool {
+	return (f.Cluster == "" || incident.Cluster == f.Cluster) &&
+		(f.Namespace == "" || incident.Namespace == f.Namespace) &&
+		(f.Application == "" || incident.Application == f.Application) &&
+		(f.Status == "" || incident.Status == f.Status)
+}
+
+// ApplicationKey identifies an application across clusters
+type ApplicationKey struct {
+	Cluster   string
+	Namespace string
+	Name      string
+}
+
+func (k ApplicationKey) String() string {
+	return k.Cluster + "/" + k.Namespace + "/" + k.Name
+}
+
+// Store persists status history, incidents and settings
+type Store interface {
+	// AppendStatus records status samples
+	AppendStatus(ctx context.Context, samples []models.StatusSample) error
+	// StatusHistory returns the samples of an application within [from, to), oldest first
+	StatusHistory(ctx context.Context, key ApplicationKey, from, to time.Time) ([]models.StatusSample, error)
+
+	// SaveIncident creates or replaces an incident
+	SaveIncident(ctx context.Context, incident models.Incident) error
+	// GetIncident returns one incident or ErrNotFound
+	GetIncident(ctx context.Context, id string) (models.Incident, error)
+	// ListIncidents returns the incidents matching the filter, most recently started first
+	ListIncidents(ctx context.Context, filter IncidentFilter) ([]models.Incident, error)
+
+	// GetSetting returns the JSON value of a setting or ErrNotFound
+	GetSetting(ctx context.Context, key string) ([]byte, error)
+	// PutSetting stores the JSON value of a setting
+	PutSetting(ctx context.Context, key string, value []byte) error
+	// DeleteSetting removes a setting, deleting a missing setting is not an error
+	DeleteSetting(ctx context.Context, key string) error
+	// Settings returns every stored setting
+	Settings(ctx context.Context) (map[string][]byte, error)
+
+	// Prune deletes status samples older than historyBefore and incidents closed before
+	// incidentsBefore. A zero time keeps everything of that kind.
+	Prune(ctx context.Context, historyBefore, incidentsBefore time.Time) (PruneResult, error)
+
+	Close() error
+}
+
+// PruneResult counts the records deleted by a retention run
+type PruneResult struct {
+	Samples   int
+	Incidents int
+}
+
+// Open creates the store selected by the configuration
+func Open(cfg config.StorageConfig, logger *logrus.Logger) (Store, error) {
+	switch cfg.Driver {
+	case DriverBolt, "":
+		return OpenBolt(cfg.Path, logger)
+	case DriverMemory:
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
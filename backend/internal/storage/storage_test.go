@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+
+	"k8s-monitor/internal/models"
+)
+
+func newTestLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// forEachDriver runs a test against every store implementation
+func forEachDriver(t *testing.T, test func(t *testing.T, store Store)) {
+	t.Run(DriverMemory, func(t *testing.T) {
+		test(t, NewMemoryStore())
+	})
+	t.Run(DriverBolt, func(t *testing.T) {
+		store, err := OpenBolt(filepath.Join(t.TempDir(), "data", "test.db"), newTestLogger())
+		if err != nil {
+			t.Fatalf("OpenBolt() error = %v", err)
+		}
+		defer store.Close()
+		test(t, store)
+	})
+}
+
+func TestStoreStatusHistory(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	sample := func(name string, offset time.Duration, status string) models.StatusSample {
+		return models.StatusSample{Cluster: "prod", Namespace: "shop", Name: name, Timestamp: start.Add(offset), Status: status}
+	}
+
+	forEachDriver(t, func(t *testing.T, store Store) {
+		ctx := context.Background()
+		err := store.AppendStatus(ctx, []models.StatusSample{
+			sample("api", 2*time.Minute, "degraded"),
+			sample("api", 0, "healthy"),
+			sample("api", time.Minute, "healthy"),
+			sample("web", time.Minute, "healthy"),
+		})
+		if err != nil {
+			t.Fatalf("AppendStatus() error = %v", err)
+		}
+
+		key := ApplicationKey{Cluster: "prod", Namespace: "shop", Name: "api"}
+		samples, err := store.StatusHistory(ctx, key, start, start.Add(2*time.Minute))
+		if err != nil {
+			t.Fatalf("StatusHistory() error = %v", err)
+		}
+		if len(samples) != 2 || !samples[0].Timestamp.Equal(start) || !samples[1].Timestamp.Equal(start.Add(time.Minute)) {
+			t.Errorf("unexpected samples %+v", samples)
+		}
+
+		result, err := store.Prune(ctx, start.Add(90*time.Second), time.Time{})
+		if err != nil {
+			t.Fatalf("Prune() error = %v", err)
+		}
+		if result.Samples != 3 {
+			t.Errorf("pruned %d samples, want 3", result.Samples)
+		}
+		samples, _ = store.StatusHistory(ctx, key, time.Time{}, start.Add(time.Hour))
+		if len(samples) != 1 || samples[0].Status != "degraded" {
+			t.Errorf("unexpected samples after pruning %+v", samples)
+		}
+	})
+}
+
+func TestStoreIncidents(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ended := start.Add(time.Hour)
+
+	forEachDriver(t, func(t *testing.T, store Store) {
+		ctx := context.Background()
+		incidents := []models.Incident{
+			{ID: "a", Namespace: "shop", Application: "api", Status: models.IncidentClosed, StartedAt: start, EndedAt: &ended},
+			{ID: "b", Namespace: "shop", Application: "web", Status: models.IncidentOpen, StartedAt: start.Add(time.Minute)},
+			{ID: "c", Namespace: "ops", Application: "api", Status: models.IncidentOpen, StartedAt: start.Add(2 * time.Minute)},
+		}
+		for _, incident := range incidents {
+			if err := store.SaveIncident(ctx, incident); err != nil {
+				t.Fatalf("SaveIncident() error = %v", err)
+			}
+		}
+
+		list, err := store.ListIncidents(ctx, IncidentFilter{Namespace: "shop"})
+		if err != nil {
+			t.Fatalf("ListIncidents() error = %v", err)
+		}
+		if len(list) != 2 || list[0].ID != "b" || list[1].ID != "a" {
+			t.Errorf("unexpected incidents %+v", list)
+		}
+		list, _ = store.ListIncidents(ctx, IncidentFilter{Application: "api", Status: models.IncidentOpen})
+		if len(list) != 1 || list[0].ID != "c" {
+			t.Errorf("unexpected incidents %+v", list)
+		}
+
+		if _, err := store.GetIncident(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetIncident() error = %v, want ErrNotFound", err)
+		}
+
+		// Open incidents are kept regardless of age
+		result, err := store.Prune(ctx, time.Time{}, ended.Add(time.Second))
+		if err != nil {
+			t.Fatalf("Prune() error = %v", err)
+		}
+		if result.Incidents != 1 {
+			t.Errorf("pruned %d incidents, want 1", result.Incidents)
+		}
+		list, _ = store.ListIncidents(ctx, IncidentFilter{})
+		if len(list) != 2 {
+			t.Errorf("got %d incidents after pruning, want 2", len(list))
+		}
+	})
+}
+
+func TestStoreSettings(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, store Store) {
+		ctx := context.Background()
+		if _, err := store.GetSetting(ctx, "theme"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetSetting() error = %v, want ErrNotFound", err)
+		}
+
+		if err := store.PutSetting(ctx, "theme", []byte(`"dark"`)); err != nil {
+			t.Fatalf("PutSetting() error = %v", err)
+		}
+		value, err := store.GetSetting(ctx, "theme")
+		if err != nil || string(value) != `"dark"` {
+			t.Errorf("GetSetting() = %s, %v", value, err)
+		}
+
+		settings, err := store.Settings(ctx)
+		if err != nil || len(settings) != 1 {
+			t.Errorf("Settings() = %v, %v", settings, err)
+		}
+
+		if err := store.DeleteSetting(ctx, "theme"); err != nil {
+			t.Fatalf("DeleteSetting() error = %v", err)
+		}
+		if err := store.DeleteSetting(ctx, "theme"); err != nil {
+			t.Errorf("deleting a missing setting failed: %v", err)
+		}
+	})
+}
+
+func TestBoltMigrations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	store, err := OpenBolt(path, newTestLogger())
+	if err != nil {
+		t.Fatalf("OpenBolt() error = %v", err)
+	}
+	if err := store.PutSetting(context.Background(), "theme", []byte(`"dark"`)); err != nil {
+		t.Fatalf("PutSetting() error = %v", err)
+	}
+	store.Close()
+
+	// Reopening an up to date database keeps its data
+	store, err = OpenBolt(path, newTestLogger())
+	if err != nil {
+		t.Fatalf("reopening error = %v", err)
+	}
+	if _, err := store.GetSetting(context.Background(), "theme"); err != nil {
+		t.Errorf("setting lost after reopening: %v", err)
+	}
+	store.Close()
+
+	// A database written by a newer build is refused
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		t.Fatalf("bolt.Open() error = %v", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		value := make([]byte, 8)
+		binary.BigEndian.PutUint64(value, uint64(schemaVersion+1))
+		return tx.Bucket(metaBucket).Put(schemaVersionKey, value)
+	})
+	db.Close()
+	if err != nil {
+		t.Fatalf("writing the schema version failed: %v", err)
+	}
+	if store, err := OpenBolt(path, newTestLogger()); err == nil {
+		store.Close()
+		t.Errorf("opening a newer schema succeeded")
+	}
+}
@@ -60,6 +60,11 @@ func WithComponent(logger *logrus.Logger, component string) *logrus.Entry {
 	return logger.WithField("component", component)
 }
 
+// WithCluster creates a logger with cluster field
+func WithCluster(logger *logrus.Logger, cluster string) *logrus.Entry {
+	return logger.WithField("cluster", cluster)
+}
+
 // WithNamespace creates a logger with namespace field
 func WithNamespace(logger *logrus.Logger, namespace string) *logrus.Entry {
 	return logger.WithField("namespace", namespace)